@@ -0,0 +1,181 @@
+package aloig
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SampledOutField holds the number of entries dropped by the adaptive
+// sampling in the summary written at the end of the window
+const SampledOutField = "sampled_out"
+
+// DefaultAdaptiveSamplingWindow is the window of the adaptive sampling when
+// none is configured
+const DefaultAdaptiveSamplingWindow = time.Second
+
+// maxSampleEvery is the tightest adaptive sampling: one entry written in
+// maxSampleEvery
+const maxSampleEvery = 1024
+
+// adaptiveState is the sampling of the entries with a level and message
+type adaptiveState struct {
+	// entry is the first entry of the window, copied for the summary
+	entry *logrus.Entry
+
+	// count and dropped are the entries of the current window
+	count   int
+	dropped int
+
+	// every is the fraction of the entries beyond the threshold written
+	every int
+}
+
+// adaptiveSampler writes the first threshold entries with the same level and
+// message in each window, then one in every N. N starts at 2 and doubles
+// after each window exceeding the threshold, so sampling tightens while the
+// volume stays high, and halves after the quieter windows. At the end of
+// each window, a summary of the dropped entries ("... (sampled: 9500 of
+// 10000 dropped in 1s)") is written with the fields of the first one. Errors
+// and more severe entries are always written, and hooks still see every
+// entry.
+type adaptiveSampler struct {
+	logrus.Formatter
+
+	logger    *logrus.Logger
+	threshold int
+	window    time.Duration
+
+	mu      sync.Mutex
+	entries map[uint64]*adaptiveState
+	timer   *time.Timer
+}
+
+// newAdaptiveSampler returns a formatter sampling the entries of logger
+// formatted with formatter beyond threshold entries per window
+func newAdaptiveSampler(formatter logrus.Formatter, logger *logrus.Logger, threshold int, window time.Duration) *adaptiveSampler {
+	if window <= 0 {
+		window = DefaultAdaptiveSamplingWindow
+	}
+	return &adaptiveSampler{
+		Formatter: formatter,
+		logger:    logger,
+		threshold: threshold,
+		window:    window,
+		entries:   make(map[uint64]*adaptiveState),
+	}
+}
+
+// Format formats the entry unless it is sampled out
+func (f *adaptiveSampler) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level <= logrus.ErrorLevel {
+		return f.Formatter.Format(entry)
+	}
+	key := samplingKey(entry)
+
+	f.mu.Lock()
+	state, ok := f.entries[key]
+	if !ok {
+		if len(f.entries) >= maxDedupEntries {
+			f.mu.Unlock()
+			return f.Formatter.Format(entry)
+		}
+		state = &adaptiveState{every: 2}
+		f.entries[key] = state
+		if f.timer == nil {
+			f.timer = time.AfterFunc(f.window, f.roll)
+		}
+	}
+	if state.count == 0 {
+		state.entry = copyEntry(entry)
+	}
+	state.count++
+	beyond := state.count - f.threshold
+	if beyond > 0 && beyond%state.every != 0 {
+		state.dropped++
+		f.mu.Unlock()
+		atomic.AddUint64(&stats.sampled, 1)
+		return nil, nil
+	}
+	f.mu.Unlock()
+
+	return f.Formatter.Format(entry)
+}
+
+// roll ends the current window: it writes the summaries, adjusts the
+// sampling of each entry and forgets the entries back to normal
+func (f *adaptiveSampler) roll() {
+	summaries := f.endWindow()
+	for _, state := range summaries {
+		f.writeSummary(state)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.entries) > 0 {
+		f.timer = time.AfterFunc(f.window, f.roll)
+	} else {
+		f.timer = nil
+	}
+}
+
+// endWindow resets the counts of the window and returns the copies of the
+// states with dropped entries
+func (f *adaptiveSampler) endWindow() []adaptiveState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var summaries []adaptiveState
+	for key, state := range f.entries {
+		if state.dropped > 0 {
+			summaries = append(summaries, *state)
+		}
+		switch {
+		case state.count > f.threshold:
+			if state.every < maxSampleEvery {
+				state.every *= 2
+			}
+		case state.every > 1:
+			state.every /= 2
+		default:
+			delete(f.entries, key)
+		}
+		state.entry, state.count, state.dropped = nil, 0, 0
+	}
+	return summaries
+}
+
+// Flush writes the summaries of the current window, e.g. before exiting
+func (f *adaptiveSampler) Flush() {
+	for _, state := range f.endWindow() {
+		f.writeSummary(state)
+	}
+}
+
+// writeSummary writes the summary of the entries dropped during a window.
+// It bypasses the hooks, which already saw every entry.
+func (f *adaptiveSampler) writeSummary(state adaptiveState) {
+	summary := state.entry
+	summary.Data[SampledOutField] = state.dropped
+	summary.Message = fmt.Sprintf("%s (sampled: %d of %d dropped in %s)", summary.Message, state.dropped, state.count, f.window)
+	summary.Time = time.Now()
+
+	serialized, err := f.Formatter.Format(summary)
+	if err != nil || len(serialized) == 0 {
+		return
+	}
+	_, _ = f.logger.Out.Write(serialized)
+}
+
+// samplingKey returns the hash of the level and message of the entry
+func samplingKey(entry *logrus.Entry) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(entry.Level.String()))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(entry.Message))
+	return h.Sum64()
+}
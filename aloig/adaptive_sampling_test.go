@@ -0,0 +1,82 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestAdaptiveSampler tests that the entries beyond the threshold are
+// sampled more and more while the volume stays high, and summarized
+func TestAdaptiveSampler(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	sampler := newAdaptiveSampler(logger.logger.Formatter, logger.logger, 10, time.Hour)
+	logger.logger.SetFormatter(sampler)
+
+	for i := 0; i < 30; i++ {
+		logger.WithField("queue", "orders").Info("message consumed")
+		logger.Error("consume failed")
+	}
+	logger.Info("single")
+
+	output := buf.String()
+	if count := strings.Count(output, `msg="message consumed"`); count != 20 {
+		t.Errorf("Expected 10 entries then one in 2, got %d", count)
+	}
+	if count := strings.Count(output, "consume failed"); count != 30 {
+		t.Errorf("Expected every error, got %d", count)
+	}
+	if !strings.Contains(output, "single") {
+		t.Error("Expected the other entries to be written")
+	}
+
+	buf.Reset()
+	sampler.Flush()
+	output = buf.String()
+	if strings.Count(output, "\n") != 1 || !strings.Contains(output, `msg="message consumed (sampled: 10 of 30 dropped in 1h0m0s)"`) ||
+		!strings.Contains(output, "queue=orders") || !strings.Contains(output, SampledOutField+"=10") {
+		t.Errorf("Expected a single summary of the sampled entries, got: %s", output)
+	}
+
+	// The sampling tightens after a window over the threshold
+	buf.Reset()
+	for i := 0; i < 30; i++ {
+		logger.Info("message consumed")
+	}
+	if count := strings.Count(buf.String(), `msg="message consumed"`); count != 15 {
+		t.Errorf("Expected 10 entries then one in 4, got %d", count)
+	}
+
+	// and relaxes after quiet windows, from one in 8 after this window
+	for i := 0; i < 5; i++ {
+		sampler.Flush()
+	}
+	sampler.mu.Lock()
+	remaining := len(sampler.entries)
+	sampler.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("Expected the entries to be forgotten after quiet windows, got %d", remaining)
+	}
+}
+
+// TestNewLoggerAdaptiveSampling tests that the summary is written when the
+// window ends
+func TestNewLoggerAdaptiveSampling(t *testing.T) {
+	logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel, AdaptiveSamplingThreshold: 1, AdaptiveSamplingWindow: 20 * time.Millisecond})
+	buf := &lockedBuffer{}
+	logger.(*logrusLogger).logger.SetOutput(buf)
+
+	for i := 0; i < 5; i++ {
+		logger.Warn("cache miss")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), `"`+SampledOutField+`":2`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a summary, got: %s", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
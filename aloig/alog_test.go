@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"os"
-	"sync"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -66,6 +66,34 @@ func TestNewLogger(t *testing.T) {
 	}
 }
 
+// TestNewLoggerCustomFields tests that the custom fields are added in every
+// environment and the standard fields outside of dev
+func TestNewLoggerCustomFields(t *testing.T) {
+	for _, environment := range []string{"dev", "prod"} {
+		t.Run(environment, func(t *testing.T) {
+			logger := NewLogger(Config{
+				Environment:  environment,
+				AppName:      "test-app",
+				Level:        logrus.InfoLevel,
+				CustomFields: map[string]interface{}{"team": "payments"},
+			}).(*logrusLogger)
+			var buf bytes.Buffer
+			logger.logger.SetOutput(&buf)
+			logger.logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+			logger.Info("with fields")
+
+			output := buf.String()
+			if !strings.Contains(output, "team=payments") {
+				t.Errorf("Expected the custom field, got: %s", output)
+			}
+			if standard := strings.Contains(output, "appname=test-app"); standard != (environment != "dev") {
+				t.Errorf("Expected the standard fields outside of dev only, got: %s", output)
+			}
+		})
+	}
+}
+
 // TestAloigFunctionsWork tests that aloig public functions work without errors
 func TestAloigFunctionsWork(t *testing.T) {
 	// Test basic functions - only verify they don't panic
@@ -119,9 +147,8 @@ func TestAloigChainingWork(t *testing.T) {
 
 // TestSingletonLogger tests singleton behavior
 func TestSingletonLogger(t *testing.T) {
-	// Reset singleton for test
-	log = nil
-	once = sync.Once{}
+	ResetForTesting()
+	defer ResetForTesting()
 
 	logger1 := GetLogger()
 	logger2 := GetLogger()
@@ -6,16 +6,18 @@ package aloig
 
 import (
 	"context"
-	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/getsentry/sentry-go"
-	sentrylogrus "github.com/getsentry/sentry-go/logrus"
 	"github.com/sirupsen/logrus"
 )
 
@@ -36,16 +38,60 @@ type Logger interface {
 	Fatalf(format string, args ...interface{})
 	Panic(args ...interface{})
 	Panicf(format string, args ...interface{})
+
+	// DPanic logs at panic level and panics in development, and logs at
+	// error level otherwise, so programming bugs are loud locally without
+	// crashing production
+	DPanic(args ...interface{})
+	DPanicf(format string, args ...interface{})
+
 	Print(args ...interface{})
 	Printf(format string, args ...interface{})
 	Println(args ...interface{})
 	Trace(args ...interface{})
 	Tracef(format string, args ...interface{})
+
+	// IsLevelEnabled checks if the entries logged at level are written, so
+	// expensive arguments are only built when they're needed
+	IsLevelEnabled(level logrus.Level) bool
+
 	WithField(key string, value interface{}) Logger
 	WithFields(fields map[string]interface{}) Logger
 	WithError(err error) Logger
 	WithContext(ctx context.Context) Logger
 
+	// WithFingerprint sets the parts used by Sentry to group the events
+	// reported by the returned logger
+	WithFingerprint(parts ...string) Logger
+
+	// Named returns a child logger whose entries carry the logger field and
+	// whose level can be set independently with SetNamedLevel
+	Named(name string) Logger
+
+	// WithLevel returns a child logger with its own level that inherits the
+	// fields, context and configuration of its parent
+	WithLevel(level logrus.Level) Logger
+
+	// WithCallerSkip returns a logger whose entries report the caller n
+	// frames further up the stack, for helpers wrapping the logger
+	WithCallerSkip(n int) Logger
+
+	// With returns a logger whose entries carry the given typed fields
+	With(fields ...Field) Logger
+
+	// WithFieldPrefix returns a child logger prefixing the keys of the
+	// fields added to it (e.g. "payments."), so they can't collide with the
+	// standard fields
+	WithFieldPrefix(prefix string) Logger
+
+	// WithGroup returns a child logger nesting the fields added to it under
+	// the named object, like slog's Logger.WithGroup
+	WithGroup(name string) Logger
+
+	// Log logs msg at level with the context fields and the given typed
+	// fields, which are only converted when the level is enabled
+	Log(ctx context.Context, level logrus.Level, msg string, fields ...Field)
+
 	// Context methods
 	DebugContext(ctx context.Context, args ...interface{})
 	DebugfContext(ctx context.Context, format string, args ...interface{})
@@ -61,6 +107,8 @@ type Logger interface {
 	FatalfContext(ctx context.Context, format string, args ...interface{})
 	PanicContext(ctx context.Context, args ...interface{})
 	PanicfContext(ctx context.Context, format string, args ...interface{})
+	DPanicContext(ctx context.Context, args ...interface{})
+	DPanicfContext(ctx context.Context, format string, args ...interface{})
 	PrintContext(ctx context.Context, args ...interface{})
 	PrintfContext(ctx context.Context, format string, args ...interface{})
 	PrintlnContext(ctx context.Context, args ...interface{})
@@ -85,32 +133,270 @@ type Config struct {
 	// TracesSampleRate is the sampling rate for traces in Sentry (0.0 - 1.0)
 	TracesSampleRate float64
 
+	// TracesSampler decides the sampling rate per transaction (e.g. by route
+	// or tenant); when set it takes precedence over TracesSampleRate
+	TracesSampler sentry.TracesSampler
+
+	// SentryLevels are the levels sent to Sentry as events
+	// (empty uses DefaultSentryLevels)
+	SentryLevels []logrus.Level
+
+	// SentryTagFields are log fields sent to Sentry as searchable tags
+	// instead of event extra data (e.g. "tenant", "region")
+	SentryTagFields []string
+
+	// SentryBeforeSend is called with every event before it is sent to
+	// Sentry; it can scrub the event or return nil to drop it
+	SentryBeforeSend func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event
+
+	// SentryIgnoreErrors are errors (matched with errors.Is) never reported
+	// to Sentry, e.g. context.Canceled
+	SentryIgnoreErrors []error
+
+	// SentryIgnoreMessages are regular expressions; entries whose message
+	// or error message matches are not reported to Sentry
+	SentryIgnoreMessages []string
+
+	// SentryIgnoreFields drops Sentry events for entries where a field has
+	// the given value
+	SentryIgnoreFields map[string]interface{}
+
+	// SentryHTTPProxy and SentryHTTPSProxy are the proxies used to reach
+	// Sentry; empty uses the HTTP_PROXY/HTTPS_PROXY environment variables
+	SentryHTTPProxy  string
+	SentryHTTPSProxy string
+
+	// SentryDebug enables the Sentry client debug output
+	SentryDebug bool
+
+	// SentrySampleRate is the sampling rate for error events (0.0 - 1.0);
+	// 0 sends every event
+	SentrySampleRate float64
+
+	// SentryFlushTimeout is how long pending events are waited for on exit
+	// and by FlushSentry (0 uses 2 seconds)
+	SentryFlushTimeout time.Duration
+
+	// SentrySpoolSize is the maximum number of events kept for retry while
+	// Sentry is unreachable (0 disables the spool)
+	SentrySpoolSize int
+
+	// SentrySpoolDir persists the spool on disk; empty keeps it in memory
+	SentrySpoolDir string
+
+	// SentryRouteField is the field used to route events to the Sentry
+	// projects in SentryRouteDSNs (e.g. "team" or "module")
+	SentryRouteField string
+
+	// SentryRouteDSNs maps values of SentryRouteField to the DSN of the
+	// project receiving their events; other events go to SentryDSN
+	SentryRouteDSNs map[string]string
+
+	// SentryBreadcrumbs enables recording entries that are not sent as
+	// Sentry events as breadcrumbs on the hub of their context, attached to
+	// the next event of the same context (see SentryHook.SetBreadcrumbLevel)
+	SentryBreadcrumbs bool
+
+	// SentryBreadcrumbLevel is the least severe level recorded as a
+	// breadcrumb (InfoLevel when zero, since panic entries are events)
+	SentryBreadcrumbLevel logrus.Level
+
+	// SentryMaxBreadcrumbs is the maximum number of breadcrumbs kept
+	// (0 uses the Sentry default of 30, negative disables breadcrumbs)
+	SentryMaxBreadcrumbs int
+
 	// Level is the minimum logging level
 	Level logrus.Level
 
 	// ReportCaller indicates whether to report the function that made the log
 	ReportCaller bool
 
+	// StackTraces adds the stack trace of the caller to the error, fatal and
+	// panic entries written in JSON (DefaultConfig enables it)
+	StackTraces bool
+
+	// NestedFields writes the fields with dotted keys ("db.query") as
+	// nested objects in JSON ({"db": {"query": ...}})
+	NestedFields bool
+
 	// CustomFields are custom fields that will be added to all logs
 	CustomFields map[string]interface{}
 	HostName     string
 	ServerName   string
+
+	// TraceIDFormat is the format of the trace and request IDs generated,
+	// e.g. by EnsureTraceID and HTTPMiddleware. TraceIDUUID, the zero
+	// value, keeps the format set before with SetTraceIDFormat (UUIDs by
+	// default).
+	TraceIDFormat TraceIDFormat
+
+	// IDGenerator generates the trace and request IDs instead, e.g. to use
+	// Snowflake IDs or IDs prefixed with the region. Without it, the
+	// generator set before with SetIDGenerator is kept.
+	IDGenerator IDGenerator
+
+	// DeadlineRemaining adds the time left before the deadline of the
+	// context to the entries logged with one (see DeadlineHook)
+	DeadlineRemaining bool
+
+	// ProcessIDs adds the process ID and the ID of the goroutine logging to
+	// all logs (see ProcessIDHook)
+	ProcessIDs bool
+
+	// Sequence numbers the entries written by the logger in the seq field,
+	// so consumers can detect the entries lost or reordered by the shipping
+	// pipeline
+	Sequence bool
+
+	// HostFields adds the primary IP address (host_ip), operating system
+	// (os) and architecture (arch) of the host to all logs
+	HostFields bool
+
+	// AWSMetadata adds the EC2 instance ID and availability zone, or the ECS
+	// task ARN, cluster and availability zone, to all logs. The metadata
+	// endpoints are queried once, when the first logger is created.
+	AWSMetadata bool
+
+	// Enrichers contribute static fields added to all logs, e.g. the
+	// metadata of the platform (see AzureEnricher). They are run when the
+	// logger is created, the fields of the later ones taking precedence.
+	Enrichers []Enricher
+
+	// DeploymentFields adds the git SHA, build time and CI pipeline ID to all
+	// logs, as registered with SetBuildInfo or read from the conventional
+	// environment variables (GIT_SHA, BUILD_TIME, CI_PIPELINE_ID, ...)
+	DeploymentFields bool
+
+	// NamedLevels sets the level of named loggers (see Named) by name
+	NamedLevels map[string]logrus.Level
+
+	// SyslogNetwork enables RFC5424 syslog output when set: "unix" for the
+	// local syslog socket, "tcp" or "udp" for a remote collector
+	SyslogNetwork string
+
+	// SyslogAddress is the remote collector address (host:port) or the path
+	// of the local socket. Empty uses the system default local socket
+	SyslogAddress string
+
+	// SyslogFacility is the syslog facility used for all messages
+	// (DefaultConfig uses SyslogFacilityUser)
+	SyslogFacility SyslogFacility
+
+	// Clock sets the time of the entries; nil uses the real time
+	Clock Clock
+
+	// TimestampFormat is the layout of the entry times (e.g.
+	// time.RFC3339Nano) or TimestampEpochMillis. Empty uses RFC3339 in JSON
+	// and DefaultPrettyTimestampFormat in dev. The plain dev formatter
+	// doesn't support TimestampEpochMillis.
+	TimestampFormat string
+
+	// TimestampUTC writes the entry times in UTC instead of local time
+	TimestampUTC bool
+
+	// SampleRate is the fraction (0.0 - 1.0) of info, debug and trace
+	// entries written; 0 keeps the current rate, every entry by default.
+	// See SetSampleRate.
+	SampleRate float64
+
+	// DedupWindow suppresses the entries repeating the level, message,
+	// caller and fields of an entry written less than DedupWindow ago, and
+	// writes a summary with their count at the end of the window (0
+	// disables the deduplication)
+	DedupWindow time.Duration
+
+	// AdaptiveSamplingThreshold samples the entries less severe than error
+	// beyond this many entries with the same level and message per
+	// AdaptiveSamplingWindow, tightening the sampling while the volume stays
+	// high (0 disables the adaptive sampling)
+	AdaptiveSamplingThreshold int
+
+	// AdaptiveSamplingWindow is the period over which the entries are
+	// counted (0 uses DefaultAdaptiveSamplingWindow)
+	AdaptiveSamplingWindow time.Duration
+
+	// ErrorRateThreshold logs a critical alert when this many error, fatal
+	// and panic entries are logged within ErrorRateWindow (0 disables the
+	// alerts). See ErrorRateHook.
+	ErrorRateThreshold int
+
+	// ErrorRateWindow is the period over which the errors are counted (0
+	// uses DefaultErrorRateWindow)
+	ErrorRateWindow time.Duration
+
+	// RedactFields are fields whose values are replaced with RedactedValue
+	// in every entry (e.g. "password", "authorization")
+	RedactFields []string
+
+	// FieldPolicies are the field policies of each environment, keyed by
+	// environment name; nil uses DefaultFieldPolicies
+	FieldPolicies map[string]FieldPolicy
+
+	// PseudonymizeKey is the secret HMAC key of the PseudonymizeHook. When
+	// set, the PseudonymizeFields are replaced with their pseudonyms in
+	// every entry and in the users reported to Sentry.
+	PseudonymizeKey string
+
+	// PseudonymizeFields are the fields pseudonymized with PseudonymizeKey
+	// (DefaultPseudonymizedFields when empty)
+	PseudonymizeFields []string
+
+	// Encoder is the library serializing the entries (EncoderLogrus by
+	// default)
+	Encoder Encoder
+
+	// PlainDevFormatter uses the logrus text formatter instead of the
+	// PrettyFormatter when the entries are written as text
+	PlainDevFormatter bool
+
+	// ForceJSON writes the entries in JSON even in the dev environment or
+	// to a terminal. It takes precedence over ForceText.
+	ForceJSON bool
+
+	// ForceText writes the entries as text even outside of the dev
+	// environment and when the output isn't a terminal. By default, text is
+	// written in dev and to terminals and JSON otherwise.
+	ForceText bool
+
+	// AsyncBufferSize writes the entries from a background goroutine with a
+	// buffer of this many entries (0 writes synchronously). See AsyncWriter
+	// and FlushLogs.
+	AsyncBufferSize int
+
+	// BackpressurePolicy decides what happens to the entries when the async
+	// buffer, or the buffer of an async hook worker, is full
+	// (BackpressureBlock by default)
+	BackpressurePolicy BackpressurePolicy
+
+	// AsyncHookWorkers fires the Sentry and syslog hooks from this many
+	// workers (0 fires them in the logging call). See AsyncHook.
+	AsyncHookWorkers int
+
+	// HookErrorPolicy decides what happens when a hook fails
+	// (HookErrorStderr by default)
+	HookErrorPolicy HookErrorPolicy
+
+	// HookErrorWriter receives the hook failures with HookErrorWriter
+	HookErrorWriter io.Writer
+
+	// HookErrorHandler is called with the hook failures with
+	// HookErrorCallback
+	HookErrorHandler func(err *HookError)
+
+	// MaxMessageLength truncates longer messages (0 disables the limit)
+	MaxMessageLength int
+
+	// MaxFieldLength truncates longer string, []byte and error field values
+	// (0 disables the limit)
+	MaxFieldLength int
 }
 
-// DefaultConfig creates a default configuration
+// DefaultConfig creates a default configuration from the environment
+// variables prefixed with DefaultEnvPrefix (see ConfigFromEnv). Invalid
+// values are ignored.
 func DefaultConfig() Config {
-	return Config{
-		Environment:      os.Getenv("ENVIRONMENT"),
-		AppName:          os.Getenv("APP_NAME"),
-		SentryDSN:        os.Getenv("SENTRY_DSN"),
-		Release:          os.Getenv("APP_NAME") + "@" + os.Getenv("DEPLOY_ID"),
-		HostName:         os.Getenv("HOSTNAME"),
-		ServerName:       os.Getenv("APP_NAME"),
-		TracesSampleRate: 0.2,
-		Level:            logrus.TraceLevel,
-		ReportCaller:     true,
-		CustomFields:     make(map[string]interface{}),
-	}
+	config, _ := ConfigFromEnv(DefaultEnvPrefix)
+	return config
 }
 
 // FieldsHook is a hook to add custom fields to all logs
@@ -131,47 +417,127 @@ func (hook *FieldsHook) Fire(entry *logrus.Entry) error {
 	return nil
 }
 
+// entryFields returns the fields added to every entry: the CustomFields, the
+// host and deployment metadata when enabled and, outside of dev where a
+// single process is watched, the standard fields identifying the process, its
+// container and the build
+func entryFields(config Config, build logrus.Fields) logrus.Fields {
+	fields := make(logrus.Fields, 5+len(build)+len(config.CustomFields))
+	if config.Environment != "dev" {
+		fields["env"] = config.Environment
+		fields["appname"] = config.AppName
+		fields["hostname"] = config.HostName
+		fields["servername"] = config.ServerName
+		fields["release"] = config.Release
+		if id := containerID(); id != "" {
+			fields[ContainerIDField] = id
+		}
+		for k, v := range build {
+			fields[k] = v
+		}
+	}
+	if config.HostFields {
+		for k, v := range hostFields() {
+			fields[k] = v
+		}
+	}
+	if config.DeploymentFields {
+		for k, v := range deploymentFields() {
+			fields[k] = v
+		}
+	}
+	for k, v := range config.CustomFields {
+		fields[k] = v
+	}
+	return fields
+}
+
 // CallerJSONFormatter is a custom JSON formatter that includes caller information
 type CallerJSONFormatter struct {
 	*logrus.JSONFormatter
+
+	// StackTraces adds the stack trace of the caller to error, fatal and
+	// panic entries (see StackTraceField)
+	StackTraces bool
+
+	// NestFields writes the fields with dotted keys as nested objects (see
+	// FieldSeparator)
+	NestFields bool
 }
 
 // Format formats the log entry including caller information
 func (f *CallerJSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	addStructuredFields(entry, f.StackTraces, f.NestFields)
+
+	formatter := f.JSONFormatter
+	if formatter.TimestampFormat == TimestampEpochMillis {
+		formatter = epochMillisFormatter(formatter, entry)
+		defer putJSONFormatter(formatter)
+	}
+	return formatCustomLevel(formatter, entry)
+}
+
+// addStructuredFields adds the caller information and, with stackTraces, the
+// stack trace of error levels and above (see errorStack) to the fields of
+// the entry, lists the joined errors in an array and nests the dotted keys
+// with nest
+func addStructuredFields(entry *logrus.Entry, stackTraces, nest bool) {
 	// Get caller information
 	if entry.Caller != nil {
-		entry.Data["caller"] = fmt.Sprintf("%s:%d", filepath.Base(entry.Caller.File), entry.Caller.Line)
+		entry.Data["caller"] = filepath.Base(entry.Caller.File) + ":" + strconv.Itoa(entry.Caller.Line)
 		entry.Data["function"] = getFunctionName(entry.Caller.Function)
 		entry.Data["full_function"] = entry.Caller.Function
 		entry.Data["file"] = entry.Caller.File
 		entry.Data["line"] = entry.Caller.Line
 	}
 
-	// Add stack trace for error levels and above
-	if entry.Level >= logrus.ErrorLevel {
-		// Get stack trace with more detail
-		stack := make([]byte, 8192) // Increased buffer size
-		length := runtime.Stack(stack, false)
-		stackStr := string(stack[:length])
+	// Add stack trace for error levels and above, the one recorded by the
+	// error when it has one rather than the stack of the logging call
+	if stackTraces && entry.Level <= logrus.ErrorLevel {
+		if _, ok := entry.Data[StackTraceField]; !ok {
+			err, _ := entry.Data[logrus.ErrorKey].(error)
+			stack := errorStack(err)
+			if stack == "" {
+				stack = captureStack(callerSkip(entry.Context))
+			}
+			if stack != "" {
+				entry.Data[StackTraceField] = stack
+			}
+		}
+	}
+
+	splitJoinedError(entry.Data)
 
-		// Parse and format the stack trace more clearly
-		lines := strings.Split(stackStr, "\n")
-		var cleanStack []string
+	if nest {
+		nestFields(entry.Data)
+	}
+}
 
-		for _, line := range lines {
-			if line != "" && !strings.Contains(line, "runtime/debug.Stack") &&
-				!strings.Contains(line, "github.com/sirupsen/logrus") &&
-				!strings.Contains(line, "aloig.(*CallerJSONFormatter).Format") {
-				cleanStack = append(cleanStack, line)
+// cleanStackTrace formats a stack trace more clearly, removing the empty
+// lines and the frames of the stack capture, of logrus and of the given
+// functions
+func cleanStackTrace(stack []byte, skip ...string) string {
+	lines := strings.Split(string(stack), "\n")
+	var cleanStack []string
+
+	for _, line := range lines {
+		if line == "" || strings.Contains(line, "runtime/debug.Stack") ||
+			strings.Contains(line, "github.com/sirupsen/logrus") {
+			continue
+		}
+		skipped := false
+		for _, s := range skip {
+			if strings.Contains(line, s) {
+				skipped = true
+				break
 			}
 		}
-
-		if len(cleanStack) > 0 {
-			entry.Data["stack_trace"] = strings.Join(cleanStack, "\n")
+		if !skipped {
+			cleanStack = append(cleanStack, line)
 		}
 	}
 
-	return f.JSONFormatter.Format(entry)
+	return strings.Join(cleanStack, "\n")
 }
 
 // getFunctionName extracts the function name without the package
@@ -186,7 +552,97 @@ func getFunctionName(fullName string) string {
 // logrusLogger is a Logger implementation that uses logrus
 type logrusLogger struct {
 	logger *logrus.Logger
-	ctx    context.Context
+	entry  *logrus.Entry
+
+	// prefix is prepended to the keys of the fields added to the logger
+	prefix string
+
+	// groups are the names of the nested objects holding the fields added
+	// to the logger, see WithGroup
+	groups []string
+
+	// rootLevel is the level of the loggers created by NewLogger, shared by
+	// their children. The logrus logger then logs every level and the level
+	// is checked here, so children can be more verbose than their parent.
+	// Without it, the level of the logrus logger applies.
+	rootLevel *uint32
+
+	// settings are the settings of the logger changed at runtime, shared
+	// with its children
+	settings *loggerSettings
+
+	// levelOverride is the level set with WithLevel, if any
+	levelOverride *logrus.Level
+
+	// levelName is the name of a named logger, whose level is set with
+	// SetNamedLevel
+	levelName string
+
+	// resources are released when the logger is replaced, set by NewLogger
+	resources *loggerResources
+}
+
+// loggerSettings are the settings of a logger changed at runtime, e.g. by
+// the package-level setters for the singleton
+type loggerSettings struct {
+	// development makes DPanic panic, see SetDevelopment
+	development int32
+
+	// eventValidation checks the events emitted against their schema, see
+	// SetEventValidation
+	eventValidation int32
+
+	// sentryHook reports the exceptions of CaptureException, set by
+	// NewLogger when Sentry is configured
+	sentryHook *SentryHook
+}
+
+// newRootLogger returns a logger writing to logger at level
+func newRootLogger(logger *logrus.Logger, level logrus.Level) *logrusLogger {
+	logger.SetLevel(logrus.TraceLevel)
+	rootLevel := uint32(level)
+	return &logrusLogger{logger: logger, rootLevel: &rootLevel, settings: &loggerSettings{}}
+}
+
+// child returns a logger writing entry with the configuration of l
+func (l *logrusLogger) child(entry *logrus.Entry) *logrusLogger {
+	child := *l
+	child.entry = entry
+	return &child
+}
+
+// level returns the level of the logger: the level of its name when one is
+// set, else the level set with WithLevel, else the level of the root logger
+func (l *logrusLogger) level() logrus.Level {
+	if l.levelName != "" {
+		if level, ok := NamedLevel(l.levelName); ok {
+			return level
+		}
+	}
+	if l.levelOverride != nil {
+		return *l.levelOverride
+	}
+	if l.rootLevel != nil {
+		return logrus.Level(atomic.LoadUint32(l.rootLevel))
+	}
+	return l.logger.GetLevel()
+}
+
+// setLevel sets the level of the root logger
+func (l *logrusLogger) setLevel(level logrus.Level) {
+	if l.rootLevel != nil {
+		atomic.StoreUint32(l.rootLevel, uint32(level))
+		return
+	}
+	l.logger.SetLevel(level)
+}
+
+// newEntry returns the entry carrying the fields and context of the logger
+func (l *logrusLogger) newEntry() *logrus.Entry {
+	if l.entry != nil {
+		return l.entry
+	}
+	return logrus.NewEntry(l.logger)
 }
 
 // isSentryEnvironment checks if the current environment requires Sentry integration
@@ -195,78 +651,291 @@ func isSentryEnvironment(env string) bool {
 }
 
 var (
-	log  Logger
-	once sync.Once
+	log   Logger
+	logMu sync.RWMutex
+
+	// once initializes the singleton. It is replaced by ResetForTesting,
+	// so it is read under logMu.
+	once = &sync.Once{}
 )
 
+// DefaultSentryLevels are the levels sent to Sentry as events when
+// Config.SentryLevels is empty
+var DefaultSentryLevels = []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel}
+
 // NewLogger creates a new Logger instance according to the provided configuration
 func NewLogger(config Config) Logger {
 	logrusInstance := logrus.New()
 
 	// Configure logging level
-	logrusInstance.SetLevel(config.Level)
+	root := newRootLogger(logrusInstance, config.Level)
+	root.resources = newLoggerResources()
 	logrusInstance.SetReportCaller(config.ReportCaller)
 
-	// Configure format according to environment
-	if config.Environment != "dev" {
-		logrusInstance.SetOutput(os.Stdout)
-		standardFields := logrus.Fields{
-			"env":        config.Environment,
-			"appname":    config.AppName,
-			"hostname":   config.HostName,
-			"servername": config.ServerName,
-			"release":    config.Release,
-		}
+	for name, level := range config.NamedLevels {
+		SetNamedLevel(name, level)
+	}
+	root.setEventValidation(config.Environment == "dev")
+	root.setDevelopment(config.Environment == "dev")
+	if config.TraceIDFormat != TraceIDUUID {
+		SetTraceIDFormat(config.TraceIDFormat)
+	}
+	if config.IDGenerator != nil {
+		SetIDGenerator(config.IDGenerator)
+	}
 
-		// Add custom fields
-		for k, v := range config.CustomFields {
-			standardFields[k] = v
-		}
+	if config.Clock != nil {
+		logrusInstance.AddHook(&ClockHook{Clock: config.Clock})
+	}
+
+	// Process fields before any other hook sees them
+	for _, hook := range StandardHooks() {
+		logrusInstance.AddHook(hook)
+	}
+
+	if config.DeadlineRemaining {
+		logrusInstance.AddHook(DeadlineHook{})
+	}
+	if config.ProcessIDs {
+		logrusInstance.AddHook(ProcessIDHook{})
+	}
+
+	if policy, ok := fieldPolicy(config); ok {
+		logrusInstance.AddHook(NewFieldPolicyHook(policy))
+	}
+
+	var pseudonymizer *PseudonymizeHook
+	if config.PseudonymizeKey != "" {
+		pseudonymizer = NewPseudonymizeHook([]byte(config.PseudonymizeKey), config.PseudonymizeFields)
+		logrusInstance.AddHook(pseudonymizer)
+	}
+
+	if len(config.RedactFields) > 0 {
+		logrusInstance.AddHook(NewRedactHook(config.RedactFields))
+	}
+
+	if config.MaxMessageLength > 0 || config.MaxFieldLength > 0 {
+		logrusInstance.AddHook(&SizeLimitHook{MaxMessageLength: config.MaxMessageLength, MaxFieldLength: config.MaxFieldLength})
+	}
+
+	// Without a host name, e.g. on bare metal or locally, ask the system
+	if config.HostName == "" {
+		config.HostName, _ = os.Hostname()
+	}
+
+	// Without a release, identify the build from the binary itself
+	var buildFields logrus.Fields
+	if config.Release == "" {
+		config.Release, buildFields = buildRelease(config.AppName)
+	}
+
+	if fields := entryFields(config, buildFields); len(fields) > 0 {
+		logrusInstance.AddHook(&FieldsHook{Fields: fields})
+	}
+
+	// Configure format according to the encoder, environment and output
+	logrusInstance.SetOutput(os.Stdout)
+	formatter, err := encoderFormatter(config)
+	logrusInstance.SetFormatter(formatter)
+	if err != nil {
+		root.WithError(err).Error("Error initializing the encoder")
+	}
+	if config.Sequence {
+		logrusInstance.SetFormatter(&sequenceFormatter{Formatter: logrusInstance.Formatter})
+	}
+	if config.TimestampUTC {
+		logrusInstance.AddHook(utcHook{})
+	}
+
+	if config.DedupWindow > 0 {
+		dedup := newDedupFormatter(logrusInstance.Formatter, logrusInstance, config.DedupWindow)
+		logrusInstance.SetFormatter(dedup)
+		root.resources.onClose(dedup.Flush, false)
+	}
+
+	if config.AdaptiveSamplingThreshold > 0 {
+		sampler := newAdaptiveSampler(logrusInstance.Formatter, logrusInstance, config.AdaptiveSamplingThreshold, config.AdaptiveSamplingWindow)
+		logrusInstance.SetFormatter(sampler)
+		root.resources.onClose(sampler.Flush, false)
+	}
+
+	logrusInstance.SetFormatter(&statsFormatter{Formatter: &samplingFormatter{Formatter: logrusInstance.Formatter}})
+	// The rate is process-wide: keep the one set before, e.g. by a
+	// RemoteLevelPoller, unless a rate is configured
+	if config.SampleRate > 0 {
+		SetSampleRate(config.SampleRate)
+	}
+
+	output := &sinkHealth{name: "output"}
+	logrusInstance.SetOutput(&healthWriter{out: logrusInstance.Out, sink: output})
+
+	if config.AsyncBufferSize > 0 {
+		asyncWriter := NewAsyncWriter(logrusInstance.Out, AsyncOptions{
+			BufferSize: config.AsyncBufferSize,
+			Policy:     config.BackpressurePolicy,
+			Logger:     root,
+		})
+		logrusInstance.SetOutput(asyncWriter)
+		registerAsyncWriter(asyncWriter)
+		output.queue = asyncWriter
+		// Write the fatal entry before exiting
+		root.resources.onClose(func() {
+			asyncWriter.Close()
+			unregisterAsyncWriter(asyncWriter)
+		}, false)
+	}
+	if config.DedupWindow > 0 {
+		// The dedup summaries are written by a ticker, outside of the
+		// logger lock
+		logrusInstance.SetOutput(&syncWriter{out: logrusInstance.Out})
+	}
+
+	enrichers := config.Enrichers
+	if config.AWSMetadata {
+		enrichers = append(enrichers[:len(enrichers):len(enrichers)], AWSEnricher())
+	}
+	if fields := enrichFields(root, enrichers); len(fields) > 0 {
+		logrusInstance.AddHook(&FieldsHook{Fields: fields})
+	}
 
-		logrusInstance.AddHook(&FieldsHook{Fields: standardFields})
-		logrusInstance.SetFormatter(&CallerJSONFormatter{JSONFormatter: &logrus.JSONFormatter{}})
-	} else {
-		logrusInstance.SetOutput(os.Stdout)
-		logrusInstance.SetFormatter(&logrus.TextFormatter{})
+	if config.ErrorRateThreshold > 0 {
+		logrusInstance.AddHook(NewErrorRateHook(root, config.ErrorRateThreshold, config.ErrorRateWindow))
+	}
+
+	// Configure syslog output if requested
+	if config.SyslogNetwork != "" {
+		syslogHook, err := NewSyslogHook(config.SyslogNetwork, config.SyslogAddress, config.SyslogFacility, config.HostName, config.AppName)
+		if err != nil {
+			root.WithError(err).Error("Error connecting to syslog")
+		} else {
+			logrusInstance.AddHook(syslogHook)
+		}
 	}
 
 	// Initialize Sentry if necessary
 	if isSentryEnvironment(config.Environment) && config.SentryDSN != "" {
 		err := initializeSentry(config)
 		if err != nil {
-			logrusInstance.WithError(err).Error("Error initializing Sentry")
+			root.WithError(err).Error("Error initializing Sentry")
 		} else {
 			// Configure Sentry hook
-			sentryLevels := []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel}
-			sentryHook, err := sentrylogrus.New(sentryLevels, sentry.CurrentHub().Client().Options())
+			sentryHook := NewSentryHook(sentry.NewHub(sentry.CurrentHub().Client(), sentry.NewScope()), sentryEventLevels(config))
+			sentryHook.SetTagFields(config.SentryTagFields)
+			filter, err := NewSentryFilter(config.SentryIgnoreErrors, config.SentryIgnoreMessages, config.SentryIgnoreFields)
 			if err != nil {
-				logrusInstance.WithError(err).Error("Error creating Sentry hook")
+				root.WithError(err).Error("Error compiling Sentry ignore rules")
 			} else {
-				logrusInstance.AddHook(sentryHook)
-				// Register handler for event flush on exit
-				logrus.RegisterExitHandler(func() {
-					sentryHook.Flush(2 * time.Second)
-				})
-				logrusInstance.Info("Sentry initialized successfully")
+				sentryHook.SetFilter(filter)
+			}
+			if config.SentryBreadcrumbs {
+				level := config.SentryBreadcrumbLevel
+				if level == logrus.PanicLevel {
+					level = logrus.InfoLevel
+				}
+				sentryHook.SetBreadcrumbLevel(level)
+			}
+			if config.SentryRouteField != "" {
+				routes, err := newSentryRouteHubs(config)
+				if err != nil {
+					root.WithError(err).Error("Error initializing Sentry routes")
+				} else {
+					sentryHook.SetRoutes(config.SentryRouteField, routes)
+				}
 			}
+			logrusInstance.AddHook(sentryHook)
+			setSentryPseudonymizer(pseudonymizer)
+			registerSentryHook(sentryHook, sentryFlushTimeout(config))
+			root.settings.sentryHook = sentryHook
+			// Flush the events on exit
+			root.resources.onClose(func() {
+				sentryHook.Flush(sentryFlushTimeout(config))
+				unregisterSentryHook(sentryHook)
+			}, false)
+			root.Info("Sentry initialized successfully")
 		}
 	}
 
-	return &logrusLogger{logger: logrusInstance}
+	setSinks(append([]*sinkHealth{output}, countHookErrors(logrusInstance, hookErrorHandler(config))...))
+	if config.AsyncHookWorkers > 0 {
+		runSinkHooksAsync(logrusInstance, AsyncHookOptions{Workers: config.AsyncHookWorkers, Policy: config.BackpressurePolicy}, root.resources)
+	}
+
+	return root
+}
+
+// StandardHooks returns the hooks NewLogger adds before any other to process
+// the entry fields: caller reporting, LogValuer resolution, error chain
+// expansion and error fields merging. Add them to loggers wrapped with FromLogrus to get the same
+// fields.
+func StandardHooks() []logrus.Hook {
+	return []logrus.Hook{&CallerHook{}, &LogValuerHook{}, &ErrorChainHook{}, &ErrorFieldsHook{}}
+}
+
+// sentryEventLevels returns the levels sent to Sentry as events
+func sentryEventLevels(config Config) []logrus.Level {
+	if len(config.SentryLevels) == 0 {
+		return DefaultSentryLevels
+	}
+	return config.SentryLevels
+}
+
+// FromLogrus wraps an existing logrus logger in the Logger interface. The
+// level of the logrus logger still applies to the named children and the
+// children created with WithLevel.
+func FromLogrus(logger *logrus.Logger) Logger {
+	return &logrusLogger{logger: logger, settings: &loggerSettings{}}
 }
 
 // initializeSentry configures the connection with Sentry
 func initializeSentry(config Config) error {
-	return sentry.Init(sentry.ClientOptions{
+	options := sentryClientOptions(config)
+	if err := applySentrySpool(&options, config, config.SentrySpoolDir); err != nil {
+		return err
+	}
+	return sentry.Init(options)
+}
+
+// applySentrySpool routes the client requests through a spool kept in dir
+// when the spool is enabled
+func applySentrySpool(options *sentry.ClientOptions, config Config, dir string) error {
+	if config.SentrySpoolSize <= 0 {
+		return nil
+	}
+	base := options.HTTPTransport
+	if base == nil {
+		base = &http.Transport{Proxy: sentryProxy(*options)}
+	}
+	spool, err := NewSentrySpool(base, dir, config.SentrySpoolSize)
+	if err != nil {
+		return err
+	}
+	options.HTTPTransport = spool
+	return nil
+}
+
+// sentryClientOptions builds the Sentry client options for the configuration
+func sentryClientOptions(config Config) sentry.ClientOptions {
+	beforeSend := config.SentryBeforeSend
+	if beforeSend == nil {
+		beforeSend = func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			return event
+		}
+	}
+
+	return sentry.ClientOptions{
 		Dsn:              config.SentryDSN,
 		Environment:      config.Environment,
 		Release:          config.Release,
 		AttachStacktrace: true,
 		ServerName:       config.AppName,
 		TracesSampleRate: config.TracesSampleRate,
-		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
-			return event
-		},
+		TracesSampler:    config.TracesSampler,
+		SampleRate:       config.SentrySampleRate,
+		Debug:            config.SentryDebug,
+		HTTPProxy:        config.SentryHTTPProxy,
+		HTTPSProxy:       config.SentryHTTPSProxy,
+		MaxBreadcrumbs:   config.SentryMaxBreadcrumbs,
+		BeforeSend:       beforeSend,
 		Tags: map[string]string{
 			"env":        config.Environment,
 			"appname":    config.AppName,
@@ -274,168 +943,359 @@ func initializeSentry(config Config) error {
 			"servername": config.ServerName,
 			"release":    config.Release,
 		},
-	})
+	}
+}
+
+// sentryProxy returns the proxy configured in the options, falling back to
+// the proxy environment variables like the Sentry transports do
+func sentryProxy(options sentry.ClientOptions) func(*http.Request) (*url.URL, error) {
+	proxy := options.HTTPSProxy
+	if proxy == "" {
+		proxy = options.HTTPProxy
+	}
+	if proxy == "" {
+		return http.ProxyFromEnvironment
+	}
+	return func(*http.Request) (*url.URL, error) {
+		return url.Parse(proxy)
+	}
+}
+
+// sentryFlushTimeout returns how long pending Sentry events are waited for
+func sentryFlushTimeout(config Config) time.Duration {
+	if config.SentryFlushTimeout <= 0 {
+		return 2 * time.Second
+	}
+	return config.SentryFlushTimeout
 }
 
 // GetLogger returns a singleton instance of the logger
 func GetLogger() Logger {
-	once.Do(func() {
-		log = NewLogger(DefaultConfig())
+	singletonOnce().Do(func() {
+		logMu.Lock()
+		defer logMu.Unlock()
+		if log == nil {
+			log = NewLogger(DefaultConfig())
+		}
 	})
+
+	logMu.RLock()
+	defer logMu.RUnlock()
 	return log
 }
 
-// ConfigureLogger configures the singleton logger instance with the given configuration
+// singletonOnce returns the once initializing the singleton
+func singletonOnce() *sync.Once {
+	logMu.RLock()
+	defer logMu.RUnlock()
+	return once
+}
+
+// ConfigureLogger configures the singleton logger instance with the given configuration.
+// It has no effect once the singleton was initialized, either by a previous
+// call or by GetLogger; use Reconfigure to replace an existing singleton.
 func ConfigureLogger(config Config) {
-	once.Do(func() {
+	singletonOnce().Do(func() {
+		logMu.Lock()
+		defer logMu.Unlock()
 		log = NewLogger(config)
 	})
 }
 
-// FlushSentry ensures that all pending events are sent to Sentry
-func FlushSentry() {
-	if isSentryEnvironment(os.Getenv("ENVIRONMENT")) {
-		sentry.Flush(2 * time.Second)
+// Reconfigure replaces the singleton logger with a new one built from config,
+// whether or not it was already initialized. Callers of GetLogger and the
+// package-level functions see either the old or the new logger, never a
+// partially configured one. The old logger is then closed: its buffered
+// entries are written, its Sentry events flushed, and it stops being
+// flushed by FlushLogs and FlushSentry.
+func Reconfigure(config Config) {
+	logger := NewLogger(config)
+
+	// Mark the singleton as initialized so GetLogger doesn't replace it
+	singletonOnce().Do(func() {})
+
+	logMu.Lock()
+	previous := log
+	log = logger
+	logMu.Unlock()
+
+	if previous, ok := previous.(*logrusLogger); ok && previous.resources != nil {
+		previous.resources.close()
 	}
 }
 
+// ResetForTesting discards the singleton logger so the next GetLogger or
+// ConfigureLogger call builds a new one. It is meant for tests only.
+func ResetForTesting() {
+	logMu.Lock()
+	defer logMu.Unlock()
+	log = nil
+	once = &sync.Once{}
+}
+
 // Logger interface implementation for logrusLogger
 
 func (l *logrusLogger) Debug(args ...interface{}) {
-	l.logger.Debug(args...)
+	if debugStripped || !l.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	l.newEntry().Debug(args...)
 }
 
 func (l *logrusLogger) Debugf(format string, args ...interface{}) {
-	l.logger.Debugf(format, args...)
+	if debugStripped || !l.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	l.newEntry().Debugf(format, args...)
 }
 
 func (l *logrusLogger) Info(args ...interface{}) {
-	l.logger.Info(args...)
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
+	l.newEntry().Info(args...)
 }
 
 func (l *logrusLogger) Infof(format string, args ...interface{}) {
-	l.logger.Infof(format, args...)
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
+	l.newEntry().Infof(format, args...)
 }
 
 func (l *logrusLogger) Warn(args ...interface{}) {
-	l.logger.Warn(args...)
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
+	l.newEntry().Warn(args...)
 }
 
 func (l *logrusLogger) Warning(args ...interface{}) {
-	l.logger.Warn(args...)
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
+	l.newEntry().Warn(args...)
 }
 
 func (l *logrusLogger) Warnf(format string, args ...interface{}) {
-	l.logger.Warnf(format, args...)
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
+	l.newEntry().Warnf(format, args...)
 }
 
 func (l *logrusLogger) Warningf(format string, args ...interface{}) {
-	l.logger.Warnf(format, args...)
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
+	l.newEntry().Warnf(format, args...)
 }
 
 func (l *logrusLogger) Error(args ...interface{}) {
-	l.logger.Error(args...)
+	if !l.IsLevelEnabled(logrus.ErrorLevel) {
+		return
+	}
+	l.withErrorArg(args).Error(args...)
 }
 
 func (l *logrusLogger) Errorf(format string, args ...interface{}) {
-	l.logger.Errorf(format, args...)
+	if !l.IsLevelEnabled(logrus.ErrorLevel) {
+		return
+	}
+	l.newEntry().Errorf(format, args...)
 }
 
 func (l *logrusLogger) Fatal(args ...interface{}) {
-	l.logger.Fatal(args...)
+	l.newEntry().Fatal(args...)
 }
 
 func (l *logrusLogger) Fatalf(format string, args ...interface{}) {
-	l.logger.Fatalf(format, args...)
+	l.newEntry().Fatalf(format, args...)
 }
 
 func (l *logrusLogger) Panic(args ...interface{}) {
-	l.logger.Panic(args...)
+	l.newEntry().Panic(args...)
 }
 
 func (l *logrusLogger) Panicf(format string, args ...interface{}) {
-	l.logger.Panicf(format, args...)
+	l.newEntry().Panicf(format, args...)
 }
 
 func (l *logrusLogger) Print(args ...interface{}) {
-	l.logger.Print(args...)
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
+	l.newEntry().Print(args...)
 }
 
 func (l *logrusLogger) Printf(format string, args ...interface{}) {
-	l.logger.Printf(format, args...)
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
+	l.newEntry().Printf(format, args...)
 }
 
 func (l *logrusLogger) Println(args ...interface{}) {
-	l.logger.Println(args...)
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
+	l.newEntry().Println(args...)
 }
 
 func (l *logrusLogger) Trace(args ...interface{}) {
-	l.logger.Trace(args...)
+	if debugStripped || !l.IsLevelEnabled(logrus.TraceLevel) {
+		return
+	}
+	l.newEntry().Trace(args...)
 }
 
 func (l *logrusLogger) Tracef(format string, args ...interface{}) {
-	l.logger.Tracef(format, args...)
+	if debugStripped || !l.IsLevelEnabled(logrus.TraceLevel) {
+		return
+	}
+	l.newEntry().Tracef(format, args...)
+}
+
+func (l *logrusLogger) IsLevelEnabled(level logrus.Level) bool {
+	return !strippedLevel(level) && l.level() >= level && l.logger.IsLevelEnabled(level)
+}
+
+// strippedLevel reports whether the entries at level are compiled out by the
+// aloig_nodebug build tag
+func strippedLevel(level logrus.Level) bool {
+	return debugStripped && level >= logrus.DebugLevel
 }
 
 func (l *logrusLogger) WithField(key string, value interface{}) Logger {
-	return &logrusLogger{logger: l.logger.WithField(key, value).Logger, ctx: l.ctx}
+	if len(l.groups) > 0 {
+		return l.WithFields(map[string]interface{}{key: value})
+	}
+	return l.child(l.newEntry().WithField(l.fieldKey(key), value))
 }
 
 func (l *logrusLogger) WithFields(fields map[string]interface{}) Logger {
 	logrusFields := logrus.Fields{}
 	for k, v := range fields {
-		logrusFields[k] = v
+		logrusFields[l.fieldKey(k)] = v
 	}
-	return &logrusLogger{logger: l.logger.WithFields(logrusFields).Logger, ctx: l.ctx}
+	entry := l.newEntry()
+	if len(l.groups) > 0 {
+		logrusFields = groupFields(entry.Data, l.groups, logrusFields)
+	}
+	return l.child(entry.WithFields(logrusFields))
 }
 
 func (l *logrusLogger) WithError(err error) Logger {
-	return &logrusLogger{logger: l.logger.WithError(err).Logger, ctx: l.ctx}
+	return l.child(l.newEntry().WithError(err))
 }
 
 func (l *logrusLogger) WithContext(ctx context.Context) Logger {
-	return &logrusLogger{logger: l.logger, ctx: ctx}
+	return l.child(l.contextEntry(ctx, noContextFields, nil, 0))
+}
+
+// contextEntry returns the entry of the logger bound to ctx with the given
+// context fields and the values of the registered keys added, copying the
+// fields of the logger only once, with room for extra more
+func (l *logrusLogger) contextEntry(ctx context.Context, fields *contextFields, registry []registeredContextField, extra int) *logrus.Entry {
+	entry := l.newEntry()
+	// Keep the caller skip of the logger
+	if skip := callerSkip(entry.Context); skip > 0 {
+		ctx = AddCallerSkip(ctx, skip)
+	}
+
+	data := make(logrus.Fields, len(entry.Data)+fields.len()+len(registry)+extra)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	addRegisteredContextFields(ctx, registry, data)
+	fields.addTo(data)
+	return &logrus.Entry{Logger: entry.Logger, Data: data, Time: entry.Time, Context: ctx}
+}
+
+// copyEntry returns a copy of the entry of the logger with room for extra
+// more fields
+func (l *logrusLogger) copyEntry(extra int) *logrus.Entry {
+	entry := l.newEntry()
+	data := make(logrus.Fields, len(entry.Data)+extra)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	return &logrus.Entry{Logger: entry.Logger, Data: data, Time: entry.Time, Context: entry.Context}
+}
+
+func (l *logrusLogger) WithFingerprint(parts ...string) Logger {
+	return l.WithField(SentryFieldFingerprint, parts)
 }
 
 // Context method implementation
 
 func (l *logrusLogger) DebugContext(ctx context.Context, args ...interface{}) {
+	if debugStripped || !l.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
 	l.withContextFields(ctx).Debug(args...)
 }
 
 func (l *logrusLogger) DebugfContext(ctx context.Context, format string, args ...interface{}) {
+	if debugStripped || !l.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
 	l.withContextFields(ctx).Debugf(format, args...)
 }
 
 func (l *logrusLogger) InfoContext(ctx context.Context, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
 	l.withContextFields(ctx).Info(args...)
 }
 
 func (l *logrusLogger) InfofContext(ctx context.Context, format string, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
 	l.withContextFields(ctx).Infof(format, args...)
 }
 
 func (l *logrusLogger) WarnContext(ctx context.Context, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
 	l.withContextFields(ctx).Warn(args...)
 }
 
 func (l *logrusLogger) WarnfContext(ctx context.Context, format string, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
 	l.withContextFields(ctx).Warnf(format, args...)
 }
 
 func (l *logrusLogger) WarningContext(ctx context.Context, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
 	l.withContextFields(ctx).Warning(args...)
 }
 
 func (l *logrusLogger) WarningfContext(ctx context.Context, format string, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.WarnLevel) {
+		return
+	}
 	l.withContextFields(ctx).Warningf(format, args...)
 }
 
 func (l *logrusLogger) ErrorContext(ctx context.Context, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.ErrorLevel) {
+		return
+	}
 	l.withContextFields(ctx).Error(args...)
 }
 
 func (l *logrusLogger) ErrorfContext(ctx context.Context, format string, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.ErrorLevel) {
+		return
+	}
 	l.withContextFields(ctx).Errorf(format, args...)
 }
 
@@ -456,37 +1316,49 @@ func (l *logrusLogger) PanicfContext(ctx context.Context, format string, args ..
 }
 
 func (l *logrusLogger) PrintContext(ctx context.Context, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
 	l.withContextFields(ctx).Print(args...)
 }
 
 func (l *logrusLogger) PrintfContext(ctx context.Context, format string, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
 	l.withContextFields(ctx).Printf(format, args...)
 }
 
 func (l *logrusLogger) PrintlnContext(ctx context.Context, args ...interface{}) {
+	if !l.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
 	l.withContextFields(ctx).Println(args...)
 }
 
 func (l *logrusLogger) TraceContext(ctx context.Context, args ...interface{}) {
+	if debugStripped || !l.IsLevelEnabled(logrus.TraceLevel) {
+		return
+	}
 	l.withContextFields(ctx).Trace(args...)
 }
 
 func (l *logrusLogger) TracefContext(ctx context.Context, format string, args ...interface{}) {
+	if debugStripped || !l.IsLevelEnabled(logrus.TraceLevel) {
+		return
+	}
 	l.withContextFields(ctx).Tracef(format, args...)
 }
 
-// withContextFields extracts context fields and adds them to the logger
-func (l *logrusLogger) withContextFields(ctx context.Context) Logger {
+// withContextFields extracts context fields and adds them to the logger.
+// It returns the concrete type so the arguments of the callers don't escape
+// through an interface call.
+func (l *logrusLogger) withContextFields(ctx context.Context) *logrusLogger {
 	if ctx == nil {
 		return l
 	}
 
-	fields := ExtractContextFields(ctx)
-	if len(fields) == 0 {
-		return l
-	}
-
-	return l.WithFields(fields)
+	return l.child(l.contextEntry(ctx, getContextFields(ctx), registeredContextFields(), 0))
 }
 
 // GetLogLevelFromEnv gets the log level from an environment variable
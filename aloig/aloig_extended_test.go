@@ -166,3 +166,74 @@ func TestAloigWithEmptyContextWork(t *testing.T) {
 	ctx := context.Background()
 	InfoContext(ctx, "test with empty context")
 }
+
+// TestLoggerKeepsFields tests that fields and context fields reach the output
+func TestLoggerKeepsFields(t *testing.T) {
+	buf, cleanup := setupTestLogger()
+	defer cleanup()
+
+	WithField("key1", "value1").WithFields(map[string]interface{}{"key2": "value2"}).Info("with fields")
+	InfoContext(WithTraceID(context.Background(), "trace-abc"), "with context")
+
+	output := buf.String()
+	for _, expected := range []string{"key1=value1", "key2=value2", "trace_id=trace-abc"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got: %s", expected, output)
+		}
+	}
+}
+
+// countingStringer counts how many times it is formatted
+type countingStringer struct {
+	calls int
+}
+
+func (s *countingStringer) String() string {
+	s.calls++
+	return "value"
+}
+
+// TestIsLevelEnabled tests that disabled levels report it and skip all work
+func TestIsLevelEnabled(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	if !logger.IsLevelEnabled(logrus.InfoLevel) || logger.IsLevelEnabled(logrus.DebugLevel) {
+		t.Errorf("Expected only info and above to be enabled")
+	}
+	if quiet := logger.WithLevel(logrus.ErrorLevel); quiet.IsLevelEnabled(logrus.InfoLevel) || !quiet.IsLevelEnabled(logrus.ErrorLevel) {
+		t.Errorf("Expected a child logger to report its own level")
+	}
+
+	ctx := WithTraceID(context.Background(), "trace-123")
+	arg := &countingStringer{}
+	logger.Debugf("value: %s", arg)
+	logger.Tracef("value: %s", arg)
+	logger.DebugfContext(ctx, "value: %s", arg)
+	logger.TraceContext(ctx, arg)
+	if arg.calls != 0 || buf.Len() != 0 {
+		t.Errorf("Expected disabled levels to format nothing, got %d calls and: %s", arg.calls, buf.String())
+	}
+
+	// Only the variadic arguments may be allocated, the context fields are
+	// never extracted
+	plain := testing.AllocsPerRun(100, func() {
+		logger.Debugf("value: %s", "debug")
+	})
+	withContext := testing.AllocsPerRun(100, func() {
+		logger.DebugfContext(ctx, "value: %s", "debug")
+	})
+	if plain > 1 || withContext > plain {
+		t.Errorf("Expected no allocations for disabled levels, got %v and %v with a context", plain, withContext)
+	}
+}
+
+// BenchmarkDisabledDebugContext measures the cost of a disabled debug entry
+func BenchmarkDisabledDebugContext(b *testing.B) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	ctx := WithTraceID(context.Background(), "trace-123")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.DebugfContext(ctx, "value: %s", "debug")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -70,6 +71,14 @@ func (m *MockLogger) Panicf(format string, args ...interface{}) {
 	m.Called(format, args)
 }
 
+func (m *MockLogger) DPanic(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *MockLogger) DPanicf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
 func (m *MockLogger) Printf(format string, args ...interface{}) {
 	m.Called(format, args)
 }
@@ -110,6 +119,50 @@ func (m *MockLogger) WithContext(ctx context.Context) Logger {
 	return args.Get(0).(Logger)
 }
 
+func (m *MockLogger) WithFingerprint(parts ...string) Logger {
+	args := m.Called(parts)
+	return args.Get(0).(Logger)
+}
+
+func (m *MockLogger) Named(name string) Logger {
+	args := m.Called(name)
+	return args.Get(0).(Logger)
+}
+
+func (m *MockLogger) WithLevel(level logrus.Level) Logger {
+	args := m.Called(level)
+	return args.Get(0).(Logger)
+}
+
+func (m *MockLogger) IsLevelEnabled(level logrus.Level) bool {
+	args := m.Called(level)
+	return args.Bool(0)
+}
+
+func (m *MockLogger) WithCallerSkip(n int) Logger {
+	args := m.Called(n)
+	return args.Get(0).(Logger)
+}
+
+func (m *MockLogger) With(fields ...Field) Logger {
+	args := m.Called(fields)
+	return args.Get(0).(Logger)
+}
+
+func (m *MockLogger) WithFieldPrefix(prefix string) Logger {
+	args := m.Called(prefix)
+	return args.Get(0).(Logger)
+}
+
+func (m *MockLogger) WithGroup(name string) Logger {
+	args := m.Called(name)
+	return args.Get(0).(Logger)
+}
+
+func (m *MockLogger) Log(ctx context.Context, level logrus.Level, msg string, fields ...Field) {
+	m.Called(ctx, level, msg, fields)
+}
+
 // Context methods
 func (m *MockLogger) DebugContext(ctx context.Context, args ...interface{}) {
 	m.Called(ctx, args)
@@ -167,6 +220,14 @@ func (m *MockLogger) PanicfContext(ctx context.Context, format string, args ...i
 	m.Called(ctx, format, args)
 }
 
+func (m *MockLogger) DPanicContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *MockLogger) DPanicfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
 func (m *MockLogger) PrintContext(ctx context.Context, args ...interface{}) {
 	m.Called(ctx, args)
 }
@@ -0,0 +1,105 @@
+// Package aloigerr builds errors carrying log fields and a severity.
+//
+// When such an error is logged through aloig with WithError, Error or
+// ErrorContext, its fields (and the fields of the aloigerr errors it wraps)
+// are merged into the entry automatically, so the context of a failure is
+// attached where the error is created instead of where it is logged.
+//
+//	err := aloigerr.Wrap(err, "charge card").With("order_id", order.ID)
+//	...
+//	aloig.ErrorContext(ctx, err) // order_id=... error="charge card: ..."
+package aloigerr
+
+import (
+	"github.com/aloi-tech/aloig_go/aloig"
+	"github.com/sirupsen/logrus"
+)
+
+// Error is an error carrying log fields and a severity
+type Error struct {
+	msg      string
+	cause    error
+	fields   map[string]interface{}
+	severity logrus.Level
+}
+
+var (
+	_ aloig.FieldsError   = (*Error)(nil)
+	_ aloig.SeverityError = (*Error)(nil)
+)
+
+// New returns an error with the given message at error severity
+func New(msg string) *Error {
+	return &Error{msg: msg, severity: logrus.ErrorLevel}
+}
+
+// Wrap returns an error wrapping err with the given message, keeping the
+// severity of err when it carries one. Wrapping a nil err is like New.
+func Wrap(err error, msg string) *Error {
+	if err == nil {
+		return New(msg)
+	}
+	return &Error{msg: msg, cause: err, severity: aloig.ErrorSeverity(err)}
+}
+
+// With returns a copy of the error carrying an additional field
+func (e *Error) With(key string, value interface{}) *Error {
+	return e.WithFields(map[string]interface{}{key: value})
+}
+
+// WithFields returns a copy of the error carrying additional fields
+func (e *Error) WithFields(fields map[string]interface{}) *Error {
+	copied := *e
+	copied.fields = make(map[string]interface{}, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		copied.fields[k] = v
+	}
+	for k, v := range fields {
+		copied.fields[k] = v
+	}
+	return &copied
+}
+
+// WithSeverity returns a copy of the error with the given severity
+func (e *Error) WithSeverity(level logrus.Level) *Error {
+	copied := *e
+	copied.severity = level
+	return &copied
+}
+
+// Error returns the message followed by the message of the wrapped error
+func (e *Error) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	if e.msg == "" {
+		return e.cause.Error()
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// LogFields returns the fields carried by the error, without the ones of
+// the errors it wraps (see aloig.ErrorFields)
+func (e *Error) LogFields() map[string]interface{} {
+	return e.fields
+}
+
+// Severity returns the level the error should be logged at
+func (e *Error) Severity() logrus.Level {
+	return e.severity
+}
+
+// Fields returns the fields carried by err and the errors it wraps
+func Fields(err error) map[string]interface{} {
+	return aloig.ErrorFields(err)
+}
+
+// Severity returns the severity of err, ErrorLevel if it carries none
+func Severity(err error) logrus.Level {
+	return aloig.ErrorSeverity(err)
+}
@@ -0,0 +1,67 @@
+package aloigerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aloi-tech/aloig_go/aloig"
+	"github.com/aloi-tech/aloig_go/aloig/aloigtest"
+	"github.com/sirupsen/logrus"
+)
+
+// TestError tests messages, wrapping, fields and severity
+func TestError(t *testing.T) {
+	root := errors.New("connection reset")
+	inner := Wrap(root, "query orders").With("table", "orders").WithSeverity(logrus.WarnLevel)
+	outer := Wrap(fmt.Errorf("retry: %w", inner), "sync").WithFields(map[string]interface{}{"table": "outer", "tenant": "acme"})
+
+	if outer.Error() != "sync: retry: query orders: connection reset" {
+		t.Errorf("Unexpected message: %s", outer.Error())
+	}
+	if !errors.Is(outer, root) {
+		t.Error("Expected the error to wrap the root cause")
+	}
+	if Severity(outer) != logrus.WarnLevel {
+		t.Errorf("Expected the wrapped severity to be kept, got %s", Severity(outer))
+	}
+	if Severity(root) != logrus.ErrorLevel {
+		t.Errorf("Expected error severity by default, got %s", Severity(root))
+	}
+
+	fields := Fields(outer)
+	if fields["table"] != "outer" || fields["tenant"] != "acme" || len(fields) != 2 {
+		t.Errorf("Expected merged fields with outer precedence, got %v", fields)
+	}
+
+	// With returns copies
+	base := New("base")
+	base.With("key", "value")
+	if len(base.LogFields()) != 0 {
+		t.Errorf("Expected With not to modify the original error, got %v", base.LogFields())
+	}
+}
+
+// TestErrorFieldsLogged tests that the error fields are merged into log entries
+func TestErrorFieldsLogged(t *testing.T) {
+	logger, logs := aloigtest.NewObservedLogger()
+	ctx := aloig.WithTraceID(context.Background(), "trace-err")
+
+	err := New("payment declined").With("order_id", "o-1")
+	logger.ErrorContext(ctx, err)
+	logger.WithError(Wrap(err, "checkout")).Warn("checkout failed")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Fields["order_id"] != "o-1" {
+			t.Errorf("Expected order_id to be merged, got %v", entry.Fields)
+		}
+	}
+	if entries[0].Fields["trace_id"] != "trace-err" {
+		t.Errorf("Expected the context fields, got %v", entries[0].Fields)
+	}
+}
@@ -0,0 +1,94 @@
+package aloigtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields are the fields expected in a captured entry
+type Fields map[string]interface{}
+
+// TestingT is the subset of *testing.T used by the assertion helpers
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertLogged checks that an entry was logged at level whose message
+// contains msg and that has all the given fields (entry or context fields).
+// It reports the captured entries when none matches.
+func AssertLogged(t TestingT, logs *ObservedLogs, level logrus.Level, msg string, fields Fields) bool {
+	t.Helper()
+
+	for _, entry := range logs.All() {
+		if entry.Level == level && strings.Contains(entry.Message, msg) && hasFields(entry, fields) {
+			return true
+		}
+	}
+
+	t.Errorf("Expected a %s entry containing %q with fields %v, got:\n%s", level, msg, fields, describe(logs.All()))
+	return false
+}
+
+// AssertNotLogged checks that no entry was logged at level whose message
+// contains msg
+func AssertNotLogged(t TestingT, logs *ObservedLogs, level logrus.Level, msg string) bool {
+	t.Helper()
+
+	for _, entry := range logs.All() {
+		if entry.Level == level && strings.Contains(entry.Message, msg) {
+			t.Errorf("Expected no %s entry containing %q, got:\n%s", level, msg, describe([]LoggedEntry{entry}))
+			return false
+		}
+	}
+	return true
+}
+
+// AssertNoneAbove checks that no entry is more severe than level, e.g.
+// AssertNoneAbove(t, logs, logrus.WarnLevel) fails on errors but allows warnings
+func AssertNoneAbove(t TestingT, logs *ObservedLogs, level logrus.Level) bool {
+	t.Helper()
+
+	var above []LoggedEntry
+	for _, entry := range logs.All() {
+		if entry.Level < level {
+			above = append(above, entry)
+		}
+	}
+
+	if len(above) > 0 {
+		t.Errorf("Expected no entries above %s, got:\n%s", level, describe(above))
+		return false
+	}
+	return true
+}
+
+// hasFields checks if the entry has all the expected fields
+func hasFields(entry LoggedEntry, fields Fields) bool {
+	for key, expected := range fields {
+		value, ok := entry.Fields[key]
+		if !ok {
+			value, ok = entry.ContextFields[key]
+		}
+		if !ok || !reflect.DeepEqual(value, expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe renders entries for failure messages
+func describe(entries []LoggedEntry) string {
+	if len(entries) == 0 {
+		return "  (no entries)"
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "  [%s] %s %v\n", entry.Level, entry.Message, entry.Fields)
+	}
+	return b.String()
+}
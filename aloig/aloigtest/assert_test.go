@@ -0,0 +1,60 @@
+package aloigtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aloi-tech/aloig_go/aloig"
+	"github.com/sirupsen/logrus"
+)
+
+// recordingT is a TestingT that records failures instead of failing the test
+type recordingT struct {
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// TestAssertLogged tests matching entries by level, message and fields
+func TestAssertLogged(t *testing.T) {
+	logger, logs := NewObservedLogger()
+	ctx := aloig.WithTraceID(context.Background(), "trace-123")
+	logger.WithField("order_id", "o-1").ErrorContext(ctx, "payment declined by provider")
+
+	AssertLogged(t, logs, logrus.ErrorLevel, "payment declined", Fields{"trace_id": "trace-123", "order_id": "o-1"})
+
+	rt := &recordingT{}
+	if AssertLogged(rt, logs, logrus.ErrorLevel, "payment declined", Fields{"trace_id": "other"}) {
+		t.Error("Expected assertion with a wrong field value to fail")
+	}
+	if AssertLogged(rt, logs, logrus.WarnLevel, "payment declined", nil) {
+		t.Error("Expected assertion with a wrong level to fail")
+	}
+	if len(rt.failures) != 2 {
+		t.Errorf("Expected 2 reported failures, got %d", len(rt.failures))
+	}
+}
+
+// TestAssertNoneAbove tests detecting entries more severe than a level
+func TestAssertNoneAbove(t *testing.T) {
+	logger, logs := NewObservedLogger()
+	logger.Info("all good")
+	logger.Warn("slow response")
+
+	AssertNoneAbove(t, logs, logrus.WarnLevel)
+	AssertNotLogged(t, logs, logrus.ErrorLevel, "slow response")
+
+	logger.Error("failure")
+	rt := &recordingT{}
+	if AssertNoneAbove(rt, logs, logrus.WarnLevel) || len(rt.failures) != 1 {
+		t.Error("Expected assertion to fail when an error was logged")
+	}
+	if AssertNotLogged(rt, logs, logrus.ErrorLevel, "failure") {
+		t.Error("Expected AssertNotLogged to fail when the entry was logged")
+	}
+}
@@ -0,0 +1,149 @@
+// Package aloigtest provides helpers to test code that logs through aloig.
+//
+// NewObservedLogger returns a Logger that keeps every entry in memory so
+// tests can assert on levels, messages, fields and context fields instead of
+// parsing formatted output.
+package aloigtest
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aloi-tech/aloig_go/aloig"
+	"github.com/sirupsen/logrus"
+)
+
+// LoggedEntry is an entry captured by an observed logger
+type LoggedEntry struct {
+	Time    time.Time
+	Level   logrus.Level
+	Message string
+
+	// Fields are all the fields of the entry, including context fields
+	Fields map[string]interface{}
+
+	// ContextFields are the fields extracted from the entry context
+	// (trace_id, request_id, user_id, session_id)
+	ContextFields map[string]interface{}
+}
+
+// ObservedLogs is a concurrency-safe store of captured entries
+type ObservedLogs struct {
+	mu      sync.RWMutex
+	entries []LoggedEntry
+}
+
+// NewObservedLogger returns a logger at trace level that records every entry
+// in the returned store. Fatal entries are recorded without exiting.
+func NewObservedLogger() (aloig.Logger, *ObservedLogs) {
+	logs := &ObservedLogs{}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.ExitFunc = func(int) {}
+	for _, hook := range aloig.StandardHooks() {
+		logger.AddHook(hook)
+	}
+	logger.AddHook(&observerHook{logs: logs})
+
+	return aloig.FromLogrus(logger), logs
+}
+
+// Len returns the number of captured entries
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.entries)
+}
+
+// All returns a copy of the captured entries
+func (o *ObservedLogs) All() []LoggedEntry {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return append([]LoggedEntry{}, o.entries...)
+}
+
+// TakeAll returns the captured entries and clears the store
+func (o *ObservedLogs) TakeAll() []LoggedEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := o.entries
+	o.entries = nil
+	return entries
+}
+
+// FilterLevel returns the entries logged at the given level
+func (o *ObservedLogs) FilterLevel(level logrus.Level) *ObservedLogs {
+	return o.filter(func(e LoggedEntry) bool {
+		return e.Level == level
+	})
+}
+
+// FilterMessage returns the entries whose message contains substr
+func (o *ObservedLogs) FilterMessage(substr string) *ObservedLogs {
+	return o.filter(func(e LoggedEntry) bool {
+		return strings.Contains(e.Message, substr)
+	})
+}
+
+// FilterField returns the entries having the field with the given value
+func (o *ObservedLogs) FilterField(key string, value interface{}) *ObservedLogs {
+	return o.filter(func(e LoggedEntry) bool {
+		v, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+// filter returns a new store with the entries matching keep
+func (o *ObservedLogs) filter(keep func(LoggedEntry) bool) *ObservedLogs {
+	filtered := &ObservedLogs{}
+	for _, entry := range o.All() {
+		if keep(entry) {
+			filtered.entries = append(filtered.entries, entry)
+		}
+	}
+	return filtered
+}
+
+// add records an entry
+func (o *ObservedLogs) add(entry LoggedEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, entry)
+}
+
+// observerHook is a hook that records entries in an ObservedLogs store
+type observerHook struct {
+	logs *ObservedLogs
+}
+
+// Levels returns the levels to which the hook will be applied
+func (h *observerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire records the entry
+func (h *observerHook) Fire(entry *logrus.Entry) error {
+	fields := make(map[string]interface{}, len(entry.Data))
+	for k, v := range entry.Data {
+		fields[k] = v
+	}
+
+	contextFields := map[string]interface{}{}
+	if entry.Context != nil {
+		contextFields = aloig.ExtractContextFields(entry.Context)
+	}
+
+	h.logs.add(LoggedEntry{
+		Time:          entry.Time,
+		Level:         entry.Level,
+		Message:       entry.Message,
+		Fields:        fields,
+		ContextFields: contextFields,
+	})
+	return nil
+}
@@ -0,0 +1,60 @@
+package aloigtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aloi-tech/aloig_go/aloig"
+	"github.com/sirupsen/logrus"
+)
+
+// TestNewObservedLogger tests that entries are captured with their fields
+func TestNewObservedLogger(t *testing.T) {
+	logger, logs := NewObservedLogger()
+
+	ctx := aloig.WithTraceID(context.Background(), "trace-123")
+	logger.WithField("order_id", "o-1").InfoContext(ctx, "order created")
+	logger.WithError(errors.New("boom")).Error("order failed")
+	logger.Warn("retrying")
+
+	if logs.Len() != 3 {
+		t.Fatalf("Expected 3 entries, got %d", logs.Len())
+	}
+
+	entry := logs.All()[0]
+	if entry.Level != logrus.InfoLevel || entry.Message != "order created" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Fields["order_id"] != "o-1" {
+		t.Errorf("Expected order_id field, got %v", entry.Fields)
+	}
+	if entry.ContextFields["trace_id"] != "trace-123" {
+		t.Errorf("Expected trace_id context field, got %v", entry.ContextFields)
+	}
+
+	if n := logs.FilterLevel(logrus.ErrorLevel).Len(); n != 1 {
+		t.Errorf("Expected 1 error entry, got %d", n)
+	}
+	if n := logs.FilterMessage("order").Len(); n != 2 {
+		t.Errorf("Expected 2 order entries, got %d", n)
+	}
+	if n := logs.FilterField("order_id", "o-1").Len(); n != 1 {
+		t.Errorf("Expected 1 entry for order o-1, got %d", n)
+	}
+
+	if taken := logs.TakeAll(); len(taken) != 3 || logs.Len() != 0 {
+		t.Errorf("Expected TakeAll to return 3 entries and clear the store, got %d/%d", len(taken), logs.Len())
+	}
+}
+
+// TestObservedLoggerFatal tests that fatal entries are recorded without exiting
+func TestObservedLoggerFatal(t *testing.T) {
+	logger, logs := NewObservedLogger()
+
+	logger.Fatal("fatal entry")
+
+	if logs.FilterLevel(logrus.FatalLevel).Len() != 1 {
+		t.Error("Expected fatal entry to be recorded")
+	}
+}
@@ -0,0 +1,362 @@
+package aloig
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultAsyncBufferSize is the number of entries buffered by an
+	// AsyncWriter when no size is given
+	DefaultAsyncBufferSize = 1024
+
+	// DefaultDropReportInterval is the interval between the log lines
+	// reporting dropped entries
+	DefaultDropReportInterval = 10 * time.Second
+
+	// defaultSampleEvery is the fraction of entries kept under pressure by
+	// BackpressureSample when no value is given
+	defaultSampleEvery = 10
+)
+
+// BackpressurePolicy decides what an AsyncWriter does with an entry when its
+// buffer can't keep up with the logging rate
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock makes the logging call wait for room in the buffer,
+	// so no entry is lost but a slow output slows the application down
+	BackpressureBlock BackpressurePolicy = iota
+
+	// BackpressureDropNew drops the entry being logged when the buffer is
+	// full
+	BackpressureDropNew
+
+	// BackpressureDropOldest drops the oldest buffered entry to make room
+	// for the entry being logged
+	BackpressureDropOldest
+
+	// BackpressureSample keeps one in SampleEvery entries once the buffer is
+	// half full, and drops the entry being logged when it is full
+	BackpressureSample
+)
+
+// backpressurePolicyNames are the names of the backpressure policies
+var backpressurePolicyNames = []string{"block", "drop_new", "drop_oldest", "sample"}
+
+// String returns the name of the policy
+func (p BackpressurePolicy) String() string {
+	if p < 0 || int(p) >= len(backpressurePolicyNames) {
+		return fmt.Sprintf("BackpressurePolicy(%d)", int(p))
+	}
+	return backpressurePolicyNames[p]
+}
+
+// ParseBackpressurePolicy returns the policy with the given name ("block",
+// "drop_new", "drop_oldest" or "sample")
+func ParseBackpressurePolicy(name string) (BackpressurePolicy, error) {
+	for i, policy := range backpressurePolicyNames {
+		if strings.EqualFold(name, policy) {
+			return BackpressurePolicy(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown backpressure policy %q", name)
+}
+
+// AsyncOptions configures an AsyncWriter
+type AsyncOptions struct {
+	// BufferSize is the number of buffered entries (0 uses
+	// DefaultAsyncBufferSize)
+	BufferSize int
+
+	// Policy applies when the buffer can't keep up
+	Policy BackpressurePolicy
+
+	// SampleEvery is the fraction of entries kept under pressure by
+	// BackpressureSample, e.g. 10 keeps one in ten (0 uses 10)
+	SampleEvery int
+
+	// DropReportInterval is the interval between the log lines reporting
+	// dropped entries (0 uses DefaultDropReportInterval)
+	DropReportInterval time.Duration
+
+	// Logger logs the dropped entries reports; nil uses the singleton
+	Logger Logger
+}
+
+// asyncItem is an entry queued in an AsyncWriter, or a flush request when
+// flushed is set
+type asyncItem struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+func (item asyncItem) flushRequest() chan struct{} { return item.flushed }
+
+// queuedItem is an item of the queues of AsyncWriter and AsyncHook: an
+// entry, or a flush request closed once the entries before it are taken
+type queuedItem interface {
+	flushRequest() chan struct{}
+}
+
+// backpressure applies a BackpressurePolicy to the queues of AsyncWriter and
+// AsyncHook, counting the dropped entries
+type backpressure struct {
+	pressured   uint64
+	dropped     uint64
+	policy      BackpressurePolicy
+	sampleEvery uint64
+}
+
+// newBackpressure returns the backpressure of policy, keeping one in
+// sampleEvery entries under pressure with BackpressureSample (0 uses 10)
+func newBackpressure(policy BackpressurePolicy, sampleEvery int) backpressure {
+	if sampleEvery <= 0 {
+		sampleEvery = defaultSampleEvery
+	}
+	return backpressure{policy: policy, sampleEvery: uint64(sampleEvery)}
+}
+
+// enqueue queues item according to the policy of b
+func enqueue[T queuedItem](b *backpressure, queue chan T, item T) {
+	switch b.policy {
+	case BackpressureDropNew:
+		tryEnqueue(b, queue, item)
+	case BackpressureDropOldest:
+		enqueueDroppingOldest(b, queue, item)
+	case BackpressureSample:
+		if len(queue) >= cap(queue)/2 && atomic.AddUint64(&b.pressured, 1)%b.sampleEvery != 0 {
+			atomic.AddUint64(&b.dropped, 1)
+			return
+		}
+		tryEnqueue(b, queue, item)
+	default:
+		queue <- item
+	}
+}
+
+// tryEnqueue queues item, dropping it when the queue is full
+func tryEnqueue[T queuedItem](b *backpressure, queue chan T, item T) {
+	select {
+	case queue <- item:
+	default:
+		atomic.AddUint64(&b.dropped, 1)
+	}
+}
+
+// enqueueDroppingOldest queues item, dropping the oldest entries until there
+// is room for it
+func enqueueDroppingOldest[T queuedItem](b *backpressure, queue chan T, item T) {
+	for {
+		select {
+		case queue <- item:
+			return
+		default:
+		}
+
+		select {
+		case oldest := <-queue:
+			if flushed := oldest.flushRequest(); flushed != nil {
+				// The entries before the request were already taken
+				close(flushed)
+			} else {
+				atomic.AddUint64(&b.dropped, 1)
+			}
+		default:
+		}
+	}
+}
+
+// AsyncWriter writes entries to its output from a background goroutine, so
+// logging calls don't wait for slow outputs. Its policy decides what happens
+// when the buffer is full. Dropped entries are counted (see Dropped) and
+// reported at warning level with "log entries dropped" every
+// DropReportInterval. Close flushes the buffer and stops the goroutine.
+type AsyncWriter struct {
+	backpressure
+
+	out            io.Writer
+	reportInterval time.Duration
+	logger         Logger
+
+	// mu guards closed; writers hold it for reading while they enqueue
+	mu     sync.RWMutex
+	closed bool
+	queue  chan asyncItem
+	done   chan struct{}
+
+	reported uint64
+}
+
+// NewAsyncWriter creates an AsyncWriter writing to out and starts its
+// goroutine
+func NewAsyncWriter(out io.Writer, options AsyncOptions) *AsyncWriter {
+	size := options.BufferSize
+	if size <= 0 {
+		size = DefaultAsyncBufferSize
+	}
+	interval := options.DropReportInterval
+	if interval <= 0 {
+		interval = DefaultDropReportInterval
+	}
+
+	w := &AsyncWriter{
+		backpressure:   newBackpressure(options.Policy, options.SampleEvery),
+		out:            out,
+		reportInterval: interval,
+		logger:         options.Logger,
+		queue:          make(chan asyncItem, size),
+		done:           make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p according to the policy. Entries written after
+// Close are written synchronously.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.out.Write(p)
+	}
+
+	// logrus reuses its buffers once Write returns
+	item := asyncItem{data: make([]byte, len(p))}
+	copy(item.data, p)
+
+	enqueue(&w.backpressure, w.queue, item)
+	return len(p), nil
+}
+
+// Dropped returns the number of entries dropped since the writer was created
+func (w *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Flush waits until the entries queued before the call are written,
+// blocking for at most timeout. It returns false if the timeout was reached.
+func (w *AsyncWriter) Flush(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	flushed := make(chan struct{})
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return true
+	}
+	select {
+	case w.queue <- asyncItem{flushed: flushed}:
+		w.mu.RUnlock()
+	case <-timer.C:
+		w.mu.RUnlock()
+		return false
+	}
+
+	select {
+	case <-flushed:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// Close writes the buffered entries and stops the goroutine
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// run writes the queued entries and reports the dropped ones until the
+// writer is closed
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case item, ok := <-w.queue:
+			if !ok {
+				w.reportDropped()
+				return
+			}
+			if item.flushed != nil {
+				close(item.flushed)
+				continue
+			}
+			// There is nobody to return write errors to
+			_, _ = w.out.Write(item.data)
+		case <-ticker.C:
+			w.reportDropped()
+		}
+	}
+}
+
+// reportDropped logs the entries dropped since the last report
+func (w *AsyncWriter) reportDropped() {
+	dropped := w.Dropped()
+	if dropped == w.reported {
+		return
+	}
+
+	logger := w.logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+	logger.WithFields(map[string]interface{}{
+		"dropped":       dropped - w.reported,
+		"dropped_total": dropped,
+		"policy":        w.policy.String(),
+	}).Warn("log entries dropped")
+	w.reported = dropped
+}
+
+var (
+	asyncMu      sync.Mutex
+	asyncWriters []*AsyncWriter
+)
+
+// registerAsyncWriter makes the writer flushed by FlushLogs
+func registerAsyncWriter(w *AsyncWriter) {
+	asyncMu.Lock()
+	defer asyncMu.Unlock()
+	asyncWriters = append(asyncWriters, w)
+}
+
+// unregisterAsyncWriter stops flushing the writer with FlushLogs
+func unregisterAsyncWriter(w *AsyncWriter) {
+	asyncMu.Lock()
+	defer asyncMu.Unlock()
+	asyncWriters = removeItem(asyncWriters, w)
+}
+
+// FlushLogs waits until the entries buffered by the loggers created with
+// Config.AsyncBufferSize are written, and those buffered by their hooks with
+// Config.AsyncHookWorkers are fired, blocking for at most timeout. It returns
+// false if the timeout was reached.
+func FlushLogs(timeout time.Duration) bool {
+	asyncMu.Lock()
+	writers := append([]*AsyncWriter(nil), asyncWriters...)
+	asyncMu.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	flushed := flushAsyncHooks(deadline)
+	for _, w := range writers {
+		flushed = w.Flush(time.Until(deadline)) && flushed
+	}
+	return flushed
+}
@@ -0,0 +1,271 @@
+package aloig
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultAsyncHookWorkers is the number of workers of an AsyncHook when no
+// number is given
+const DefaultAsyncHookWorkers = 4
+
+// AsyncHookOptions configures an AsyncHook
+type AsyncHookOptions struct {
+	// Workers is the number of goroutines firing the hook (0 uses
+	// DefaultAsyncHookWorkers)
+	Workers int
+
+	// BufferSize is the number of entries buffered per worker (0 uses
+	// DefaultAsyncBufferSize)
+	BufferSize int
+
+	// Policy applies when the buffer of a worker is full
+	Policy BackpressurePolicy
+
+	// SampleEvery is the fraction of entries kept under pressure by
+	// BackpressureSample, e.g. 10 keeps one in ten (0 uses 10)
+	SampleEvery int
+}
+
+// asyncHookItem is an entry queued in an AsyncHook, or a flush request when
+// flushed is set
+type asyncHookItem struct {
+	entry   *logrus.Entry
+	flushed chan struct{}
+}
+
+func (item asyncHookItem) flushRequest() chan struct{} { return item.flushed }
+
+// AsyncHook fires a hook from a pool of workers, so a slow hook (e.g. Sentry
+// behind a slow network) doesn't add latency to the logging calls. The
+// entries of a trace ID are always fired by the same worker, in order. Each
+// worker has a bounded buffer whose policy decides what happens when it is
+// full; dropped entries are counted (see Dropped). The hook gets a copy of
+// the entries, fired after the logging call returned.
+type AsyncHook struct {
+	backpressure
+
+	hook logrus.Hook
+
+	// mu guards closed; loggers hold it for reading while they enqueue
+	mu     sync.RWMutex
+	closed bool
+	queues []chan asyncHookItem
+	done   sync.WaitGroup
+
+	next uint64
+}
+
+// NewAsyncHook creates an AsyncHook firing hook and starts its workers
+func NewAsyncHook(hook logrus.Hook, options AsyncHookOptions) *AsyncHook {
+	workers := options.Workers
+	if workers <= 0 {
+		workers = DefaultAsyncHookWorkers
+	}
+	size := options.BufferSize
+	if size <= 0 {
+		size = DefaultAsyncBufferSize
+	}
+
+	h := &AsyncHook{
+		backpressure: newBackpressure(options.Policy, options.SampleEvery),
+		hook:         hook,
+		queues:       make([]chan asyncHookItem, workers),
+	}
+	h.done.Add(workers)
+	for i := range h.queues {
+		h.queues[i] = make(chan asyncHookItem, size)
+		go h.run(h.queues[i])
+	}
+	return h
+}
+
+// Levels returns the levels of the hook
+func (h *AsyncHook) Levels() []logrus.Level {
+	return h.hook.Levels()
+}
+
+// Fire queues a copy of the entry according to the policy. Panic entries,
+// which may crash the process once logged, and entries fired after Close
+// are fired synchronously.
+func (h *AsyncHook) Fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed || entry.Level == logrus.PanicLevel {
+		return h.hook.Fire(entry)
+	}
+
+	item := asyncHookItem{entry: copyEntry(entry)}
+	enqueue(&h.backpressure, h.queues[h.worker(entry)], item)
+	return nil
+}
+
+// worker returns the index of the worker firing the entry: the one of its
+// trace ID, or the next one when it has none
+func (h *AsyncHook) worker(entry *logrus.Entry) int {
+	traceID, _ := entry.Data[string(TraceIDKey)].(string)
+	if traceID == "" {
+		traceID = GetTraceID(entry.Context)
+	}
+	if traceID == "" {
+		return int(atomic.AddUint64(&h.next, 1) % uint64(len(h.queues)))
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(traceID))
+	return int(hash.Sum32() % uint32(len(h.queues)))
+}
+
+// Dropped returns the number of entries dropped since the hook was created
+func (h *AsyncHook) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// queued returns the number of entries waiting and the number of entries
+// the workers can buffer
+func (h *AsyncHook) queued() (depth, capacity int) {
+	for _, queue := range h.queues {
+		depth += len(queue)
+		capacity += cap(queue)
+	}
+	return depth, capacity
+}
+
+// Flush waits until the entries queued before the call are fired, blocking
+// for at most timeout. It returns false if the timeout was reached.
+func (h *AsyncHook) Flush(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		return true
+	}
+	requests := make([]chan struct{}, 0, len(h.queues))
+	for _, queue := range h.queues {
+		flushed := make(chan struct{})
+		select {
+		case queue <- asyncHookItem{flushed: flushed}:
+			requests = append(requests, flushed)
+		case <-timer.C:
+			h.mu.RUnlock()
+			return false
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, flushed := range requests {
+		select {
+		case <-flushed:
+		case <-timer.C:
+			return false
+		}
+	}
+	return true
+}
+
+// Close fires the buffered entries and stops the workers
+func (h *AsyncHook) Close() error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		for _, queue := range h.queues {
+			close(queue)
+		}
+	}
+	h.mu.Unlock()
+
+	h.done.Wait()
+	return nil
+}
+
+// run fires the entries of queue until the hook is closed
+func (h *AsyncHook) run(queue chan asyncHookItem) {
+	defer h.done.Done()
+	for item := range queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		// There is nobody to return the errors to; the hooks wrapped by
+		// NewLogger handle them according to the HookErrorPolicy
+		_ = h.hook.Fire(item.entry)
+	}
+}
+
+// copyEntry returns a copy of the entry and its fields that can be used
+// after the logging call returned
+func copyEntry(entry *logrus.Entry) *logrus.Entry {
+	copied := entry.Dup()
+	copied.Level, copied.Message, copied.Caller = entry.Level, entry.Message, entry.Caller
+	return copied
+}
+
+var (
+	asyncHooksMu sync.Mutex
+	asyncHooks   []*AsyncHook
+)
+
+// registerAsyncHook makes the hook flushed by FlushLogs and FlushSentry
+func registerAsyncHook(h *AsyncHook) {
+	asyncHooksMu.Lock()
+	defer asyncHooksMu.Unlock()
+	asyncHooks = append(asyncHooks, h)
+}
+
+// unregisterAsyncHook stops flushing the hook with FlushLogs and
+// FlushSentry
+func unregisterAsyncHook(h *AsyncHook) {
+	asyncHooksMu.Lock()
+	defer asyncHooksMu.Unlock()
+	asyncHooks = removeItem(asyncHooks, h)
+}
+
+// flushAsyncHooks flushes the registered hooks, blocking until deadline at
+// most. It returns false if the deadline was reached.
+func flushAsyncHooks(deadline time.Time) bool {
+	asyncHooksMu.Lock()
+	hooks := append([]*AsyncHook(nil), asyncHooks...)
+	asyncHooksMu.Unlock()
+
+	flushed := true
+	for _, h := range hooks {
+		flushed = h.Flush(time.Until(deadline)) && flushed
+	}
+	return flushed
+}
+
+// runSinkHooksAsync replaces the hooks of logger writing to a sink (Sentry
+// and syslog), as wrapped by countHookErrors, with AsyncHooks closed with
+// resources
+func runSinkHooksAsync(logger *logrus.Logger, options AsyncHookOptions, resources *loggerResources) {
+	hooks := make(logrus.LevelHooks, len(logger.Hooks))
+	async := make(map[*sinkHealth]*AsyncHook)
+	for level, levelHooks := range logger.Hooks {
+		for _, hook := range levelHooks {
+			if stats, ok := hook.(statsHook); ok && stats.sink != nil {
+				if async[stats.sink] == nil {
+					async[stats.sink] = NewAsyncHook(stats, options)
+				}
+				hook = async[stats.sink]
+			}
+			hooks[level] = append(hooks[level], hook)
+		}
+	}
+	logger.ReplaceHooks(hooks)
+
+	for _, h := range async {
+		h := h
+		registerAsyncHook(h)
+		// Fire the buffered entries before the other handlers flush Sentry
+		resources.onClose(func() {
+			_ = h.Close()
+			unregisterAsyncHook(h)
+		}, true)
+	}
+}
@@ -0,0 +1,124 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recordingHook records the messages of the entries it fires, blocking
+// until its gate is opened
+type recordingHook struct {
+	mu       sync.Mutex
+	messages map[string][]string
+	gate     chan struct{}
+}
+
+func newRecordingHook() *recordingHook {
+	return &recordingHook{messages: make(map[string][]string), gate: make(chan struct{})}
+}
+
+func (h *recordingHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h *recordingHook) Fire(entry *logrus.Entry) error {
+	<-h.gate
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	traceID := GetTraceID(entry.Context)
+	h.messages[traceID] = append(h.messages[traceID], entry.Message)
+	return nil
+}
+
+func (h *recordingHook) fired(traceID string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.messages[traceID]
+}
+
+// TestAsyncHook tests that a slow hook doesn't block the logging calls and
+// fires the entries of each trace in order
+func TestAsyncHook(t *testing.T) {
+	hook := newRecordingHook()
+	async := NewAsyncHook(hook, AsyncHookOptions{Workers: 3})
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(async)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 20; i++ {
+			for _, traceID := range []string{"trace-a", "trace-b"} {
+				logger.InfoContext(WithTraceID(context.Background(), traceID), fmt.Sprint(i))
+			}
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the logging calls not to wait for the hook")
+	}
+
+	close(hook.gate)
+	if !async.Flush(time.Second) {
+		t.Fatal("Expected the flush to complete")
+	}
+	for _, traceID := range []string{"trace-a", "trace-b"} {
+		fired := hook.fired(traceID)
+		if len(fired) != 20 {
+			t.Fatalf("Expected 20 entries for %s, got %v", traceID, fired)
+		}
+		for i, message := range fired {
+			if message != fmt.Sprint(i) {
+				t.Errorf("Expected the entries of %s in order, got %v", traceID, fired)
+				break
+			}
+		}
+	}
+	_ = async.Close()
+}
+
+// TestAsyncHookDropNew tests that entries are dropped when the buffer is
+// full with BackpressureDropNew
+func TestAsyncHookDropNew(t *testing.T) {
+	hook := newRecordingHook()
+	async := NewAsyncHook(hook, AsyncHookOptions{Workers: 1, BufferSize: 2, Policy: BackpressureDropNew})
+
+	for i := 0; i < 10; i++ {
+		_ = async.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: fmt.Sprint(i), Data: logrus.Fields{}})
+	}
+	close(hook.gate)
+	_ = async.Close()
+
+	// The worker may have taken the first entry before the buffer filled
+	if fired := len(hook.fired("")); fired < 2 || fired > 3 || async.Dropped() != uint64(10-fired) {
+		t.Errorf("Expected the entries beyond the buffer to be dropped, got %d fired and %d dropped", fired, async.Dropped())
+	}
+}
+
+// TestRunSinkHooksAsync tests that only the hooks writing to a sink are
+// made asynchronous
+func TestRunSinkHooksAsync(t *testing.T) {
+	logger := logrus.New()
+	logger.AddHook(&SyslogHook{})
+	logger.AddHook(&FieldsHook{})
+	countHookErrors(logger, nil)
+	resources := newLoggerResources()
+	defer resources.close()
+	runSinkHooksAsync(logger, AsyncHookOptions{Workers: 1}, resources)
+
+	var wrapped, direct int
+	for _, hook := range logger.Hooks[logrus.InfoLevel] {
+		if _, ok := hook.(*AsyncHook); ok {
+			wrapped++
+		} else {
+			direct++
+		}
+	}
+	if wrapped != 1 || direct != 1 {
+		t.Errorf("Expected the syslog hook only to be asynchronous, got %d async and %d sync hooks", wrapped, direct)
+	}
+}
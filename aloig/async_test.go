@@ -0,0 +1,164 @@
+package aloig
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// gatedWriter records the writes, blocking them until it is opened
+type gatedWriter struct {
+	mu      sync.Mutex
+	lines   []string
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{started: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func (w *gatedWriter) written() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.Join(w.lines, ",")
+}
+
+// fillAsyncWriter writes "1" and waits for the goroutine to be stuck writing
+// it, then writes the other entries
+func fillAsyncWriter(t *testing.T, out *gatedWriter, w *AsyncWriter, entries ...string) {
+	if _, err := w.Write([]byte("1")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	<-out.started
+	for _, entry := range entries {
+		if _, err := w.Write([]byte(entry)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+// TestAsyncWriterPolicies tests what each policy keeps when the buffer is full
+func TestAsyncWriterPolicies(t *testing.T) {
+	testCases := []struct {
+		policy   BackpressurePolicy
+		size     int
+		entries  []string
+		expected string
+		dropped  uint64
+	}{
+		{BackpressureDropNew, 2, []string{"2", "3", "4", "5"}, "1,2,3", 2},
+		{BackpressureDropOldest, 2, []string{"2", "3", "4", "5"}, "1,4,5", 2},
+		{BackpressureSample, 4, []string{"2", "3", "4", "5", "6", "7"}, "1,2,3,5,7", 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			logger, _ := newBufferLogger(logrus.InfoLevel)
+			out := newGatedWriter()
+			w := NewAsyncWriter(out, AsyncOptions{BufferSize: tc.size, Policy: tc.policy, SampleEvery: 2, Logger: logger})
+
+			fillAsyncWriter(t, out, w, tc.entries...)
+			if dropped := w.Dropped(); dropped != tc.dropped {
+				t.Errorf("Expected %d dropped entries, got %d", tc.dropped, dropped)
+			}
+
+			close(out.gate)
+			w.Close()
+			if written := out.written(); written != tc.expected {
+				t.Errorf("Expected %q to be written, got %q", tc.expected, written)
+			}
+		})
+	}
+}
+
+// TestAsyncWriterBlock tests that the block policy waits for room instead of
+// dropping entries
+func TestAsyncWriterBlock(t *testing.T) {
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, AsyncOptions{BufferSize: 1, Policy: BackpressureBlock})
+
+	fillAsyncWriter(t, out, w, "2")
+	written := make(chan struct{})
+	go func() {
+		w.Write([]byte("3"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("Expected the write to block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(out.gate)
+	<-written
+	w.Close()
+	if written := out.written(); written != "1,2,3" {
+		t.Errorf("Expected every entry to be written, got %q", written)
+	}
+	if dropped := w.Dropped(); dropped != 0 {
+		t.Errorf("Expected no dropped entries, got %d", dropped)
+	}
+}
+
+// TestAsyncWriterReportsDrops tests that dropped entries are logged
+func TestAsyncWriterReportsDrops(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, AsyncOptions{BufferSize: 1, Policy: BackpressureDropNew, Logger: logger})
+
+	fillAsyncWriter(t, out, w, "2", "3", "4")
+	close(out.gate)
+	w.Close()
+
+	for _, expected := range []string{`msg="log entries dropped"`, "dropped=2", "dropped_total=2", "policy=drop_new"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in the report, got: %s", expected, buf.String())
+		}
+	}
+}
+
+// TestAsyncLogger tests loggers created with an async buffer
+func TestAsyncLogger(t *testing.T) {
+	logger := NewLogger(Config{Environment: "dev", Level: logrus.InfoLevel, PlainDevFormatter: true, AsyncBufferSize: 16})
+	out := newGatedWriter()
+	close(out.gate)
+	asyncWriter := logger.(*logrusLogger).logger.Out.(*AsyncWriter)
+	asyncWriter.out = out
+
+	logger.Info("first")
+	logger.Info("second")
+	if !FlushLogs(time.Second) {
+		t.Fatal("Expected the entries to be flushed")
+	}
+	if written := out.written(); !strings.Contains(written, "first") || !strings.Contains(written, "second") {
+		t.Errorf("Expected the entries to be written, got %q", written)
+	}
+	asyncWriter.Close()
+}
+
+// TestParseBackpressurePolicy tests parsing policy names
+func TestParseBackpressurePolicy(t *testing.T) {
+	for _, policy := range []BackpressurePolicy{BackpressureBlock, BackpressureDropNew, BackpressureDropOldest, BackpressureSample} {
+		parsed, err := ParseBackpressurePolicy(strings.ToUpper(policy.String()))
+		if err != nil || parsed != policy {
+			t.Errorf("Expected %v, got %v (%v)", policy, parsed, err)
+		}
+	}
+	if _, err := ParseBackpressurePolicy("drop_everything"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
@@ -0,0 +1,146 @@
+package aloig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Fields added by the AWS metadata enrichment
+const (
+	AWSInstanceIDField       = "aws_instance_id"
+	AWSAvailabilityZoneField = "aws_availability_zone"
+	AWSTaskARNField          = "aws_task_arn"
+	AWSClusterField          = "aws_cluster"
+)
+
+// awsIMDSURL is the address of the EC2 instance metadata service
+const awsIMDSURL = "http://169.254.169.254"
+
+// awsMetadataSource queries the ECS task metadata endpoint when ecsURL is
+// set, and the EC2 instance metadata service otherwise
+type awsMetadataSource struct {
+	imdsURL string
+	ecsURL  string
+	client  *http.Client
+}
+
+// awsEnricher queries the AWS metadata once per process
+var awsEnricher = newOnceEnricher(EnricherFunc(func(ctx context.Context) (map[string]interface{}, error) {
+	source := awsMetadataSource{
+		imdsURL: awsIMDSURL,
+		ecsURL:  os.Getenv("ECS_CONTAINER_METADATA_URI_V4"),
+		client:  &http.Client{Timeout: MetadataTimeout},
+	}
+	return source.fields(ctx)
+}))
+
+// AWSEnricher returns the enricher adding the fields describing the EC2
+// instance or ECS task running the process (see Config.AWSMetadata). The
+// endpoints are only queried once per process, failures included.
+func AWSEnricher() Enricher {
+	return awsEnricher
+}
+
+// fields queries the metadata endpoint
+func (s awsMetadataSource) fields(ctx context.Context) (map[string]interface{}, error) {
+	if s.ecsURL != "" {
+		return s.ecsFields(ctx)
+	}
+	return s.ec2Fields(ctx)
+}
+
+// ecsFields reads the cluster, task and availability zone from the task
+// metadata endpoint (version 4)
+func (s awsMetadataSource) ecsFields(ctx context.Context) (map[string]interface{}, error) {
+	body, err := s.get(ctx, s.ecsURL+"/task", "")
+	if err != nil {
+		return nil, err
+	}
+	var task struct {
+		Cluster          string
+		TaskARN          string
+		AvailabilityZone string
+	}
+	if err := json.Unmarshal(body, &task); err != nil {
+		return nil, fmt.Errorf("ecs task metadata: %w", err)
+	}
+
+	fields := make(map[string]interface{}, 3)
+	for key, value := range map[string]string{
+		AWSClusterField:          task.Cluster,
+		AWSTaskARNField:          task.TaskARN,
+		AWSAvailabilityZoneField: task.AvailabilityZone,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	return fields, nil
+}
+
+// ec2Fields reads the instance ID and availability zone from the instance
+// metadata service, with an IMDSv2 session token when one can be had
+func (s awsMetadataSource) ec2Fields(ctx context.Context) (map[string]interface{}, error) {
+	token := s.imdsToken(ctx)
+	instanceID, err := s.get(ctx, s.imdsURL+"/latest/meta-data/instance-id", token)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := s.get(ctx, s.imdsURL+"/latest/meta-data/placement/availability-zone", token)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		AWSInstanceIDField:       string(instanceID),
+		AWSAvailabilityZoneField: string(zone),
+	}, nil
+}
+
+// imdsToken returns an IMDSv2 session token, or "" to fall back on IMDSv1
+func (s awsMetadataSource) imdsToken(ctx context.Context) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.imdsURL+"/latest/api/token", nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	token, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return ""
+	}
+	return string(token)
+}
+
+// get returns the body served at url, with the IMDSv2 token if any
+func (s awsMetadataSource) get(ctx context.Context, url, token string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aws metadata %s: unexpected status %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return bytes.TrimSpace(body), err
+}
@@ -0,0 +1,80 @@
+package aloig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAWSMetadataEC2 tests reading the instance metadata with an IMDSv2
+// token
+func TestAWSMetadataEC2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest/api/token" {
+			if r.Method != http.MethodPut || r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte("session-token"))
+			return
+		}
+		if r.Header.Get("X-aws-ec2-metadata-token") != "session-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/latest/meta-data/instance-id":
+			w.Write([]byte("i-0abc123def456\n"))
+		case "/latest/meta-data/placement/availability-zone":
+			w.Write([]byte("eu-west-1b"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := awsMetadataSource{imdsURL: server.URL, client: server.Client()}
+	fields, err := source.fields(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fields[AWSInstanceIDField] != "i-0abc123def456" || fields[AWSAvailabilityZoneField] != "eu-west-1b" {
+		t.Errorf("Expected the instance ID and availability zone, got %v", fields)
+	}
+}
+
+// TestAWSMetadataECS tests reading the task metadata
+func TestAWSMetadataECS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/abc/task" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"Cluster": "arn:aws:ecs:eu-west-1:123456789012:cluster/prod", "TaskARN": "arn:aws:ecs:eu-west-1:123456789012:task/prod/7d1f", "AvailabilityZone": "eu-west-1a", "LaunchType": "FARGATE"}`))
+	}))
+	defer server.Close()
+
+	source := awsMetadataSource{imdsURL: "http://127.0.0.1:1", ecsURL: server.URL + "/v4/abc", client: server.Client()}
+	fields, err := source.fields(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fields[AWSClusterField] != "arn:aws:ecs:eu-west-1:123456789012:cluster/prod" || fields[AWSTaskARNField] != "arn:aws:ecs:eu-west-1:123456789012:task/prod/7d1f" ||
+		fields[AWSAvailabilityZoneField] != "eu-west-1a" {
+		t.Errorf("Expected the cluster, task and availability zone, got %v", fields)
+	}
+}
+
+// TestAWSMetadataUnavailable tests that a failed query is reported
+func TestAWSMetadataUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	source := awsMetadataSource{imdsURL: server.URL, client: server.Client()}
+	if fields, err := source.fields(context.Background()); err == nil {
+		t.Errorf("Expected an error, got %v", fields)
+	}
+}
@@ -0,0 +1,77 @@
+package aloig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Fields added by the AzureEnricher
+const (
+	AzureVMIDField          = "azure_vm_id"
+	AzureVMNameField        = "azure_vm_name"
+	AzureLocationField      = "azure_location"
+	AzureZoneField          = "azure_zone"
+	AzureResourceGroupField = "azure_resource_group"
+)
+
+// azureIMDSURL is the address of the Azure instance metadata service
+const azureIMDSURL = "http://169.254.169.254"
+
+// azureEnricher queries the Azure metadata once per process
+var azureEnricher = newOnceEnricher(EnricherFunc(func(ctx context.Context) (map[string]interface{}, error) {
+	return azureMetadataFields(ctx, azureIMDSURL, &http.Client{Timeout: MetadataTimeout})
+}))
+
+// AzureEnricher returns the enricher adding the ID, name, location,
+// availability zone and resource group of the Azure VM running the process,
+// read from the instance metadata service. The service is only queried once
+// per process, failures included.
+func AzureEnricher() Enricher {
+	return azureEnricher
+}
+
+// azureMetadataFields reads the compute metadata of the VM from the instance
+// metadata service at baseURL
+func azureMetadataFields(ctx context.Context, baseURL string, client *http.Client) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/metadata/instance/compute?api-version=2021-02-01", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("azure metadata: unexpected status %s", resp.Status)
+	}
+	var compute struct {
+		VMID              string `json:"vmId"`
+		Name              string `json:"name"`
+		Location          string `json:"location"`
+		Zone              string `json:"zone"`
+		ResourceGroupName string `json:"resourceGroupName"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&compute); err != nil {
+		return nil, fmt.Errorf("azure metadata: %w", err)
+	}
+
+	fields := make(map[string]interface{}, 5)
+	for key, value := range map[string]string{
+		AzureVMIDField:          compute.VMID,
+		AzureVMNameField:        compute.Name,
+		AzureLocationField:      compute.Location,
+		AzureZoneField:          compute.Zone,
+		AzureResourceGroupField: compute.ResourceGroupName,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	return fields, nil
+}
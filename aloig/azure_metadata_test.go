@@ -0,0 +1,33 @@
+package aloig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAzureMetadata tests reading the compute metadata of the VM
+func TestAzureMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata/instance/compute" || r.Header.Get("Metadata") != "true" || r.URL.Query().Get("api-version") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"vmId": "02aab8a4-74ef-476e-8182-f6d2ba4166a6", "name": "orders-vm-1", "location": "westeurope", "zone": "2", "resourceGroupName": "orders-prod", "vmSize": "Standard_D2s_v3"}`))
+	}))
+	defer server.Close()
+
+	fields, err := azureMetadataFields(context.Background(), server.URL, server.Client())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fields[AzureVMIDField] != "02aab8a4-74ef-476e-8182-f6d2ba4166a6" || fields[AzureVMNameField] != "orders-vm-1" ||
+		fields[AzureLocationField] != "westeurope" || fields[AzureZoneField] != "2" || fields[AzureResourceGroupField] != "orders-prod" {
+		t.Errorf("Expected the VM metadata, got %v", fields)
+	}
+
+	if _, err := azureMetadataFields(context.Background(), server.URL+"/missing", server.Client()); err == nil {
+		t.Error("Expected an error for an unexpected status")
+	}
+}
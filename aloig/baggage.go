@@ -0,0 +1,111 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// BaggageHeader carries the context fields of a request between services
+	BaggageHeader = "X-Aloig-Baggage"
+
+	// BaggageMetadataKey is the gRPC metadata key carrying the context
+	// fields, gRPC requiring lowercase keys
+	BaggageMetadataKey = "x-aloig-baggage"
+
+	// MaxBaggageSize is the maximum size of an encoded baggage; the fields
+	// that don't fit are left out
+	MaxBaggageSize = 8192
+
+	// MaxBaggageMembers is the maximum number of fields decoded from a
+	// baggage
+	MaxBaggageMembers = 64
+)
+
+// EncodeBaggage encodes the context fields of ctx (see ExtractContextFields)
+// in the W3C baggage format, "name=value" pairs separated by commas with
+// percent-encoded values, so they can be sent to another service in
+// BaggageHeader or the BaggageMetadataKey gRPC metadata. Values are sent as
+// strings.
+func EncodeBaggage(ctx context.Context) string {
+	fields := ExtractContextFields(ctx)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		member := escapeBaggage(name) + "=" + escapeBaggage(fmt.Sprint(fields[name]))
+		if b.Len()+len(member)+1 > MaxBaggageSize {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(member)
+	}
+	return b.String()
+}
+
+// DecodeBaggage returns a context carrying the fields encoded in baggage on
+// top of those of ctx, like ContextWithFields. Invalid members are skipped
+// and reported in the error; the valid ones are still added.
+func DecodeBaggage(ctx context.Context, baggage string) (context.Context, error) {
+	if strings.TrimSpace(baggage) == "" {
+		return ctx, nil
+	}
+
+	fields := make(map[string]interface{})
+	var invalid []string
+	for i, member := range strings.Split(baggage, ",") {
+		if i >= MaxBaggageMembers {
+			invalid = append(invalid, fmt.Sprintf("more than %d members", MaxBaggageMembers))
+			break
+		}
+		// Properties (";key=value") are not used
+		member, _, _ = strings.Cut(member, ";")
+		rawName, rawValue, ok := strings.Cut(member, "=")
+		name, nameErr := url.PathUnescape(strings.TrimSpace(rawName))
+		value, valueErr := url.PathUnescape(strings.TrimSpace(rawValue))
+		if !ok || name == "" || nameErr != nil || valueErr != nil {
+			invalid = append(invalid, fmt.Sprintf("%q", strings.TrimSpace(member)))
+			continue
+		}
+		fields[name] = value
+	}
+
+	if len(fields) > 0 {
+		ctx = ContextWithFields(ctx, fields)
+	}
+	if len(invalid) > 0 {
+		return ctx, fmt.Errorf("invalid baggage members: %s", strings.Join(invalid, ", "))
+	}
+	return ctx, nil
+}
+
+// escapeBaggage percent-encodes the characters not allowed in baggage names
+// and values, including the separators
+func escapeBaggage(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// InjectBaggage sets BaggageHeader to the encoded context fields of ctx,
+// e.g. on the requests of an HTTP client
+func InjectBaggage(ctx context.Context, header http.Header) {
+	if baggage := EncodeBaggage(ctx); baggage != "" {
+		header.Set(BaggageHeader, baggage)
+	}
+}
+
+// ExtractBaggage returns a context carrying the fields of the BaggageHeader
+// of header on top of those of ctx. Invalid members are skipped.
+func ExtractBaggage(ctx context.Context, header http.Header) context.Context {
+	ctx, _ = DecodeBaggage(ctx, strings.Join(header.Values(BaggageHeader), ","))
+	return ctx
+}
@@ -0,0 +1,83 @@
+package aloig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestBaggageRoundTrip tests that the context fields survive a service hop
+func TestBaggageRoundTrip(t *testing.T) {
+	ctx := WithUserID(context.Background(), "user-789")
+	ctx = ContextWithFields(ctx, map[string]interface{}{"tenant": "acme, inc=1;", "order_id": 42, "note": "a+b c"})
+
+	header := http.Header{}
+	InjectBaggage(ctx, header)
+	if baggage := header.Get(BaggageHeader); baggage != "note=a%2Bb%20c,order_id=42,tenant=acme%2C%20inc%3D1%3B,user_id=user-789" {
+		t.Errorf("Unexpected baggage: %s", baggage)
+	}
+
+	restored := ExtractBaggage(context.Background(), header)
+	fields := ExtractContextFields(restored)
+	expected := map[string]interface{}{"tenant": "acme, inc=1;", "order_id": "42", "note": "a+b c", "user_id": "user-789"}
+	if len(fields) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, fields)
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, fields[k])
+		}
+	}
+	if GetUserID(restored) != "user-789" {
+		t.Errorf("Expected the user ID to be restored, got %q", GetUserID(restored))
+	}
+}
+
+// TestDecodeBaggageInvalid tests that invalid members are skipped
+func TestDecodeBaggageInvalid(t *testing.T) {
+	ctx, err := DecodeBaggage(context.Background(), " step = charge ;prop=1, broken, =empty, bad=%zz")
+	if err == nil {
+		t.Error("Expected an error for the invalid members")
+	}
+	fields := ExtractContextFields(ctx)
+	if len(fields) != 1 || fields["step"] != "charge" {
+		t.Errorf("Expected only the valid member, got %v", fields)
+	}
+
+	members := strings.TrimSuffix(strings.Repeat("k=v,", MaxBaggageMembers+1), ",")
+	if _, err := DecodeBaggage(context.Background(), members); err == nil {
+		t.Error("Expected an error for too many members")
+	}
+}
+
+// TestEncodeBaggageSize tests that the encoded baggage stays within the limit
+func TestEncodeBaggageSize(t *testing.T) {
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{
+		"big":   strings.Repeat("x", MaxBaggageSize),
+		"small": "kept",
+	})
+	if baggage := EncodeBaggage(ctx); baggage != "small=kept" {
+		t.Errorf("Expected the oversized field to be left out, got %d bytes", len(baggage))
+	}
+}
+
+// TestHTTPMiddlewareBaggage tests that the middleware restores the baggage
+// only when enabled
+func TestHTTPMiddlewareBaggage(t *testing.T) {
+	for _, propagate := range []bool{true, false} {
+		logger, buf := newBufferLogger(logrus.InfoLevel)
+		handler := HTTPMiddleware(HTTPOptions{Logger: logger, PropagateBaggage: propagate})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+		req.Header.Set(BaggageHeader, "tenant=acme")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if logged := strings.Contains(buf.String(), "tenant=acme"); logged != propagate {
+			t.Errorf("Expected tenant logged to be %v, got: %s", propagate, buf.String())
+		}
+	}
+}
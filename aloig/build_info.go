@@ -0,0 +1,71 @@
+package aloig
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+)
+
+// readBuildInfo reads the build info of the binary; tests replace it
+var readBuildInfo = debug.ReadBuildInfo
+
+// buildInfoFields returns the Go version and, when the binary was built with
+// module support, the main module and its version control revision
+func buildInfoFields() map[string]interface{} {
+	fields := map[string]interface{}{"go_version": runtime.Version()}
+	info, ok := readBuildInfo()
+	if !ok {
+		return fields
+	}
+	fields["main_module"] = info.Main.Path
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		fields["main_version"] = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			fields["vcs_revision"] = setting.Value
+		case "vcs.modified":
+			fields["vcs_modified"] = setting.Value == "true"
+		}
+	}
+	return fields
+}
+
+// buildVersion returns the version of the main module, or its version
+// control revision for development builds, or "" when the binary has neither
+func buildVersion() string {
+	fields := buildInfoFields()
+	if version, ok := fields["main_version"].(string); ok {
+		return version
+	}
+	revision, _ := fields["vcs_revision"].(string)
+	return revision
+}
+
+// buildRelease returns the release of the binary, "<appName>@<version>", and
+// the fields identifying its build (Go version, version control revision and
+// whether the working tree was modified), from the build info. The release
+// is "" when the binary has neither a version nor a revision.
+func buildRelease(appName string) (string, logrus.Fields) {
+	info := buildInfoFields()
+	fields := logrus.Fields{"go_version": info["go_version"]}
+	for _, key := range []string{"vcs_revision", "vcs_modified"} {
+		if value, ok := info[key]; ok {
+			fields[key] = value
+		}
+	}
+
+	version := buildVersion()
+	if version == "" {
+		return "", fields
+	}
+	if _, tagged := info["main_version"]; !tagged && info["vcs_modified"] == true {
+		version += "+dirty"
+	}
+	if appName != "" {
+		version = appName + "@" + version
+	}
+	return version, fields
+}
@@ -0,0 +1,79 @@
+package aloig
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeBuildInfo replaces the build info of the binary until the test ends
+func fakeBuildInfo(t *testing.T, version string, settings ...debug.BuildSetting) {
+	original := readBuildInfo
+	t.Cleanup(func() { readBuildInfo = original })
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.22.0",
+			Main:      debug.Module{Path: "example.com/orders", Version: version},
+			Settings:  settings,
+		}, true
+	}
+}
+
+// TestBuildRelease tests the release derived from the module version or the
+// revision
+func TestBuildRelease(t *testing.T) {
+	revision := debug.BuildSetting{Key: "vcs.revision", Value: "4f2a9c1"}
+	tests := []struct {
+		name     string
+		version  string
+		settings []debug.BuildSetting
+		release  string
+	}{
+		{"tagged", "v1.4.2", []debug.BuildSetting{revision}, "orders@v1.4.2"},
+		{"revision", "(devel)", []debug.BuildSetting{revision, {Key: "vcs.modified", Value: "false"}}, "orders@4f2a9c1"},
+		{"dirty", "(devel)", []debug.BuildSetting{revision, {Key: "vcs.modified", Value: "true"}}, "orders@4f2a9c1+dirty"},
+		{"unknown", "(devel)", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeBuildInfo(t, tt.version, tt.settings...)
+			release, fields := buildRelease("orders")
+			if release != tt.release {
+				t.Errorf("Expected release %q, got %q", tt.release, release)
+			}
+			if fields["go_version"] == nil {
+				t.Errorf("Expected the Go version, got %v", fields)
+			}
+			if _, ok := fields["vcs_revision"]; ok != (len(tt.settings) > 0) {
+				t.Errorf("Expected the revision when known, got %v", fields)
+			}
+		})
+	}
+}
+
+// TestNewLoggerBuildInfo tests that the build info fills an empty release
+func TestNewLoggerBuildInfo(t *testing.T) {
+	fakeBuildInfo(t, "(devel)", debug.BuildSetting{Key: "vcs.revision", Value: "4f2a9c1"}, debug.BuildSetting{Key: "vcs.modified", Value: "true"})
+
+	for _, release := range []string{"", "orders@42"} {
+		logger := NewLogger(Config{Environment: "prod", AppName: "orders", Release: release, Level: logrus.InfoLevel}).(*logrusLogger)
+		var buf bytes.Buffer
+		logger.logger.SetOutput(&buf)
+		logger.logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+		logger.Info("started")
+
+		output := buf.String()
+		if release == "" {
+			if !strings.Contains(output, "release=orders@4f2a9c1+dirty") || !strings.Contains(output, "vcs_revision=4f2a9c1") ||
+				!strings.Contains(output, "vcs_modified=true") || !strings.Contains(output, "go_version=") {
+				t.Errorf("Expected the release and build fields from the build info, got: %s", output)
+			}
+		} else if !strings.Contains(output, "release=orders@42") || strings.Contains(output, "vcs_revision") {
+			t.Errorf("Expected the configured release only, got: %s", output)
+		}
+	}
+}
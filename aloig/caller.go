@@ -0,0 +1,113 @@
+package aloig
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxCallerDepth is the number of frames searched for the caller
+const maxCallerDepth = 32
+
+// callerSkipKey is the context key holding the number of extra caller frames
+// to skip
+type callerSkipKey struct{}
+
+// aloigPackage is the prefix of the functions of this package
+var aloigPackage = packagePrefix(reflect.ValueOf(packagePrefix).Pointer())
+
+// packagePrefix returns the package path and dot prefixing the name of the
+// function at pc
+func packagePrefix(pc uintptr) string {
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	return name[:slash+strings.Index(name[slash:], ".")+1]
+}
+
+// AddCallerSkip returns a context making the entries logged with it report
+// the caller n frames further up the stack, e.g. for a helper that logs on
+// behalf of its caller:
+//
+//	func logFailure(ctx context.Context, err error) {
+//		aloig.ErrorContext(aloig.AddCallerSkip(ctx, 1), err)
+//	}
+func AddCallerSkip(ctx context.Context, n int) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, callerSkipKey{}, callerSkip(ctx)+n)
+}
+
+// callerSkip returns the number of extra caller frames to skip for ctx
+func callerSkip(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	skip, _ := ctx.Value(callerSkipKey{}).(int)
+	return skip
+}
+
+// WithCallerSkip returns a logger whose entries report the caller n frames
+// further up the stack, for packages wrapping the logger in helpers
+func (l *logrusLogger) WithCallerSkip(n int) Logger {
+	entry := l.newEntry()
+	return l.child(entry.WithContext(AddCallerSkip(entry.Context, n)))
+}
+
+// CallerHook reports the caller of the entry as the first frame outside
+// this package and logrus, instead of logrus reporting the aloig method that
+// logged it, skipping the extra frames set with WithCallerSkip and
+// AddCallerSkip. It only acts when the logger reports the caller.
+type CallerHook struct{}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *CallerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sets the caller of the entry
+func (hook *CallerHook) Fire(entry *logrus.Entry) error {
+	if entry.Caller == nil {
+		return nil
+	}
+	if frame, ok := findCaller(callerSkip(entry.Context)); ok {
+		entry.Caller = &frame
+	}
+	return nil
+}
+
+// findCaller returns the first frame outside this package and logrus,
+// skipping skip more frames
+func findCaller(skip int) (runtime.Frame, bool) {
+	pcs := make([]uintptr, maxCallerDepth+skip)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	found := false
+	for {
+		frame, more := frames.Next()
+		if !found && !isLoggingFrame(frame) {
+			found = true
+		}
+		if found {
+			if skip == 0 {
+				return frame, true
+			}
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// isLoggingFrame checks if the frame belongs to this package (tests aside)
+// or to logrus
+func isLoggingFrame(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "github.com/sirupsen/logrus.") {
+		return true
+	}
+	return strings.HasPrefix(frame.Function, aloigPackage) && !strings.HasSuffix(frame.File, "_test.go")
+}
@@ -0,0 +1,66 @@
+package aloig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newCallerLogger returns a buffer logger reporting callers through the CallerHook
+func newCallerLogger() (*logrusLogger, *bytes.Buffer) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetReportCaller(true)
+	logger.logger.AddHook(&CallerHook{})
+	return logger, buf
+}
+
+// currentLine returns the line it is called from
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+// logOnBehalf is a helper logging on behalf of its caller
+func logOnBehalf(logger Logger, msg string) {
+	logger.WithCallerSkip(1).Info(msg)
+}
+
+// TestCallerHook tests that the caller is the call site instead of aloig
+func TestCallerHook(t *testing.T) {
+	logger, out := newCallerLogger()
+
+	line := currentLine() + 1
+	logger.WithField("key", "value").Info("direct")
+	if expected := fmt.Sprintf("caller_test.go:%d", line); !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected %q as the caller, got: %s", expected, out.String())
+	}
+}
+
+// TestWithCallerSkip tests per-logger and per-call caller skips
+func TestWithCallerSkip(t *testing.T) {
+	logger, out := newCallerLogger()
+
+	line := currentLine() + 1
+	logOnBehalf(logger, "from helper")
+	if expected := fmt.Sprintf("caller_test.go:%d", line); !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected %q as the caller, got: %s", expected, out.String())
+	}
+
+	out.Reset()
+	helper := func(ctx context.Context) {
+		logger.WithCallerSkip(1).InfoContext(AddCallerSkip(ctx, 1), "from nested helper")
+	}
+	wrapper := func(ctx context.Context) {
+		helper(ctx)
+	}
+	line = currentLine() + 1
+	wrapper(WithTraceID(context.Background(), "trace-caller"))
+	if expected := fmt.Sprintf("caller_test.go:%d", line); !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected %q as the caller, got: %s", expected, out.String())
+	}
+}
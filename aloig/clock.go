@@ -0,0 +1,75 @@
+package aloig
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Clock tells the time of log entries
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock reading the real time
+var SystemClock Clock = systemClock{}
+
+// systemClock reads the real time
+type systemClock struct{}
+
+// Now returns the current time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock is a Clock that only moves when told to, so tests get
+// deterministic timestamps and formatter output can be compared with golden
+// files
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock returns a clock stopped at now
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time of the clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Add moves the clock forward by d
+func (c *ManualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// ClockHook sets the time of every entry from a Clock. NewLogger adds it
+// when Config.Clock is set; add it first to loggers wrapped with FromLogrus.
+type ClockHook struct {
+	Clock Clock
+}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *ClockHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sets the entry time
+func (hook *ClockHook) Fire(entry *logrus.Entry) error {
+	entry.Time = hook.Clock.Now()
+	return nil
+}
@@ -0,0 +1,30 @@
+package aloig
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestClock tests that entries take their time from the configured clock
+func TestClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	logger := NewLogger(Config{Environment: "prod", AppName: "clock-test", Level: logrus.InfoLevel, Clock: clock}).(*logrusLogger)
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+
+	logger.Info("first")
+	clock.Add(2 * time.Second)
+	logger.Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[0], `"time":"2024-01-02T03:04:05Z"`) || !strings.Contains(lines[1], `"time":"2024-01-02T03:04:07Z"`) {
+		t.Errorf("Expected the clock times, got: %s", buf.String())
+	}
+}
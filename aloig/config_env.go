@@ -0,0 +1,322 @@
+package aloig
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultEnvPrefix is the prefix of the variables read by DefaultConfig
+const DefaultEnvPrefix = "ALOIG"
+
+// envVar is a configuration environment variable
+type envVar struct {
+	// name is the name of the variable after the prefix
+	name string
+
+	// legacy is the unprefixed name read before the prefixed one, kept
+	// for the services configured before prefixed variables existed
+	legacy string
+
+	set func(config *Config, value string) error
+}
+
+// envVars are the variables read by ConfigFromEnv. Lists are comma
+// separated and maps are comma separated key=value pairs.
+var envVars = []envVar{
+	{name: "ENVIRONMENT", legacy: "ENVIRONMENT", set: envString(func(c *Config) *string { return &c.Environment })},
+	{name: "APP_NAME", legacy: "APP_NAME", set: envString(func(c *Config) *string { return &c.AppName })},
+	{name: "RELEASE", set: envString(func(c *Config) *string { return &c.Release })},
+	{name: "HOSTNAME", legacy: "HOSTNAME", set: envString(func(c *Config) *string { return &c.HostName })},
+	{name: "SERVER_NAME", set: envString(func(c *Config) *string { return &c.ServerName })},
+	{name: "LEVEL", set: envLevel(func(c *Config) *logrus.Level { return &c.Level })},
+	{name: "NAMED_LEVELS", set: func(c *Config, value string) error {
+		levels := make(map[string]logrus.Level)
+		for name, s := range parseEnvMap(value) {
+			level, err := logrus.ParseLevel(s)
+			if err != nil {
+				return err
+			}
+			levels[name] = level
+		}
+		c.NamedLevels = levels
+		return nil
+	}},
+	{name: "REPORT_CALLER", set: envBool(func(c *Config) *bool { return &c.ReportCaller })},
+	{name: "STACK_TRACES", set: envBool(func(c *Config) *bool { return &c.StackTraces })},
+	{name: "NESTED_FIELDS", set: envBool(func(c *Config) *bool { return &c.NestedFields })},
+	{name: "CUSTOM_FIELDS", set: func(c *Config, value string) error {
+		for k, v := range parseEnvMap(value) {
+			c.CustomFields[k] = v
+		}
+		return nil
+	}},
+	{name: "TRACE_ID_FORMAT", set: func(c *Config, value string) error {
+		format, err := ParseTraceIDFormat(value)
+		if err == nil {
+			c.TraceIDFormat = format
+		}
+		return err
+	}},
+	{name: "DEADLINE_REMAINING", set: envBool(func(c *Config) *bool { return &c.DeadlineRemaining })},
+	{name: "PROCESS_IDS", set: envBool(func(c *Config) *bool { return &c.ProcessIDs })},
+	{name: "SEQUENCE", set: envBool(func(c *Config) *bool { return &c.Sequence })},
+	{name: "HOST_FIELDS", set: envBool(func(c *Config) *bool { return &c.HostFields })},
+	{name: "AWS_METADATA", set: envBool(func(c *Config) *bool { return &c.AWSMetadata })},
+	{name: "DEPLOYMENT_FIELDS", set: envBool(func(c *Config) *bool { return &c.DeploymentFields })},
+	{name: "SAMPLE_RATE", set: envFloat(func(c *Config) *float64 { return &c.SampleRate })},
+	{name: "DEDUP_WINDOW", set: envDuration(func(c *Config) *time.Duration { return &c.DedupWindow })},
+	{name: "ADAPTIVE_SAMPLING_THRESHOLD", set: envInt(func(c *Config) *int { return &c.AdaptiveSamplingThreshold })},
+	{name: "ADAPTIVE_SAMPLING_WINDOW", set: envDuration(func(c *Config) *time.Duration { return &c.AdaptiveSamplingWindow })},
+	{name: "ERROR_RATE_THRESHOLD", set: envInt(func(c *Config) *int { return &c.ErrorRateThreshold })},
+	{name: "ERROR_RATE_WINDOW", set: envDuration(func(c *Config) *time.Duration { return &c.ErrorRateWindow })},
+	{name: "REDACT_FIELDS", set: envList(func(c *Config) *[]string { return &c.RedactFields })},
+	{name: "ALLOW_FIELDS", set: envFieldPolicy(func(p *FieldPolicy) *[]string { return &p.Allow })},
+	{name: "DENY_FIELDS", set: envFieldPolicy(func(p *FieldPolicy) *[]string { return &p.Deny })},
+	{name: "PSEUDONYMIZE_KEY", set: envString(func(c *Config) *string { return &c.PseudonymizeKey })},
+	{name: "PSEUDONYMIZE_FIELDS", set: envList(func(c *Config) *[]string { return &c.PseudonymizeFields })},
+	{name: "ENCODER", set: func(c *Config, value string) error {
+		encoder, err := ParseEncoder(value)
+		if err == nil {
+			c.Encoder = encoder
+		}
+		return err
+	}},
+	{name: "PLAIN_DEV_FORMATTER", set: envBool(func(c *Config) *bool { return &c.PlainDevFormatter })},
+	{name: "FORCE_JSON", set: envBool(func(c *Config) *bool { return &c.ForceJSON })},
+	{name: "FORCE_TEXT", set: envBool(func(c *Config) *bool { return &c.ForceText })},
+	{name: "TIMESTAMP_FORMAT", set: envString(func(c *Config) *string { return &c.TimestampFormat })},
+	{name: "TIMESTAMP_UTC", set: envBool(func(c *Config) *bool { return &c.TimestampUTC })},
+	{name: "ASYNC_BUFFER_SIZE", set: envInt(func(c *Config) *int { return &c.AsyncBufferSize })},
+	{name: "ASYNC_HOOK_WORKERS", set: envInt(func(c *Config) *int { return &c.AsyncHookWorkers })},
+	{name: "BACKPRESSURE_POLICY", set: func(c *Config, value string) error {
+		policy, err := ParseBackpressurePolicy(value)
+		if err == nil {
+			c.BackpressurePolicy = policy
+		}
+		return err
+	}},
+	{name: "HOOK_ERROR_POLICY", set: func(c *Config, value string) error {
+		policy, err := ParseHookErrorPolicy(value)
+		if err == nil {
+			c.HookErrorPolicy = policy
+		}
+		return err
+	}},
+	{name: "MAX_MESSAGE_LENGTH", set: envInt(func(c *Config) *int { return &c.MaxMessageLength })},
+	{name: "MAX_FIELD_LENGTH", set: envInt(func(c *Config) *int { return &c.MaxFieldLength })},
+	{name: "SYSLOG_NETWORK", legacy: "SYSLOG_NETWORK", set: envString(func(c *Config) *string { return &c.SyslogNetwork })},
+	{name: "SYSLOG_ADDRESS", legacy: "SYSLOG_ADDRESS", set: envString(func(c *Config) *string { return &c.SyslogAddress })},
+	{name: "SYSLOG_FACILITY", set: func(c *Config, value string) error {
+		facility, err := ParseSyslogFacility(value)
+		if err == nil {
+			c.SyslogFacility = facility
+		}
+		return err
+	}},
+	{name: "SENTRY_DSN", legacy: "SENTRY_DSN", set: envString(func(c *Config) *string { return &c.SentryDSN })},
+	{name: "SENTRY_LEVELS", set: func(c *Config, value string) error {
+		var levels []logrus.Level
+		for _, s := range parseEnvList(value) {
+			level, err := logrus.ParseLevel(s)
+			if err != nil {
+				return err
+			}
+			levels = append(levels, level)
+		}
+		c.SentryLevels = levels
+		return nil
+	}},
+	{name: "SENTRY_SAMPLE_RATE", set: envFloat(func(c *Config) *float64 { return &c.SentrySampleRate })},
+	{name: "SENTRY_TRACES_SAMPLE_RATE", set: envFloat(func(c *Config) *float64 { return &c.TracesSampleRate })},
+	{name: "SENTRY_TAG_FIELDS", set: envList(func(c *Config) *[]string { return &c.SentryTagFields })},
+	{name: "SENTRY_FLUSH_TIMEOUT", set: envDuration(func(c *Config) *time.Duration { return &c.SentryFlushTimeout })},
+	{name: "SENTRY_BREADCRUMBS", set: envBool(func(c *Config) *bool { return &c.SentryBreadcrumbs })},
+	{name: "SENTRY_BREADCRUMB_LEVEL", set: envLevel(func(c *Config) *logrus.Level { return &c.SentryBreadcrumbLevel })},
+	{name: "SENTRY_MAX_BREADCRUMBS", set: envInt(func(c *Config) *int { return &c.SentryMaxBreadcrumbs })},
+	{name: "SENTRY_SPOOL_SIZE", set: envInt(func(c *Config) *int { return &c.SentrySpoolSize })},
+	{name: "SENTRY_SPOOL_DIR", set: envString(func(c *Config) *string { return &c.SentrySpoolDir })},
+	{name: "SENTRY_ROUTE_FIELD", set: envString(func(c *Config) *string { return &c.SentryRouteField })},
+	{name: "SENTRY_ROUTE_DSNS", set: func(c *Config, value string) error {
+		c.SentryRouteDSNs = parseEnvMap(value)
+		return nil
+	}},
+	{name: "SENTRY_HTTP_PROXY", set: envString(func(c *Config) *string { return &c.SentryHTTPProxy })},
+	{name: "SENTRY_HTTPS_PROXY", set: envString(func(c *Config) *string { return &c.SentryHTTPSProxy })},
+	{name: "SENTRY_DEBUG", set: envBool(func(c *Config) *bool { return &c.SentryDebug })},
+}
+
+// ConfigFromEnv builds a Config from the environment variables named after
+// prefix, e.g. ALOIG_LEVEL=warn for the "ALOIG" prefix (see the README for
+// the full list). The unprefixed ENVIRONMENT, APP_NAME, DEPLOY_ID, HOSTNAME,
+// SENTRY_DSN, SYSLOG_NETWORK and SYSLOG_ADDRESS variables are still read, the
+// prefixed ones taking precedence. Invalid values are returned as an error
+// and leave the default in place; the other values are applied.
+func ConfigFromEnv(prefix string) (Config, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	config := Config{
+		TracesSampleRate: 0.2,
+		Level:            logrus.TraceLevel,
+		ReportCaller:     true,
+		StackTraces:      true,
+		CustomFields:     make(map[string]interface{}),
+		SyslogFacility:   SyslogFacilityUser,
+		MaxMessageLength: DefaultMaxMessageLength,
+		MaxFieldLength:   DefaultMaxFieldLength,
+	}
+
+	var invalid []string
+	for _, v := range envVars {
+		for _, name := range []string{v.legacy, prefix + v.name} {
+			if name == "" {
+				continue
+			}
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := v.set(&config, value); err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+	}
+
+	if config.Release == "" {
+		// Without a deploy ID, NewLogger reads the release from the build
+		// info
+		deployID := os.Getenv("DEPLOY_ID")
+		if prefixed, ok := os.LookupEnv(prefix + "DEPLOY_ID"); ok {
+			deployID = prefixed
+		}
+		if deployID != "" {
+			config.Release = config.AppName + "@" + deployID
+		}
+	}
+	if config.ServerName == "" {
+		config.ServerName = config.AppName
+	}
+
+	if len(invalid) > 0 {
+		return config, fmt.Errorf("invalid environment variables: %s", strings.Join(invalid, "; "))
+	}
+	return config, nil
+}
+
+// envString returns a setter for the string field returned by field
+func envString(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+// envBool returns a setter for the bool field returned by field
+func envBool(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err == nil {
+			*field(c) = b
+		}
+		return err
+	}
+}
+
+// envInt returns a setter for the int field returned by field
+func envInt(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		i, err := strconv.Atoi(value)
+		if err == nil {
+			*field(c) = i
+		}
+		return err
+	}
+}
+
+// envFloat returns a setter for the float64 field returned by field
+func envFloat(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			*field(c) = f
+		}
+		return err
+	}
+}
+
+// envDuration returns a setter for the duration field returned by field
+func envDuration(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err == nil {
+			*field(c) = d
+		}
+		return err
+	}
+}
+
+// envLevel returns a setter for the level field returned by field
+func envLevel(field func(*Config) *logrus.Level) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		level, err := logrus.ParseLevel(value)
+		if err == nil {
+			*field(c) = level
+		}
+		return err
+	}
+}
+
+// envList returns a setter for the list field returned by field
+func envList(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = parseEnvList(value)
+		return nil
+	}
+}
+
+// envFieldPolicy returns a setter for the list returned by field of the
+// field policy of the configured environment
+func envFieldPolicy(field func(*FieldPolicy) *[]string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		policies := make(map[string]FieldPolicy)
+		if c.FieldPolicies == nil {
+			c.FieldPolicies = DefaultFieldPolicies
+		}
+		for env, policy := range c.FieldPolicies {
+			policies[env] = policy
+		}
+		policy := policies[c.Environment]
+		*field(&policy) = parseEnvList(value)
+		policies[c.Environment] = policy
+		c.FieldPolicies = policies
+		return nil
+	}
+}
+
+// parseEnvList splits a comma separated list, dropping empty items
+func parseEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseEnvMap parses comma separated key=value pairs
+func parseEnvMap(value string) map[string]string {
+	pairs := make(map[string]string)
+	for _, item := range parseEnvList(value) {
+		if k, v, ok := strings.Cut(item, "="); ok {
+			pairs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return pairs
+}
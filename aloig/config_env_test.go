@@ -0,0 +1,74 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestConfigFromEnv tests reading prefixed and legacy variables
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("APP_NAME", "legacy-app")
+	t.Setenv("DEPLOY_ID", "42")
+	t.Setenv("SENTRY_DSN", "https://legacy@example.com/1")
+	t.Setenv("TEST_SENTRY_DSN", "https://public@example.com/1")
+	t.Setenv("TEST_LEVEL", "warn")
+	t.Setenv("TEST_REPORT_CALLER", "false")
+	t.Setenv("TEST_NAMED_LEVELS", "db=error, http=debug")
+	t.Setenv("TEST_CUSTOM_FIELDS", "team=payments")
+	t.Setenv("TEST_SYSLOG_FACILITY", "local3")
+	t.Setenv("TEST_SENTRY_LEVELS", "error,fatal")
+	t.Setenv("TEST_SENTRY_FLUSH_TIMEOUT", "3s")
+	t.Setenv("TEST_SENTRY_TAG_FIELDS", "tenant, region")
+
+	config, err := ConfigFromEnv("TEST")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.AppName != "legacy-app" || config.Release != "legacy-app@42" || config.ServerName != "legacy-app" {
+		t.Errorf("Expected the legacy application variables, got %+v", config)
+	}
+	if config.SentryDSN != "https://public@example.com/1" {
+		t.Errorf("Expected the prefixed variable to take precedence, got %s", config.SentryDSN)
+	}
+	if config.Level != logrus.WarnLevel || config.ReportCaller || config.NamedLevels["http"] != logrus.DebugLevel || config.CustomFields["team"] != "payments" {
+		t.Errorf("Unexpected general settings: %+v", config)
+	}
+	if config.SyslogFacility != SyslogFacilityLocal3 || len(config.SentryLevels) != 2 || config.SentryFlushTimeout != 3*time.Second || len(config.SentryTagFields) != 2 {
+		t.Errorf("Unexpected sink settings: %+v", config)
+	}
+	if config.MaxMessageLength != DefaultMaxMessageLength || config.SentryBreadcrumbs {
+		t.Errorf("Expected defaults for unset variables, got %+v", config)
+	}
+}
+
+// TestConfigFromEnvNoDeployID tests that the release is left to the build
+// info without a deploy ID
+func TestConfigFromEnvNoDeployID(t *testing.T) {
+	t.Setenv("TEST_APP_NAME", "orders")
+
+	config, err := ConfigFromEnv("TEST")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Release != "" {
+		t.Errorf("Expected no release, got %q", config.Release)
+	}
+}
+
+// TestConfigFromEnvInvalid tests that invalid values are reported and keep the default
+func TestConfigFromEnvInvalid(t *testing.T) {
+	t.Setenv("TEST_LEVEL", "loud")
+	t.Setenv("TEST_SYSLOG_FACILITY", "nowhere")
+	t.Setenv("TEST_SAMPLE_RATE", "0.5")
+
+	config, err := ConfigFromEnv("TEST_")
+	if err == nil || !strings.Contains(err.Error(), "TEST_LEVEL") || !strings.Contains(err.Error(), "TEST_SYSLOG_FACILITY") {
+		t.Errorf("Expected both invalid variables in the error, got %v", err)
+	}
+	if config.Level != logrus.TraceLevel || config.SyslogFacility != SyslogFacilityUser || config.SampleRate != 0.5 {
+		t.Errorf("Expected defaults for invalid values and valid values applied, got %+v", config)
+	}
+}
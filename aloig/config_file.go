@@ -0,0 +1,342 @@
+package aloig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// configEnvPattern matches ${VAR} and ${VAR:-default} references in
+// configuration files
+var configEnvPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// fileConfig is the representation of Config in configuration files. Unset
+// values keep the value of DefaultConfig.
+type fileConfig struct {
+	Environment        *string                `yaml:"environment" json:"environment"`
+	AppName            *string                `yaml:"app_name" json:"app_name"`
+	Release            *string                `yaml:"release" json:"release"`
+	HostName           *string                `yaml:"hostname" json:"hostname"`
+	ServerName         *string                `yaml:"server_name" json:"server_name"`
+	Level              string                 `yaml:"level" json:"level"`
+	NamedLevels        map[string]string      `yaml:"named_levels" json:"named_levels"`
+	ReportCaller       *bool                  `yaml:"report_caller" json:"report_caller"`
+	StackTraces        *bool                  `yaml:"stack_traces" json:"stack_traces"`
+	NestedFields       *bool                  `yaml:"nested_fields" json:"nested_fields"`
+	CustomFields       map[string]interface{} `yaml:"custom_fields" json:"custom_fields"`
+	TraceIDFormat      string                 `yaml:"trace_id_format" json:"trace_id_format"`
+	DeadlineRemaining  *bool                  `yaml:"deadline_remaining" json:"deadline_remaining"`
+	ProcessIDs         *bool                  `yaml:"process_ids" json:"process_ids"`
+	Sequence           *bool                  `yaml:"sequence" json:"sequence"`
+	HostFields         *bool                  `yaml:"host_fields" json:"host_fields"`
+	AWSMetadata        *bool                  `yaml:"aws_metadata" json:"aws_metadata"`
+	DeploymentFields   *bool                  `yaml:"deployment_fields" json:"deployment_fields"`
+	SampleRate         *float64               `yaml:"sample_rate" json:"sample_rate"`
+	DedupWindow        string                 `yaml:"dedup_window" json:"dedup_window"`
+	SamplingThreshold  *int                   `yaml:"adaptive_sampling_threshold" json:"adaptive_sampling_threshold"`
+	SamplingWindow     string                 `yaml:"adaptive_sampling_window" json:"adaptive_sampling_window"`
+	ErrorRateThreshold *int                   `yaml:"error_rate_threshold" json:"error_rate_threshold"`
+	ErrorRateWindow    string                 `yaml:"error_rate_window" json:"error_rate_window"`
+	RedactFields       []string               `yaml:"redact_fields" json:"redact_fields"`
+	FieldPolicies      map[string]FieldPolicy `yaml:"field_policies" json:"field_policies"`
+	PseudonymizeKey    *string                `yaml:"pseudonymize_key" json:"pseudonymize_key"`
+	PseudonymizeFields []string               `yaml:"pseudonymize_fields" json:"pseudonymize_fields"`
+	Encoder            string                 `yaml:"encoder" json:"encoder"`
+	PlainDevFormatter  *bool                  `yaml:"plain_dev_formatter" json:"plain_dev_formatter"`
+	ForceJSON          *bool                  `yaml:"force_json" json:"force_json"`
+	ForceText          *bool                  `yaml:"force_text" json:"force_text"`
+	TimestampFormat    *string                `yaml:"timestamp_format" json:"timestamp_format"`
+	TimestampUTC       *bool                  `yaml:"timestamp_utc" json:"timestamp_utc"`
+	AsyncBufferSize    *int                   `yaml:"async_buffer_size" json:"async_buffer_size"`
+	AsyncHookWorkers   *int                   `yaml:"async_hook_workers" json:"async_hook_workers"`
+	BackpressurePolicy string                 `yaml:"backpressure_policy" json:"backpressure_policy"`
+	HookErrorPolicy    string                 `yaml:"hook_error_policy" json:"hook_error_policy"`
+	MaxMessageLength   *int                   `yaml:"max_message_length" json:"max_message_length"`
+	MaxFieldLength     *int                   `yaml:"max_field_length" json:"max_field_length"`
+	Syslog             fileSyslogConfig       `yaml:"syslog" json:"syslog"`
+	Sentry             fileSentryConfig       `yaml:"sentry" json:"sentry"`
+}
+
+// fileSyslogConfig is the syslog section of configuration files
+type fileSyslogConfig struct {
+	Network  *string `yaml:"network" json:"network"`
+	Address  *string `yaml:"address" json:"address"`
+	Facility string  `yaml:"facility" json:"facility"`
+}
+
+// fileSentryConfig is the Sentry section of configuration files
+type fileSentryConfig struct {
+	DSN              *string                `yaml:"dsn" json:"dsn"`
+	Levels           []string               `yaml:"levels" json:"levels"`
+	SampleRate       *float64               `yaml:"sample_rate" json:"sample_rate"`
+	TracesSampleRate *float64               `yaml:"traces_sample_rate" json:"traces_sample_rate"`
+	TagFields        []string               `yaml:"tag_fields" json:"tag_fields"`
+	IgnoreMessages   []string               `yaml:"ignore_messages" json:"ignore_messages"`
+	IgnoreFields     map[string]interface{} `yaml:"ignore_fields" json:"ignore_fields"`
+	FlushTimeout     string                 `yaml:"flush_timeout" json:"flush_timeout"`
+	Breadcrumbs      *bool                  `yaml:"breadcrumbs" json:"breadcrumbs"`
+	BreadcrumbLevel  string                 `yaml:"breadcrumb_level" json:"breadcrumb_level"`
+	MaxBreadcrumbs   *int                   `yaml:"max_breadcrumbs" json:"max_breadcrumbs"`
+	RouteField       *string                `yaml:"route_field" json:"route_field"`
+	RouteDSNs        map[string]string      `yaml:"route_dsns" json:"route_dsns"`
+	SpoolSize        *int                   `yaml:"spool_size" json:"spool_size"`
+	SpoolDir         *string                `yaml:"spool_dir" json:"spool_dir"`
+	Debug            *bool                  `yaml:"debug" json:"debug"`
+	HTTPProxy        *string                `yaml:"http_proxy" json:"http_proxy"`
+	HTTPSProxy       *string                `yaml:"https_proxy" json:"https_proxy"`
+}
+
+// LoadConfig builds a Config from a YAML (.yaml, .yml) or JSON (.json) file.
+// Values not set in the file keep the value of DefaultConfig. ${VAR} and
+// ${VAR:-default} are replaced with environment variables before parsing,
+// so secrets like the Sentry DSN can stay out of the file. Unknown keys are
+// an error.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	data = expandConfigEnv(data)
+
+	var file fileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil {
+			return Config{}, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&file); err != nil {
+			return Config{}, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported config file format %q", filepath.Ext(path))
+	}
+
+	config := DefaultConfig()
+	if err := file.apply(&config); err != nil {
+		return Config{}, fmt.Errorf("loading %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// expandConfigEnv replaces the environment variable references in data
+func expandConfigEnv(data []byte) []byte {
+	return configEnvPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		groups := configEnvPattern.FindSubmatch(match)
+		if value, ok := os.LookupEnv(string(groups[1])); ok {
+			return []byte(value)
+		}
+		return groups[2]
+	})
+}
+
+// apply sets the values of the file on config
+func (f fileConfig) apply(config *Config) error {
+	setString(&config.Environment, f.Environment)
+	setString(&config.AppName, f.AppName)
+	setString(&config.Release, f.Release)
+	setString(&config.HostName, f.HostName)
+	setString(&config.ServerName, f.ServerName)
+	setBool(&config.ReportCaller, f.ReportCaller)
+	setBool(&config.StackTraces, f.StackTraces)
+	setBool(&config.NestedFields, f.NestedFields)
+	setBool(&config.PlainDevFormatter, f.PlainDevFormatter)
+	setBool(&config.ForceJSON, f.ForceJSON)
+	setBool(&config.ForceText, f.ForceText)
+	setString(&config.TimestampFormat, f.TimestampFormat)
+	setBool(&config.TimestampUTC, f.TimestampUTC)
+	setInt(&config.AsyncBufferSize, f.AsyncBufferSize)
+	setInt(&config.AsyncHookWorkers, f.AsyncHookWorkers)
+	setInt(&config.MaxMessageLength, f.MaxMessageLength)
+	setInt(&config.MaxFieldLength, f.MaxFieldLength)
+
+	if f.SampleRate != nil {
+		config.SampleRate = *f.SampleRate
+	}
+	if f.BackpressurePolicy != "" {
+		policy, err := ParseBackpressurePolicy(f.BackpressurePolicy)
+		if err != nil {
+			return err
+		}
+		config.BackpressurePolicy = policy
+	}
+	if f.Level != "" {
+		level, err := logrus.ParseLevel(f.Level)
+		if err != nil {
+			return err
+		}
+		config.Level = level
+	}
+	if len(f.NamedLevels) > 0 {
+		config.NamedLevels = make(map[string]logrus.Level, len(f.NamedLevels))
+		for name, value := range f.NamedLevels {
+			level, err := logrus.ParseLevel(value)
+			if err != nil {
+				return fmt.Errorf("named level %q: %w", name, err)
+			}
+			config.NamedLevels[name] = level
+		}
+	}
+	for k, v := range f.CustomFields {
+		config.CustomFields[k] = v
+	}
+	if f.TraceIDFormat != "" {
+		format, err := ParseTraceIDFormat(f.TraceIDFormat)
+		if err != nil {
+			return err
+		}
+		config.TraceIDFormat = format
+	}
+	setBool(&config.DeadlineRemaining, f.DeadlineRemaining)
+	setBool(&config.ProcessIDs, f.ProcessIDs)
+	setBool(&config.Sequence, f.Sequence)
+	setBool(&config.HostFields, f.HostFields)
+	setBool(&config.AWSMetadata, f.AWSMetadata)
+	setBool(&config.DeploymentFields, f.DeploymentFields)
+	if f.Encoder != "" {
+		encoder, err := ParseEncoder(f.Encoder)
+		if err != nil {
+			return err
+		}
+		config.Encoder = encoder
+	}
+	if f.HookErrorPolicy != "" {
+		policy, err := ParseHookErrorPolicy(f.HookErrorPolicy)
+		if err != nil {
+			return err
+		}
+		config.HookErrorPolicy = policy
+	}
+	if f.DedupWindow != "" {
+		window, err := time.ParseDuration(f.DedupWindow)
+		if err != nil {
+			return fmt.Errorf("dedup window: %w", err)
+		}
+		config.DedupWindow = window
+	}
+	setInt(&config.AdaptiveSamplingThreshold, f.SamplingThreshold)
+	if f.SamplingWindow != "" {
+		window, err := time.ParseDuration(f.SamplingWindow)
+		if err != nil {
+			return fmt.Errorf("adaptive sampling window: %w", err)
+		}
+		config.AdaptiveSamplingWindow = window
+	}
+	setInt(&config.ErrorRateThreshold, f.ErrorRateThreshold)
+	if f.ErrorRateWindow != "" {
+		window, err := time.ParseDuration(f.ErrorRateWindow)
+		if err != nil {
+			return fmt.Errorf("error rate window: %w", err)
+		}
+		config.ErrorRateWindow = window
+	}
+	if f.RedactFields != nil {
+		config.RedactFields = f.RedactFields
+	}
+	if f.FieldPolicies != nil {
+		config.FieldPolicies = f.FieldPolicies
+	}
+	setString(&config.PseudonymizeKey, f.PseudonymizeKey)
+	if f.PseudonymizeFields != nil {
+		config.PseudonymizeFields = f.PseudonymizeFields
+	}
+
+	setString(&config.SyslogNetwork, f.Syslog.Network)
+	setString(&config.SyslogAddress, f.Syslog.Address)
+	if f.Syslog.Facility != "" {
+		facility, err := ParseSyslogFacility(f.Syslog.Facility)
+		if err != nil {
+			return err
+		}
+		config.SyslogFacility = facility
+	}
+
+	return f.Sentry.apply(config)
+}
+
+// apply sets the Sentry values of the file on config
+func (f fileSentryConfig) apply(config *Config) error {
+	setString(&config.SentryDSN, f.DSN)
+	setString(&config.SentryRouteField, f.RouteField)
+	setString(&config.SentrySpoolDir, f.SpoolDir)
+	setString(&config.SentryHTTPProxy, f.HTTPProxy)
+	setString(&config.SentryHTTPSProxy, f.HTTPSProxy)
+	setBool(&config.SentryBreadcrumbs, f.Breadcrumbs)
+	setBool(&config.SentryDebug, f.Debug)
+	setInt(&config.SentryMaxBreadcrumbs, f.MaxBreadcrumbs)
+	setInt(&config.SentrySpoolSize, f.SpoolSize)
+	if f.SampleRate != nil {
+		config.SentrySampleRate = *f.SampleRate
+	}
+	if f.TracesSampleRate != nil {
+		config.TracesSampleRate = *f.TracesSampleRate
+	}
+	if f.TagFields != nil {
+		config.SentryTagFields = f.TagFields
+	}
+	if f.IgnoreMessages != nil {
+		config.SentryIgnoreMessages = f.IgnoreMessages
+	}
+	if f.IgnoreFields != nil {
+		config.SentryIgnoreFields = f.IgnoreFields
+	}
+	if f.RouteDSNs != nil {
+		config.SentryRouteDSNs = f.RouteDSNs
+	}
+
+	for _, value := range f.Levels {
+		level, err := logrus.ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("sentry level: %w", err)
+		}
+		config.SentryLevels = append(config.SentryLevels, level)
+	}
+	if f.BreadcrumbLevel != "" {
+		level, err := logrus.ParseLevel(f.BreadcrumbLevel)
+		if err != nil {
+			return fmt.Errorf("sentry breadcrumb level: %w", err)
+		}
+		config.SentryBreadcrumbLevel = level
+	}
+	if f.FlushTimeout != "" {
+		timeout, err := time.ParseDuration(f.FlushTimeout)
+		if err != nil {
+			return fmt.Errorf("sentry flush timeout: %w", err)
+		}
+		config.SentryFlushTimeout = timeout
+	}
+	return nil
+}
+
+// setString sets *dst to *value when value is set
+func setString(dst *string, value *string) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// setBool sets *dst to *value when value is set
+func setBool(dst *bool, value *bool) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// setInt sets *dst to *value when value is set
+func setInt(dst *int, value *int) {
+	if value != nil {
+		*dst = *value
+	}
+}
@@ -0,0 +1,100 @@
+package aloig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// writeConfigFile writes a configuration file in a temporary directory
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+// TestLoadConfigYAML tests loading a YAML file with environment variables
+func TestLoadConfigYAML(t *testing.T) {
+	t.Setenv("TEST_SENTRY_DSN", "https://public@example.com/1")
+	path := writeConfigFile(t, "aloig.yaml", `
+environment: prod
+app_name: orders
+level: warn
+report_caller: false
+named_levels:
+  db: error
+custom_fields:
+  team: payments
+redact_fields: [password]
+max_field_length: 100
+syslog:
+  network: udp
+  address: ${TEST_SYSLOG_ADDRESS:-localhost:514}
+  facility: local0
+sentry:
+  dsn: ${TEST_SENTRY_DSN}
+  levels: [error, fatal]
+  sample_rate: 0.5
+  flush_timeout: 5s
+  breadcrumbs: true
+  breadcrumb_level: warn
+`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Environment != "prod" || config.AppName != "orders" || config.Level != logrus.WarnLevel || config.ReportCaller {
+		t.Errorf("Unexpected general settings: %+v", config)
+	}
+	if config.NamedLevels["db"] != logrus.ErrorLevel || config.CustomFields["team"] != "payments" || len(config.RedactFields) != 1 {
+		t.Errorf("Unexpected fields settings: %+v", config)
+	}
+	if config.MaxFieldLength != 100 || config.MaxMessageLength != DefaultMaxMessageLength {
+		t.Errorf("Expected the file limit and the default limit, got %d and %d", config.MaxFieldLength, config.MaxMessageLength)
+	}
+	if config.SyslogNetwork != "udp" || config.SyslogAddress != "localhost:514" || config.SyslogFacility != SyslogFacilityLocal0 {
+		t.Errorf("Unexpected syslog settings: %+v", config)
+	}
+	if config.SentryDSN != "https://public@example.com/1" || len(config.SentryLevels) != 2 || config.SentrySampleRate != 0.5 ||
+		config.SentryFlushTimeout != 5*time.Second || config.SentryBreadcrumbLevel != logrus.WarnLevel || !config.SentryBreadcrumbs {
+		t.Errorf("Unexpected Sentry settings: %+v", config)
+	}
+}
+
+// TestLoadConfigJSON tests loading a JSON file
+func TestLoadConfigJSON(t *testing.T) {
+	path := writeConfigFile(t, "aloig.json", `{"environment": "staging", "level": "debug", "sentry": {"tag_fields": ["tenant"]}}`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Environment != "staging" || config.Level != logrus.DebugLevel || len(config.SentryTagFields) != 1 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+}
+
+// TestLoadConfigErrors tests that invalid files are rejected
+func TestLoadConfigErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown.yaml":  "levle: info",
+		"unknown.json":  `{"levle": "info"}`,
+		"level.yaml":    "level: loud",
+		"facility.yaml": "syslog: {facility: nowhere}",
+		"timeout.yaml":  "sentry: {flush_timeout: soon}",
+		"config.toml":   "level = 'info'",
+	} {
+		if _, err := LoadConfig(writeConfigFile(t, name, content)); err == nil {
+			t.Errorf("Expected an error loading %s", name)
+		}
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error loading a missing file")
+	}
+}
@@ -0,0 +1,73 @@
+package aloig
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ContainerIDField holds the ID of the container running the process
+const ContainerIDField = "container_id"
+
+var (
+	// cgroupContainerPattern matches the container ID at the end of a cgroup
+	// path, e.g. /docker/<id>, /kubepods/.../<id> or
+	// /system.slice/cri-containerd-<id>.scope
+	cgroupContainerPattern = regexp.MustCompile(`([0-9a-f]{64})(?:\.scope)?$`)
+
+	// mountContainerPattern matches the container ID in the files Docker
+	// mounts into its containers, e.g. /var/lib/docker/containers/<id>/hostname
+	mountContainerPattern = regexp.MustCompile(`/containers/([0-9a-f]{64})/`)
+)
+
+var (
+	containerIDOnce  sync.Once
+	containerIDValue string
+)
+
+// containerID returns the ID of the Docker or containerd container running
+// the process, or "" outside of a container. It is read once from the
+// cgroups of the process (cgroup v1) or, with cgroup v2 where they don't
+// show it, from its mounts.
+func containerID() string {
+	containerIDOnce.Do(func() {
+		if cgroup, err := os.ReadFile("/proc/self/cgroup"); err == nil {
+			containerIDValue = containerIDFromCgroup(string(cgroup))
+		}
+		if containerIDValue != "" {
+			return
+		}
+		if mountinfo, err := os.ReadFile("/proc/self/mountinfo"); err == nil {
+			containerIDValue = containerIDFromMountinfo(string(mountinfo))
+		}
+	})
+	return containerIDValue
+}
+
+// containerIDFromCgroup returns the container ID found in the paths of a
+// /proc/<pid>/cgroup file
+func containerIDFromCgroup(cgroup string) string {
+	for _, line := range strings.Split(cgroup, "\n") {
+		// hierarchy-ID:controllers:path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if match := cgroupContainerPattern.FindStringSubmatch(parts[2]); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// containerIDFromMountinfo returns the container ID found in the mount roots
+// of a /proc/<pid>/mountinfo file
+func containerIDFromMountinfo(mountinfo string) string {
+	for _, line := range strings.Split(mountinfo, "\n") {
+		if match := mountContainerPattern.FindStringSubmatch(line); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package aloig
+
+import "testing"
+
+// TestContainerIDFromCgroup tests the detection of Docker, Kubernetes and
+// containerd container IDs in cgroup v1 paths
+func TestContainerIDFromCgroup(t *testing.T) {
+	const id = "3c8f1d5a9b2e4f6a7c0d8e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b"
+	tests := []struct {
+		name   string
+		cgroup string
+		want   string
+	}{
+		{"docker", "12:pids:/docker/" + id + "\n11:memory:/docker/" + id + "\n", id},
+		{"kubernetes", "4:memory:/kubepods/burstable/pod5f1c2e3a-1b2c-4d5e-8f9a-0b1c2d3e4f5a/" + id + "\n", id},
+		{"containerd", "0::/system.slice/cri-containerd-" + id + ".scope\n", id},
+		{"host", "12:pids:/user.slice\n0::/init.scope\n", ""},
+		{"cgroup v2", "0::/\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerIDFromCgroup(tt.cgroup); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestContainerIDFromMountinfo tests the detection of Docker container IDs in
+// the mounts under cgroup v2
+func TestContainerIDFromMountinfo(t *testing.T) {
+	const id = "3c8f1d5a9b2e4f6a7c0d8e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b"
+	mountinfo := "612 590 0:54 / / rw,relatime master:290 - overlay overlay rw\n" +
+		"629 612 254:1 /var/lib/docker/containers/" + id + "/hostname /etc/hostname rw,relatime - ext4 /dev/vda1 rw\n"
+	if got := containerIDFromMountinfo(mountinfo); got != id {
+		t.Errorf("Expected %q, got %q", id, got)
+	}
+	if got := containerIDFromMountinfo("22 1 8:1 / / rw,relatime - ext4 /dev/sda1 rw\n"); got != "" {
+		t.Errorf("Expected no container ID on the host, got %q", got)
+	}
+}
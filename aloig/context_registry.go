@@ -0,0 +1,74 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// registeredContextField is a context key whose value is logged as a field
+type registeredContextField struct {
+	key  interface{}
+	name string
+}
+
+var (
+	contextRegistryMu sync.RWMutex
+	// contextRegistry is replaced, never modified, so it can be read after
+	// releasing the lock
+	contextRegistry []registeredContextField
+)
+
+// RegisterContextField makes the context log calls and ExtractContextFields
+// add the value stored under key in the context as the fieldName field, so
+// applications can propagate their own correlation IDs like trace_id:
+//
+//	type tenantKey struct{}
+//
+//	aloig.RegisterContextField(tenantKey{}, "tenant_id")
+//	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+//	aloig.InfoContext(ctx, "Order created") // tenant_id=acme
+//
+// Registering a key again changes its field name. The built-in IDs take
+// precedence over registered fields with the same name.
+func RegisterContextField(key interface{}, fieldName string) error {
+	if key == nil {
+		return fmt.Errorf("context field %q without key", fieldName)
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		return fmt.Errorf("context field %q key of type %T is not comparable", fieldName, key)
+	}
+	if fieldName == "" {
+		return fmt.Errorf("context key %v without field name", key)
+	}
+
+	contextRegistryMu.Lock()
+	defer contextRegistryMu.Unlock()
+
+	registry := make([]registeredContextField, 0, len(contextRegistry)+1)
+	for _, field := range contextRegistry {
+		if field.key != key {
+			registry = append(registry, field)
+		}
+	}
+	contextRegistry = append(registry, registeredContextField{key: key, name: fieldName})
+	return nil
+}
+
+// registeredContextFields returns the registered context keys
+func registeredContextFields() []registeredContextField {
+	contextRegistryMu.RLock()
+	defer contextRegistryMu.RUnlock()
+	return contextRegistry
+}
+
+// addRegisteredContextFields adds the values of the registered keys found in
+// ctx to data
+func addRegisteredContextFields(ctx context.Context, registry []registeredContextField, data map[string]interface{}) {
+	for _, field := range registry {
+		if value := ctx.Value(field.key); value != nil {
+			data[field.name] = value
+		}
+	}
+}
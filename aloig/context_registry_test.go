@@ -0,0 +1,79 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+type tenantKey struct{}
+
+type orderKey string
+
+// TestRegisterContextField tests that registered context keys are logged
+func TestRegisterContextField(t *testing.T) {
+	original := contextRegistry
+	defer func() { contextRegistry = original }()
+
+	if err := RegisterContextField(tenantKey{}, "tenant"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := RegisterContextField(tenantKey{}, "tenant_id"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := RegisterContextField(orderKey("order"), "order_id"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	ctx = context.WithValue(ctx, orderKey("order"), 42)
+	ctx = WithTraceID(ctx, "trace-123")
+
+	fields := ExtractContextFields(ctx)
+	if fields["tenant_id"] != "acme" || fields["order_id"] != 42 || fields["trace_id"] != "trace-123" {
+		t.Errorf("Expected the registered fields, got %v", fields)
+	}
+	if _, ok := fields["tenant"]; ok {
+		t.Errorf("Expected the field to be renamed, got %v", fields)
+	}
+
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.InfoContext(ctx, "Order created")
+	for _, expected := range []string{"tenant_id=acme", "order_id=42", "trace_id=trace-123"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in the output, got: %s", expected, buf.String())
+		}
+	}
+
+	buf.Reset()
+	logger.InfoContext(context.Background(), "No tenant")
+	if strings.Contains(buf.String(), "tenant_id") {
+		t.Errorf("Expected no field for missing values, got: %s", buf.String())
+	}
+}
+
+// TestRegisterContextFieldErrors tests the rejected registrations
+func TestRegisterContextFieldErrors(t *testing.T) {
+	original := contextRegistry
+	defer func() { contextRegistry = original }()
+
+	testCases := []struct {
+		name  string
+		key   interface{}
+		field string
+	}{
+		{"nil key", nil, "tenant_id"},
+		{"uncomparable key", []string{"tenant"}, "tenant_id"},
+		{"empty field name", tenantKey{}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := RegisterContextField(tc.key, tc.field); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
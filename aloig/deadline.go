@@ -0,0 +1,34 @@
+package aloig
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DeadlineRemainingField holds the time left before the deadline of the
+// context of an entry, in milliseconds
+const DeadlineRemainingField = "deadline_remaining_ms"
+
+// DeadlineHook adds the time left before the deadline of the context of the
+// entries, negative once it passed, which shows how the time budget of a
+// request was spent when debugging timeout cascades across services. The
+// entries logged without a context or with one without a deadline are left
+// unchanged. NewLogger adds it when Config.DeadlineRemaining is set.
+type DeadlineHook struct{}
+
+// Levels returns the levels to which the hook will be applied
+func (DeadlineHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the remaining time of the deadline
+func (DeadlineHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if deadline, ok := entry.Context.Deadline(); ok {
+		entry.Data[DeadlineRemainingField] = float64(time.Until(deadline)) / float64(time.Millisecond)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package aloig
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestDeadlineHook tests that the remaining time is only added to the entries
+// logged with a deadline
+func TestDeadlineHook(t *testing.T) {
+	logger := NewLogger(Config{Environment: "dev", Level: logrus.InfoLevel, DeadlineRemaining: true}).(*logrusLogger)
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+	logger.logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	logger.InfoContext(ctx, "with deadline")
+	output := buf.String()
+	if !strings.Contains(output, DeadlineRemainingField+"=3") {
+		t.Errorf("Expected about an hour remaining, got: %s", output)
+	}
+
+	buf.Reset()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	logger.InfoContext(expired, "expired")
+	if output := buf.String(); !strings.Contains(output, DeadlineRemainingField+"=-") {
+		t.Errorf("Expected a negative remaining time, got: %s", output)
+	}
+
+	buf.Reset()
+	logger.InfoContext(context.Background(), "without deadline")
+	logger.Info("without context")
+	if output := buf.String(); strings.Contains(output, DeadlineRemainingField) {
+		t.Errorf("Expected no remaining time without deadline, got: %s", output)
+	}
+}
@@ -0,0 +1,214 @@
+package aloig
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RepeatedField holds the number of entries suppressed by the deduplication
+// in the summary written at the end of the window
+const RepeatedField = "repeated"
+
+// maxDedupEntries is the number of distinct entries tracked at a time;
+// entries beyond it are written without deduplication
+const maxDedupEntries = 10000
+
+// dedupState is an entry written during the current window
+type dedupState struct {
+	start time.Time
+
+	// entry is a copy of the first repeated entry, for the summary. The
+	// entries written once aren't copied.
+	entry    *logrus.Entry
+	repeated int
+}
+
+// dedupFormatter writes the first of the entries with the same level,
+// message, caller and fields within a window and nothing for the following
+// ones. When the window ends, a summary of the suppressed entries ("...
+// (repeated 412 times in 10s)") is written with the fields of the entry.
+// Hooks still see every entry.
+type dedupFormatter struct {
+	logrus.Formatter
+
+	logger *logrus.Logger
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[uint64]*dedupState
+
+	// ticking is set while the ticker ending the windows runs
+	ticking bool
+}
+
+// newDedupFormatter returns a formatter deduplicating the entries of logger
+// formatted with formatter within window
+func newDedupFormatter(formatter logrus.Formatter, logger *logrus.Logger, window time.Duration) *dedupFormatter {
+	return &dedupFormatter{
+		Formatter: formatter,
+		logger:    logger,
+		window:    window,
+		entries:   make(map[uint64]*dedupState),
+	}
+}
+
+// Format formats the entry unless it repeats an entry of the window
+func (f *dedupFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	key := dedupKey(entry)
+
+	f.mu.Lock()
+	if state, ok := f.entries[key]; ok {
+		if state.entry == nil {
+			state.entry = copyEntry(entry)
+		}
+		state.repeated++
+		f.mu.Unlock()
+		atomic.AddUint64(&stats.suppressed, 1)
+		return nil, nil
+	}
+	if len(f.entries) < maxDedupEntries {
+		f.entries[key] = &dedupState{start: time.Now()}
+		if !f.ticking {
+			f.ticking = true
+			go f.tick()
+		}
+	}
+	f.mu.Unlock()
+
+	return f.Formatter.Format(entry)
+}
+
+// tick ends the windows as they expire, checking them every quarter of the
+// window. It returns once no window is open; Format starts it again.
+func (f *dedupFormatter) tick() {
+	interval := f.window / 4
+	if interval <= 0 {
+		interval = f.window
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if !f.endWindows(now) {
+			return
+		}
+	}
+}
+
+// endWindows writes the summaries of the windows expired at now. It returns
+// whether windows are still open.
+func (f *dedupFormatter) endWindows(now time.Time) bool {
+	var ended []*dedupState
+	f.mu.Lock()
+	for key, state := range f.entries {
+		if now.Sub(state.start) >= f.window {
+			ended = append(ended, state)
+			delete(f.entries, key)
+		}
+	}
+	open := len(f.entries) > 0
+	f.ticking = open
+	f.mu.Unlock()
+
+	f.writeSummaries(ended)
+	return open
+}
+
+// Flush writes the summaries of the current windows, e.g. before exiting
+func (f *dedupFormatter) Flush() {
+	var ended []*dedupState
+	f.mu.Lock()
+	for _, state := range f.entries {
+		ended = append(ended, state)
+	}
+	f.entries = make(map[uint64]*dedupState)
+	f.mu.Unlock()
+
+	f.writeSummaries(ended)
+}
+
+// writeSummaries writes the summaries of the entries suppressed in the ended
+// windows, if any, in a single write. They bypass the hooks, which already
+// saw every entry.
+func (f *dedupFormatter) writeSummaries(ended []*dedupState) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	for _, state := range ended {
+		if state.repeated == 0 {
+			continue
+		}
+		summary := state.entry
+		summary.Data[RepeatedField] = state.repeated
+		summary.Message = fmt.Sprintf("%s (repeated %d times in %s)", summary.Message, state.repeated, f.window)
+		summary.Time = time.Now()
+
+		if serialized, err := f.Formatter.Format(summary); err == nil {
+			buf.Write(serialized)
+		}
+	}
+	if buf.Len() > 0 {
+		// The output is a syncWriter, see NewLogger
+		_, _ = f.logger.Out.Write(buf.Bytes())
+	}
+}
+
+// syncWriter serializes the writes to out, for the loggers whose summaries
+// are written outside of the logger lock by a ticker
+type syncWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// Write writes p to the output
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.out.Write(p)
+}
+
+// dedupKey returns the hash of the level, message, caller and fields of the
+// entry, except the Sentry event ID
+func dedupKey(entry *logrus.Entry) uint64 {
+	h := fnv.New64a()
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteString(entry.Level.String())
+	buf.WriteByte(0)
+	buf.WriteString(entry.Message)
+	if entry.Caller != nil {
+		buf.WriteByte(0)
+		buf.WriteString(entry.Caller.File)
+		buf.WriteString(strconv.Itoa(entry.Caller.Line))
+	}
+
+	keysPtr := keysPool.Get().(*[]string)
+	keys := (*keysPtr)[:0]
+	for k := range entry.Data {
+		// Every entry reported to Sentry has its own event ID
+		if k != SentryEventIDField {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf.WriteByte(0)
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		fmt.Fprint(buf, entry.Data[k])
+	}
+	*keysPtr = keys[:0]
+	keysPool.Put(keysPtr)
+
+	_, _ = h.Write(buf.Bytes())
+	return h.Sum64()
+}
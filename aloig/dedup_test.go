@@ -0,0 +1,140 @@
+package aloig
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lockedBuffer is a buffer written from several goroutines
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestDedupFormatter tests that repeated entries are suppressed and
+// summarized at the end of the window
+func TestDedupFormatter(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	dedup := newDedupFormatter(logger.logger.Formatter, logger.logger, time.Hour)
+	logger.logger.SetFormatter(dedup)
+
+	for i := 0; i < 5; i++ {
+		logger.WithField("host", "db-1").Warn("connection refused")
+	}
+	logger.WithField("host", "db-2").Warn("connection refused")
+	logger.Info("single")
+
+	if count := strings.Count(buf.String(), "connection refused"); count != 2 {
+		t.Errorf("Expected one entry per host, got %d: %s", count, buf.String())
+	}
+
+	buf.Reset()
+	dedup.Flush()
+	output := buf.String()
+	if strings.Count(output, "\n") != 1 || !strings.Contains(output, `msg="connection refused (repeated 4 times in 1h0m0s)"`) ||
+		!strings.Contains(output, "host=db-1") || !strings.Contains(output, "repeated=4") || !strings.Contains(output, "level=warning") {
+		t.Errorf("Expected a single summary of the repeated entries, got: %s", output)
+	}
+
+	buf.Reset()
+	logger.WithField("host", "db-1").Warn("connection refused")
+	if !strings.Contains(buf.String(), "connection refused") {
+		t.Errorf("Expected a new window after the summary, got: %s", buf.String())
+	}
+}
+
+// TestNewLoggerDedupWindow tests that the summary is written when the
+// window ends
+func TestNewLoggerDedupWindow(t *testing.T) {
+	logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel, DedupWindow: 20 * time.Millisecond})
+	buf := &lockedBuffer{}
+	logger.(*logrusLogger).logger.SetOutput(buf)
+
+	for i := 0; i < 3; i++ {
+		logger.Error("payment failed")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), `"repeated":2`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a summary, got: %s", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if count := strings.Count(buf.String(), "\n"); count != 2 {
+		t.Errorf("Expected the first entry and the summary, got: %s", buf.String())
+	}
+}
+
+// TestDedupTicker tests that a single ticker ends the windows while entries
+// are written concurrently, and stops once they are closed
+func TestDedupTicker(t *testing.T) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	var buf bytes.Buffer
+	out := &syncWriter{out: &buf}
+	logger.logger.SetOutput(out)
+	dedup := newDedupFormatter(logger.logger.Formatter, logger.logger, 10*time.Millisecond)
+	logger.logger.SetFormatter(dedup)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				logger.Warn("connection refused")
+				logger.WithField("attempt", j).Info("retrying")
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		dedup.mu.Lock()
+		ticking, open := dedup.ticking, len(dedup.entries)
+		dedup.mu.Unlock()
+		if !ticking && open == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the ticker to stop, got %d open windows", open)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	out.mu.Lock()
+	output := buf.String()
+	out.mu.Unlock()
+	if !strings.Contains(output, "connection refused (repeated") {
+		t.Errorf("Expected summaries of the repeated entries, got: %s", output)
+	}
+}
+
+// TestDedupKeyIgnoresSentryEventID tests that entries only differing by their
+// Sentry event ID are deduplicated
+func TestDedupKeyIgnoresSentryEventID(t *testing.T) {
+	first := &logrus.Entry{Message: "payment failed", Level: logrus.ErrorLevel, Data: logrus.Fields{"order": 1, SentryEventIDField: "a"}}
+	second := &logrus.Entry{Message: "payment failed", Level: logrus.ErrorLevel, Data: logrus.Fields{"order": 1, SentryEventIDField: "b"}}
+	if dedupKey(first) != dedupKey(second) {
+		t.Error("Expected the same key for entries with different Sentry event IDs")
+	}
+}
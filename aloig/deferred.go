@@ -0,0 +1,88 @@
+package aloig
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DeferredEntry accumulates the fields of an operation while it runs and
+// logs them as a single entry when it ends, one wide event per operation
+// instead of many fragmented lines. It is safe for concurrent use.
+type DeferredEntry struct {
+	ctx    context.Context
+	logger Logger
+	name   string
+	start  time.Time
+	once   sync.Once
+
+	mu     sync.Mutex
+	fields map[string]interface{}
+}
+
+// Begin starts the deferred entry of the named operation on the singleton
+// logger. Set fields as the operation goes and call End when it ends:
+//
+//	e := aloig.Begin(ctx, "handle_order")
+//	defer func() { e.End(err) }()
+//	e.Set("order_id", order.ID)
+//	...
+//	e.Set("items", len(order.Items))
+func Begin(ctx context.Context, name string) *DeferredEntry {
+	return NewDeferredEntry(ctx, nil, name)
+}
+
+// NewDeferredEntry starts the deferred entry of the named operation on
+// logger. A nil logger writes to the singleton logger.
+func NewDeferredEntry(ctx context.Context, logger Logger, name string) *DeferredEntry {
+	return &DeferredEntry{ctx: ctx, logger: logger, name: name, start: time.Now(), fields: make(map[string]interface{})}
+}
+
+// Set adds a field to the entry, replacing the value set before with the
+// same key
+func (e *DeferredEntry) Set(key string, value interface{}) *DeferredEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fields[key] = value
+	return e
+}
+
+// SetFields adds fields to the entry, replacing the values set before with
+// the same keys
+func (e *DeferredEntry) SetFields(fields map[string]interface{}) *DeferredEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for k, v := range fields {
+		e.fields[k] = v
+	}
+	return e
+}
+
+// End logs the entry with the fields set, the duration and the outcome of
+// the operation, at error level with the error when err is not nil. Only the
+// first call logs; the fields set afterwards are ignored.
+func (e *DeferredEntry) End(err error) {
+	e.once.Do(func() {
+		logger := e.logger
+		if logger == nil {
+			logger = GetLogger()
+		}
+
+		e.mu.Lock()
+		fields := make(map[string]interface{}, len(e.fields)+3)
+		for k, v := range e.fields {
+			fields[k] = v
+		}
+		e.mu.Unlock()
+		fields["operation"] = e.name
+		fields["duration_ms"] = float64(time.Since(e.start)) / float64(time.Millisecond)
+		fields["success"] = err == nil
+
+		entry := logger.WithFields(fields)
+		if err != nil {
+			entry.WithError(err).ErrorContext(e.ctx, e.name+" failed")
+			return
+		}
+		entry.InfoContext(e.ctx, e.name+" completed")
+	})
+}
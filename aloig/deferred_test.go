@@ -0,0 +1,71 @@
+package aloig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestDeferredEntry tests that the fields set during the operation are
+// logged in a single entry when it ends
+func TestDeferredEntry(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	ctx := WithTraceID(context.Background(), "trace-order")
+
+	e := NewDeferredEntry(ctx, logger, "handle_order")
+	e.Set("order_id", "o-1").Set("items", 2)
+	var wg sync.WaitGroup
+	for _, step := range []string{"reserved", "charged"} {
+		wg.Add(1)
+		go func(step string) {
+			defer wg.Done()
+			e.Set(step, true)
+		}(step)
+	}
+	wg.Wait()
+	e.SetFields(map[string]interface{}{"items": 3, "carrier": "ups"})
+
+	if buf.Len() != 0 {
+		t.Fatalf("Expected nothing logged before End, got: %s", buf.String())
+	}
+	e.End(nil)
+	e.Set("late", true)
+	e.End(errors.New("ignored"))
+
+	output := buf.String()
+	if strings.Count(output, "\n") != 1 {
+		t.Fatalf("Expected a single entry, got: %s", output)
+	}
+	for _, expected := range []string{"level=info", `msg="handle_order completed"`, "operation=handle_order", "order_id=o-1", "items=3", "carrier=ups", "reserved=true", "charged=true", "success=true", "duration_ms=", "trace_id=trace-order"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "late") {
+		t.Errorf("Expected the fields set after End to be ignored, got: %s", output)
+	}
+}
+
+// TestBeginFailure tests that a failed operation is logged at error level
+// on the singleton logger
+func TestBeginFailure(t *testing.T) {
+	originalLog := log
+	defer func() { log = originalLog }()
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	log = logger
+
+	e := Begin(context.Background(), "handle_order")
+	e.Set("order_id", "o-2")
+	e.End(errors.New("card declined"))
+
+	output := buf.String()
+	for _, expected := range []string{"level=error", `msg="handle_order failed"`, "order_id=o-2", "success=false", `error="card declined"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
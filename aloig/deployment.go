@@ -0,0 +1,80 @@
+package aloig
+
+import (
+	"os"
+	"sync"
+)
+
+// BuildInfo describes the deployed build, typically from variables injected
+// with -ldflags "-X main.gitSHA=..." at build time
+type BuildInfo struct {
+	// GitSHA is the commit the binary was built from (git_sha field)
+	GitSHA string
+
+	// BuildTime is when the binary was built (build_time field)
+	BuildTime string
+
+	// PipelineID identifies the CI pipeline that built or deployed the
+	// binary (ci_pipeline_id field)
+	PipelineID string
+}
+
+var (
+	buildInfoMu sync.RWMutex
+	buildInfo   BuildInfo
+)
+
+// SetBuildInfo registers the deployment metadata added to the entries of the
+// loggers created afterwards with Config.DeploymentFields, taking precedence
+// over the environment variables. Call it at the top of main, before the
+// first log:
+//
+//	var gitSHA, buildTime string // set with -ldflags -X
+//
+//	func main() {
+//		aloig.SetBuildInfo(aloig.BuildInfo{GitSHA: gitSHA, BuildTime: buildTime})
+//		...
+//	}
+func SetBuildInfo(info BuildInfo) {
+	buildInfoMu.Lock()
+	defer buildInfoMu.Unlock()
+	buildInfo = info
+}
+
+// deploymentEnvVars are the conventional variables holding the deployment
+// metadata, by field, in order of precedence
+var deploymentEnvVars = []struct {
+	field string
+	names []string
+}{
+	{"git_sha", []string{"GIT_SHA", "GIT_COMMIT", "COMMIT_SHA", "SOURCE_VERSION", "GITHUB_SHA", "CI_COMMIT_SHA", "CIRCLE_SHA1", "BUILDKITE_COMMIT"}},
+	{"build_time", []string{"BUILD_TIME", "BUILD_DATE", "BUILD_TIMESTAMP"}},
+	{"ci_pipeline_id", []string{"CI_PIPELINE_ID", "GITHUB_RUN_ID", "BUILDKITE_BUILD_ID", "CIRCLE_WORKFLOW_ID", "BUILD_ID"}},
+}
+
+// deploymentFields returns the registered deployment metadata, falling back
+// on the conventional environment variables for what wasn't registered
+func deploymentFields() map[string]interface{} {
+	buildInfoMu.RLock()
+	registered := map[string]string{
+		"git_sha":        buildInfo.GitSHA,
+		"build_time":     buildInfo.BuildTime,
+		"ci_pipeline_id": buildInfo.PipelineID,
+	}
+	buildInfoMu.RUnlock()
+
+	fields := make(map[string]interface{}, len(deploymentEnvVars))
+	for _, v := range deploymentEnvVars {
+		value := registered[v.field]
+		for _, name := range v.names {
+			if value != "" {
+				break
+			}
+			value = os.Getenv(name)
+		}
+		if value != "" {
+			fields[v.field] = value
+		}
+	}
+	return fields
+}
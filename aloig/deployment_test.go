@@ -0,0 +1,65 @@
+package aloig
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// clearDeploymentEnv unsets the deployment variables and the registered
+// build info until the test ends
+func clearDeploymentEnv(t *testing.T) {
+	for _, v := range deploymentEnvVars {
+		for _, name := range v.names {
+			t.Setenv(name, "")
+		}
+	}
+	t.Cleanup(func() { SetBuildInfo(BuildInfo{}) })
+}
+
+// TestDeploymentFields tests that the registered build info takes precedence
+// over the environment variables
+func TestDeploymentFields(t *testing.T) {
+	clearDeploymentEnv(t)
+	if fields := deploymentFields(); len(fields) != 0 {
+		t.Errorf("Expected no fields, got %v", fields)
+	}
+
+	t.Setenv("GITHUB_SHA", "4f2a9c1")
+	t.Setenv("BUILD_DATE", "2024-05-01T10:00:00Z")
+	t.Setenv("GITHUB_RUN_ID", "981")
+	fields := deploymentFields()
+	if fields["git_sha"] != "4f2a9c1" || fields["build_time"] != "2024-05-01T10:00:00Z" || fields["ci_pipeline_id"] != "981" {
+		t.Errorf("Expected the fields from the environment, got %v", fields)
+	}
+
+	t.Setenv("GIT_SHA", "77be0d3")
+	SetBuildInfo(BuildInfo{PipelineID: "1024"})
+	fields = deploymentFields()
+	if fields["git_sha"] != "77be0d3" || fields["ci_pipeline_id"] != "1024" || fields["build_time"] != "2024-05-01T10:00:00Z" {
+		t.Errorf("Expected the registered values and the first variable set to win, got %v", fields)
+	}
+}
+
+// TestNewLoggerDeploymentFields tests that the deployment fields are only
+// added when enabled
+func TestNewLoggerDeploymentFields(t *testing.T) {
+	clearDeploymentEnv(t)
+	SetBuildInfo(BuildInfo{GitSHA: "4f2a9c1", BuildTime: "2024-05-01T10:00:00Z"})
+
+	for _, enabled := range []bool{false, true} {
+		logger := NewLogger(Config{Environment: "dev", Level: logrus.InfoLevel, DeploymentFields: enabled}).(*logrusLogger)
+		var buf bytes.Buffer
+		logger.logger.SetOutput(&buf)
+		logger.logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+		logger.Info("started")
+
+		output := buf.String()
+		if added := strings.Contains(output, "git_sha=4f2a9c1") && strings.Contains(output, "build_time="); added != enabled {
+			t.Errorf("Expected the deployment fields only when enabled (%v), got: %s", enabled, output)
+		}
+	}
+}
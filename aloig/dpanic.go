@@ -0,0 +1,57 @@
+package aloig
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// SetDevelopment enables the development behaviors of the singleton logger
+// and its children, such as DPanic panicking. NewLogger enables them for the
+// loggers of the dev environment only.
+func SetDevelopment(enabled bool) {
+	if l, ok := GetLogger().(*logrusLogger); ok {
+		l.setDevelopment(enabled)
+	}
+}
+
+// setDevelopment enables the development behaviors of the logger and its
+// children
+func (l *logrusLogger) setDevelopment(enabled bool) {
+	if l.settings == nil {
+		return
+	}
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&l.settings.development, value)
+}
+
+// isDevelopment checks if the development behaviors are enabled
+func (l *logrusLogger) isDevelopment() bool {
+	return l.settings != nil && atomic.LoadInt32(&l.settings.development) == 1
+}
+
+func (l *logrusLogger) DPanic(args ...interface{}) {
+	if l.isDevelopment() {
+		l.Panic(args...)
+		return
+	}
+	l.Error(args...)
+}
+
+func (l *logrusLogger) DPanicf(format string, args ...interface{}) {
+	if l.isDevelopment() {
+		l.Panicf(format, args...)
+		return
+	}
+	l.Errorf(format, args...)
+}
+
+func (l *logrusLogger) DPanicContext(ctx context.Context, args ...interface{}) {
+	l.withContextFields(ctx).DPanic(args...)
+}
+
+func (l *logrusLogger) DPanicfContext(ctx context.Context, format string, args ...interface{}) {
+	l.withContextFields(ctx).DPanicf(format, args...)
+}
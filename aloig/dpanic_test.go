@@ -0,0 +1,57 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestDPanic tests that DPanic only panics in development
+func TestDPanic(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	ctx := WithTraceID(context.Background(), "trace-dpanic")
+
+	logger.DPanicContext(ctx, "unexpected state")
+	if output := buf.String(); !strings.Contains(output, "level=error") || !strings.Contains(output, "trace_id=trace-dpanic") {
+		t.Errorf("Expected an error entry, got: %s", output)
+	}
+
+	// Children share the development behaviors of their parent
+	child := logger.WithField("component", "orders")
+	logger.setDevelopment(true)
+	buf.Reset()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected DPanic to panic in development")
+			}
+		}()
+		child.DPanicf("unexpected state %d", 42)
+	}()
+	if output := buf.String(); !strings.Contains(output, "level=panic") || !strings.Contains(output, `msg="unexpected state 42"`) {
+		t.Errorf("Expected a panic entry, got: %s", output)
+	}
+}
+
+// TestSetDevelopment tests that SetDevelopment changes the singleton logger
+// only
+func TestSetDevelopment(t *testing.T) {
+	originalLog := log
+	defer func() { log = originalLog }()
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	other, _ := newBufferLogger(logrus.InfoLevel)
+	log = logger
+
+	SetDevelopment(true)
+	if !logger.isDevelopment() || other.isDevelopment() {
+		t.Error("Expected only the singleton logger to be in development")
+	}
+	if NewLogger(Config{Environment: "prod"}).(*logrusLogger).isDevelopment() {
+		t.Error("Expected a prod logger not to be in development")
+	}
+	if !NewLogger(Config{Environment: "dev"}).(*logrusLogger).isDevelopment() {
+		t.Error("Expected a dev logger to be in development")
+	}
+}
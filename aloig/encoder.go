@@ -0,0 +1,126 @@
+package aloig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Encoder is the library serializing the entries of the loggers created
+// with NewLogger into their output. It is the logrus formatter of the
+// loggers: the entries are still built, filtered and passed to the hooks by
+// logrus, whatever the encoder.
+type Encoder int
+
+const (
+	// EncoderLogrus serializes the entries with the logrus JSON formatter,
+	// or the PrettyFormatter for text output
+	EncoderLogrus Encoder = iota
+
+	// EncoderSlog serializes the entries with a log/slog handler
+	EncoderSlog
+
+	// EncoderZerolog serializes the entries with zerolog
+	EncoderZerolog
+)
+
+// encoderNames are the names of the encoders
+var encoderNames = []string{"logrus", "slog", "zerolog"}
+
+// String returns the name of the encoder
+func (b Encoder) String() string {
+	if b < 0 || int(b) >= len(encoderNames) {
+		return fmt.Sprintf("Encoder(%d)", int(b))
+	}
+	return encoderNames[b]
+}
+
+// ParseEncoder returns the encoder with the given name ("logrus", "slog" or
+// "zerolog")
+func ParseEncoder(name string) (Encoder, error) {
+	for i, encoder := range encoderNames {
+		if strings.EqualFold(name, encoder) {
+			return Encoder(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown encoder %q", name)
+}
+
+var (
+	encodersMu sync.Mutex
+
+	// encoderFormatters create the formatter of the encoders other than
+	// logrus, registered by the files implementing them
+	encoderFormatters = make(map[Encoder]func(config Config) logrus.Formatter)
+)
+
+// registerEncoder makes a encoder available to NewLogger. newFormatter
+// returns the formatter serializing the entries; a formatter writing them
+// itself returns no bytes.
+func registerEncoder(encoder Encoder, newFormatter func(config Config) logrus.Formatter) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoderFormatters[encoder] = newFormatter
+}
+
+// encoderFormatter returns the formatter of the encoder of config. When the
+// encoder isn't available in this build, it returns the logrus formatter
+// with an error.
+func encoderFormatter(config Config) (logrus.Formatter, error) {
+	if config.Encoder == EncoderLogrus {
+		return logrusFormatter(config), nil
+	}
+
+	encodersMu.Lock()
+	newFormatter := encoderFormatters[config.Encoder]
+	encodersMu.Unlock()
+	if newFormatter == nil {
+		return logrusFormatter(config), fmt.Errorf("encoder %s is not available in this build", config.Encoder)
+	}
+	return newFormatter(config), nil
+}
+
+// logrusFormatter returns the formatter of EncoderLogrus: JSON, or text for
+// a human (see textOutput)
+func logrusFormatter(config Config) logrus.Formatter {
+	if !textOutput(config, isTerminal(os.Stdout)) {
+		return &CallerJSONFormatter{
+			JSONFormatter: &logrus.JSONFormatter{TimestampFormat: config.TimestampFormat},
+			StackTraces:   config.StackTraces,
+			NestFields:    config.NestedFields,
+		}
+	}
+	if config.PlainDevFormatter {
+		layout := config.TimestampFormat
+		if layout == TimestampEpochMillis {
+			layout = ""
+		}
+		return &logrus.TextFormatter{TimestampFormat: layout, FullTimestamp: layout != ""}
+	}
+	return &PrettyFormatter{TimestampFormat: config.TimestampFormat}
+}
+
+// encoderLevel returns the name of the level of the entry, or of its custom
+// level, and prepares its fields for the encoders writing them next to the
+// time, level and message like the logrus JSON formatter: the custom level
+// moves the logrus level to BaseLevelField, and the fields clashing with the
+// time, level and message keys are prefixed with "fields."
+func encoderLevel(entry *logrus.Entry) string {
+	for _, key := range []string{logrus.FieldKeyTime, logrus.FieldKeyLevel, logrus.FieldKeyMsg} {
+		if value, ok := entry.Data[key]; ok {
+			delete(entry.Data, key)
+			entry.Data["fields."+key] = value
+		}
+	}
+
+	name, ok := entry.Data[LevelNameField].(string)
+	if !ok {
+		return entry.Level.String()
+	}
+	delete(entry.Data, LevelNameField)
+	entry.Data[BaseLevelField] = entry.Level.String()
+	return name
+}
@@ -0,0 +1,112 @@
+//go:build go1.21
+
+package aloig
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	registerEncoder(EncoderSlog, func(config Config) logrus.Formatter {
+		return &slogFormatter{
+			text:            textOutput(config, isTerminal(os.Stdout)),
+			timestampFormat: config.TimestampFormat,
+			stackTraces:     config.StackTraces,
+			nestFields:      config.NestedFields,
+		}
+	})
+}
+
+// slogLevels are the slog levels of the logrus levels, by logrus level.
+// Trace, fatal and panic are 4 below debug and 4 and 8 above error.
+var slogLevels = [...]slog.Level{
+	logrus.PanicLevel: slog.LevelError + 8,
+	logrus.FatalLevel: slog.LevelError + 4,
+	logrus.ErrorLevel: slog.LevelError,
+	logrus.WarnLevel:  slog.LevelWarn,
+	logrus.InfoLevel:  slog.LevelInfo,
+	logrus.DebugLevel: slog.LevelDebug,
+	logrus.TraceLevel: slog.LevelDebug - 4,
+}
+
+// slogFormatter serializes the entries with the slog JSON or text handler,
+// with the level names and time layout of the other encoders. The handler
+// writes into the buffer of the entry, so the entries go to the output of
+// the logger like with the other encoders.
+type slogFormatter struct {
+	text            bool
+	timestampFormat string
+	stackTraces     bool
+	nestFields      bool
+}
+
+// Format serializes the entry into the buffer of the entry
+func (f *slogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	addStructuredFields(entry, f.stackTraces, f.nestFields)
+	name := encoderLevel(entry)
+
+	var pc uintptr
+	if entry.Caller != nil {
+		pc = entry.Caller.PC
+	}
+	record := slog.NewRecord(entry.Time, slogLevels[entry.Level], entry.Message, pc)
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		record.AddAttrs(slog.Any(key, entry.Data[key]))
+	}
+
+	buf := entry.Buffer
+	if buf == nil {
+		buf = &bytes.Buffer{}
+	}
+	options := &slog.HandlerOptions{Level: slogLevels[logrus.TraceLevel], ReplaceAttr: f.replaceAttr(name)}
+	var handler slog.Handler = slog.NewJSONHandler(buf, options)
+	if f.text {
+		handler = slog.NewTextHandler(buf, options)
+	}
+	if err := handler.Handle(ctx, record); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// replaceAttr returns the function writing the level of the entry as level
+// and its time with the timestamp format
+func (f *slogFormatter) replaceAttr(level string) func(groups []string, attr slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return attr
+		}
+		switch attr.Key {
+		case slog.LevelKey:
+			return slog.String(slog.LevelKey, level)
+		case slog.TimeKey:
+			t := attr.Value.Time()
+			switch f.timestampFormat {
+			case TimestampEpochMillis:
+				return slog.Int64(slog.TimeKey, t.UnixMilli())
+			case "":
+				return slog.String(slog.TimeKey, t.Format(time.RFC3339))
+			default:
+				return slog.String(slog.TimeKey, t.Format(f.timestampFormat))
+			}
+		}
+		return attr
+	}
+}
@@ -0,0 +1,71 @@
+//go:build go1.21
+
+package aloig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newSlogTestLogger returns a logrus logger writing to the returned buffer
+// with the slog encoder
+func newSlogTestLogger(t *testing.T, config Config) (*logrus.Logger, *bytes.Buffer) {
+	config.Encoder = EncoderSlog
+	formatter, err := encoderFormatter(config)
+	if err != nil {
+		t.Fatalf("Expected the slog encoder to be available, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetLevel(logrus.TraceLevel)
+	logger.SetOutput(&buf)
+	logger.SetFormatter(formatter)
+	return logger, &buf
+}
+
+// TestSlogEncoder tests that the slog encoder writes the keys and level
+// names of the logrus JSON formatter
+func TestSlogEncoder(t *testing.T) {
+	logger, buf := newSlogTestLogger(t, Config{Environment: "prod", TimestampFormat: TimestampEpochMillis})
+	logger.WithFields(logrus.Fields{"order_id": "o-1", "level": "clash", logrus.ErrorKey: errors.New("declined")}).Warn("payment failed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", buf.String(), err)
+	}
+	expected := map[string]interface{}{
+		"level":        "warning",
+		"msg":          "payment failed",
+		"order_id":     "o-1",
+		"fields.level": "clash",
+		"error":        "declined",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["time"].(float64); !ok {
+		t.Errorf("Expected the time in epoch milliseconds, got %v", entry["time"])
+	}
+}
+
+// TestSlogEncoderText tests that the slog encoder writes the text entries
+// to the output of the logger
+func TestSlogEncoderText(t *testing.T) {
+	logger, buf := newSlogTestLogger(t, Config{Environment: "dev", ForceText: true})
+	logger.WithField("order_id", "o-1").Log(logrus.FatalLevel, "fatal")
+
+	output := buf.String()
+	for _, expected := range []string{"level=fatal", "msg=fatal", "order_id=o-1"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package aloig
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestParseEncoder tests parsing the encoder names
+func TestParseEncoder(t *testing.T) {
+	for _, encoder := range []Encoder{EncoderLogrus, EncoderSlog, EncoderZerolog} {
+		parsed, err := ParseEncoder(strings.ToUpper(encoder.String()))
+		if err != nil || parsed != encoder {
+			t.Errorf("Expected %s, got %v (%v)", encoder, parsed, err)
+		}
+	}
+	if _, err := ParseEncoder("glog"); err == nil {
+		t.Error("Expected an error for an unknown encoder")
+	}
+}
+
+// baseFormatter returns the formatter of the encoder of logger, under the
+// formatters added by NewLogger
+func baseFormatter(logger Logger) logrus.Formatter {
+	formatter := logger.(*logrusLogger).logger.Formatter
+	if stats, ok := formatter.(*statsFormatter); ok {
+		formatter = stats.Formatter
+	}
+	if sampling, ok := formatter.(*samplingFormatter); ok {
+		formatter = sampling.Formatter
+	}
+	return formatter
+}
+
+// TestNewLoggerEncoder tests that NewLogger uses the formatter of the
+// configured encoder
+func TestNewLoggerEncoder(t *testing.T) {
+	encodersMu.Lock()
+	previous, registered := encoderFormatters[EncoderZerolog]
+	encodersMu.Unlock()
+	defer func() {
+		encodersMu.Lock()
+		delete(encoderFormatters, EncoderZerolog)
+		if registered {
+			encoderFormatters[EncoderZerolog] = previous
+		}
+		encodersMu.Unlock()
+	}()
+
+	registerEncoder(EncoderZerolog, func(config Config) logrus.Formatter {
+		return &logrus.TextFormatter{DisableColors: true}
+	})
+	logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel, Encoder: EncoderZerolog})
+	if _, ok := baseFormatter(logger).(*logrus.TextFormatter); !ok {
+		t.Errorf("Expected the formatter of the encoder, got %T", baseFormatter(logger))
+	}
+}
+
+// TestNewLoggerUnavailableEncoder tests that NewLogger falls back to logrus
+// when the encoder isn't available
+func TestNewLoggerUnavailableEncoder(t *testing.T) {
+	encodersMu.Lock()
+	previous, registered := encoderFormatters[EncoderZerolog]
+	delete(encoderFormatters, EncoderZerolog)
+	encodersMu.Unlock()
+	defer func() {
+		if registered {
+			registerEncoder(EncoderZerolog, previous)
+		}
+	}()
+
+	_, err := encoderFormatter(Config{Environment: "prod", Encoder: EncoderZerolog})
+	if err == nil || !strings.Contains(err.Error(), "zerolog") {
+		t.Errorf("Expected an unavailable encoder error, got %v", err)
+	}
+	logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel, Encoder: EncoderZerolog})
+	if _, ok := baseFormatter(logger).(*CallerJSONFormatter); !ok {
+		t.Errorf("Expected the logrus JSON formatter, got %T", baseFormatter(logger))
+	}
+}
+
+// BenchmarkLoggerInfo measures the cost of a log call through the whole
+// pipeline of NewLogger with each encoder available in the build
+func BenchmarkLoggerInfo(b *testing.B) {
+	originalLog := log
+	defer func() { log = originalLog }()
+
+	for _, encoder := range []Encoder{EncoderLogrus, EncoderSlog, EncoderZerolog} {
+		config := Config{Environment: "prod", Level: logrus.InfoLevel, Encoder: encoder}
+		if _, err := encoderFormatter(config); err != nil {
+			continue
+		}
+		b.Run(encoder.String(), func(b *testing.B) {
+			logger := NewLogger(config)
+			logger.(*logrusLogger).logger.SetOutput(io.Discard)
+			logger = logger.WithField("order_id", "o-1")
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.WithField("attempt", i).Info("payment authorized")
+			}
+		})
+	}
+}
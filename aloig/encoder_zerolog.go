@@ -0,0 +1,59 @@
+//go:build aloig_zerolog
+
+package aloig
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// Build with the aloig_zerolog tag to make EncoderZerolog available
+func init() {
+	registerEncoder(EncoderZerolog, func(config Config) logrus.Formatter {
+		return &zerologFormatter{
+			timestampFormat: config.TimestampFormat,
+			stackTraces:     config.StackTraces,
+			nestFields:      config.NestedFields,
+		}
+	})
+}
+
+// zerologFormatter serializes the entries in JSON with zerolog, with the
+// same keys as CallerJSONFormatter. A log call allocates about half as much
+// as with CallerJSONFormatter, the entries still going through the logrus
+// levels and hooks before (see BenchmarkLoggerInfo).
+type zerologFormatter struct {
+	timestampFormat string
+	stackTraces     bool
+	nestFields      bool
+}
+
+// Format serializes the entry into the buffer of the entry
+func (f *zerologFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	addStructuredFields(entry, f.stackTraces, f.nestFields)
+	level := encoderLevel(entry)
+
+	buf := entry.Buffer
+	if buf == nil {
+		buf = &bytes.Buffer{}
+	}
+	// The logger only wraps the buffer; the events are pooled by zerolog
+	logger := zerolog.New(buf)
+	event := logger.Log()
+	switch f.timestampFormat {
+	case TimestampEpochMillis:
+		event.Int64(logrus.FieldKeyTime, entry.Time.UnixMilli())
+	case "":
+		event.Str(logrus.FieldKeyTime, entry.Time.Format(time.RFC3339))
+	default:
+		event.Str(logrus.FieldKeyTime, entry.Time.Format(f.timestampFormat))
+	}
+	event.Str(logrus.FieldKeyLevel, level).
+		Str(logrus.FieldKeyMsg, entry.Message).
+		Fields(map[string]interface{}(entry.Data)).
+		Send()
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,57 @@
+//go:build aloig_zerolog
+
+package aloig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestZerologEncoder tests that the zerolog encoder writes the keys of the
+// logrus JSON formatter
+func TestZerologEncoder(t *testing.T) {
+	formatter, err := encoderFormatter(Config{Encoder: EncoderZerolog})
+	if err != nil {
+		t.Fatalf("Expected the zerolog encoder to be available, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(formatter)
+	logger.WithFields(logrus.Fields{"order_id": "o-1", "msg": "clash", logrus.ErrorKey: errors.New("declined")}).Warn("payment failed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", buf.String(), err)
+	}
+	expected := map[string]interface{}{
+		"level":      "warning",
+		"msg":        "payment failed",
+		"order_id":   "o-1",
+		"fields.msg": "clash",
+		"error":      "declined",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["time"].(string); !ok {
+		t.Errorf("Expected the time, got %v", entry["time"])
+	}
+}
+
+// BenchmarkZerologFormatter measures the cost of formatting an entry with
+// the zerolog encoder, to compare with BenchmarkCallerJSONFormatter
+func BenchmarkZerologFormatter(b *testing.B) {
+	formatter, err := encoderFormatter(Config{Encoder: EncoderZerolog})
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkFormatter(b, formatter, nil)
+}
@@ -0,0 +1,78 @@
+package aloig
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MetadataTimeout bounds each Enricher, e.g. the queries to the metadata
+// endpoints
+const MetadataTimeout = time.Second
+
+// Enricher contributes static fields added to every entry, typically the
+// metadata of the platform running the process. NewLogger calls it once,
+// with a context canceled after MetadataTimeout.
+type Enricher interface {
+	Fields(ctx context.Context) (map[string]interface{}, error)
+}
+
+// EnricherFunc adapts a function to the Enricher interface
+type EnricherFunc func(ctx context.Context) (map[string]interface{}, error)
+
+// Fields calls f
+func (f EnricherFunc) Fields(ctx context.Context) (map[string]interface{}, error) {
+	return f(ctx)
+}
+
+// NopEnricher returns an enricher contributing no fields, e.g. for the
+// platforms without metadata
+func NopEnricher() Enricher {
+	return EnricherFunc(func(context.Context) (map[string]interface{}, error) {
+		return nil, nil
+	})
+}
+
+// onceEnricher calls an enricher the first time only, so the loggers created
+// afterwards reuse its fields or error
+type onceEnricher struct {
+	enricher Enricher
+
+	once   sync.Once
+	fields map[string]interface{}
+	err    error
+}
+
+// newOnceEnricher returns an enricher calling enricher once
+func newOnceEnricher(enricher Enricher) *onceEnricher {
+	return &onceEnricher{enricher: enricher}
+}
+
+// Fields returns the fields of the first call
+func (e *onceEnricher) Fields(ctx context.Context) (map[string]interface{}, error) {
+	e.once.Do(func() {
+		e.fields, e.err = e.enricher.Fields(ctx)
+	})
+	return e.fields, e.err
+}
+
+// enrichFields runs the enrichers in order, the fields of the later ones
+// taking precedence. Failures are logged with logger as warnings.
+func enrichFields(logger Logger, enrichers []Enricher) logrus.Fields {
+	fields := make(logrus.Fields)
+	for _, enricher := range enrichers {
+		ctx, cancel := context.WithTimeout(context.Background(), MetadataTimeout)
+		enriched, err := enricher.Fields(ctx)
+		cancel()
+		if err != nil {
+			logger.WithError(err).Warn("Error running an enricher")
+			continue
+		}
+		for k, v := range enriched {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,58 @@
+package aloig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestNewLoggerEnrichers tests that the fields of the enrichers are added to
+// every entry, a failing enricher contributing nothing
+func TestNewLoggerEnrichers(t *testing.T) {
+	logger := NewLogger(Config{Environment: "dev", Level: logrus.InfoLevel, Enrichers: []Enricher{
+		NopEnricher(),
+		EnricherFunc(func(ctx context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"rack": "r12", "dc": "par1"}, nil
+		}),
+		EnricherFunc(func(ctx context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"dc": "ams2"}, errors.New("metadata unavailable")
+		}),
+		EnricherFunc(func(ctx context.Context) (map[string]interface{}, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("Expected the enricher to be bounded")
+			}
+			return map[string]interface{}{"rack": "r13"}, nil
+		}),
+	}}).(*logrusLogger)
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+	logger.logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+	logger.Info("started")
+
+	output := buf.String()
+	if !strings.Contains(output, "rack=r13") || !strings.Contains(output, "dc=par1") {
+		t.Errorf("Expected the fields of the later enrichers to win and the failed one to be skipped, got: %s", output)
+	}
+}
+
+// TestOnceEnricher tests that the wrapped enricher is only called once
+func TestOnceEnricher(t *testing.T) {
+	calls := 0
+	enricher := newOnceEnricher(EnricherFunc(func(ctx context.Context) (map[string]interface{}, error) {
+		calls++
+		return nil, errors.New("unreachable")
+	}))
+	for i := 0; i < 3; i++ {
+		if _, err := enricher.Fields(context.Background()); err == nil {
+			t.Error("Expected the error of the first call")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+}
@@ -0,0 +1,99 @@
+package aloig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorChainField is the field listing the errors wrapped by the entry error
+const ErrorChainField = "error_chain"
+
+// ErrorsField is the field listing the messages of the errors joined by the
+// entry error, e.g. with errors.Join, written instead of the error field by
+// the JSON formatters
+const ErrorsField = "errors"
+
+// maxErrorChain bounds the number of errors listed in the chain
+const maxErrorChain = 32
+
+// ErrorChainLink is an error of a chain, as rendered in the error_chain field
+type ErrorChainLink struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// ErrorChainHook is a hook that adds the error_chain field to entries whose
+// error wraps other errors, listing the type and message of each error from
+// the outermost to the root causes. Errors wrapping several errors, like the
+// ones returned by errors.Join, are walked depth first.
+type ErrorChainHook struct{}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *ErrorChainHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the error chain of the entry error
+func (hook *ErrorChainHook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok || err == nil {
+		return nil
+	}
+
+	if chain := ErrorChain(err); len(chain) > 1 {
+		entry.Data[ErrorChainField] = chain
+	}
+	return nil
+}
+
+// ErrorChain returns the type and message of err and of every error it
+// wraps, through Unwrap() error and Unwrap() []error
+func ErrorChain(err error) []ErrorChainLink {
+	var chain []ErrorChainLink
+
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil || len(chain) >= maxErrorChain {
+			return
+		}
+		chain = append(chain, ErrorChainLink{Type: fmt.Sprintf("%T", err), Message: err.Error()})
+
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range multi.Unwrap() {
+				walk(e)
+			}
+			return
+		}
+		walk(errors.Unwrap(err))
+	}
+
+	walk(err)
+	return chain
+}
+
+// splitJoinedError replaces the error field of data with the messages of the
+// errors it joins, through Unwrap() []error, in the errors field, so each of
+// them is an element of an array rather than a line of a single string
+func splitJoinedError(data logrus.Fields) {
+	multi, ok := data[logrus.ErrorKey].(interface{ Unwrap() []error })
+	if !ok {
+		return
+	}
+	if _, exists := data[ErrorsField]; exists {
+		return
+	}
+
+	var messages []string
+	for _, err := range multi.Unwrap() {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) == 0 {
+		return
+	}
+	data[ErrorsField] = messages
+	delete(data, logrus.ErrorKey)
+}
@@ -0,0 +1,89 @@
+package aloig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// joinedError wraps several errors like errors.Join
+type joinedError struct {
+	errs []error
+}
+
+func (e *joinedError) Error() string   { return "multiple errors" }
+func (e *joinedError) Unwrap() []error { return e.errs }
+
+// TestErrorChain tests walking wrapped and joined errors
+func TestErrorChain(t *testing.T) {
+	root := io.ErrUnexpectedEOF
+	err := fmt.Errorf("load config: %w", &joinedError{errs: []error{fmt.Errorf("read: %w", root), errors.New("parse failed")}})
+
+	chain := ErrorChain(err)
+	expected := []ErrorChainLink{
+		{Type: "*fmt.wrapError", Message: "load config: multiple errors"},
+		{Type: "*aloig.joinedError", Message: "multiple errors"},
+		{Type: "*fmt.wrapError", Message: "read: unexpected EOF"},
+		{Type: "*errors.errorString", Message: "unexpected EOF"},
+		{Type: "*errors.errorString", Message: "parse failed"},
+	}
+	if len(chain) != len(expected) {
+		t.Fatalf("Expected %d links, got %+v", len(expected), chain)
+	}
+	for i := range expected {
+		if chain[i] != expected[i] {
+			t.Errorf("Link %d: expected %+v, got %+v", i, expected[i], chain[i])
+		}
+	}
+}
+
+// TestErrorChainHook tests that only wrapping errors get an error_chain field
+func TestErrorChainHook(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(&ErrorChainHook{})
+
+	logger.WithError(errors.New("plain")).Error("failed")
+	if strings.Contains(buf.String(), ErrorChainField) {
+		t.Errorf("Expected no chain for a plain error, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.WithError(fmt.Errorf("save user: %w", io.ErrShortWrite)).Error("failed")
+	if output := buf.String(); !strings.Contains(output, "error_chain=") || !strings.Contains(output, "short write") {
+		t.Errorf("Expected the error chain, got: %s", output)
+	}
+}
+
+// TestJoinedErrorArray tests that the errors joined by the entry error are
+// written as the elements of the errors array in JSON
+func TestJoinedErrorArray(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetFormatter(&CallerJSONFormatter{JSONFormatter: &logrus.JSONFormatter{}})
+
+	joined := &joinedError{errs: []error{errors.New("email invalid"), nil, fmt.Errorf("age: %w", io.ErrUnexpectedEOF)}}
+	logger.WithError(joined).Error("validation failed")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON line, got %s", buf.String())
+	}
+	expected := []interface{}{"email invalid", "age: unexpected EOF"}
+	if !reflect.DeepEqual(line[ErrorsField], expected) {
+		t.Errorf("Expected errors=%v, got %v", expected, line[ErrorsField])
+	}
+	if _, ok := line[logrus.ErrorKey]; ok {
+		t.Errorf("Expected no concatenated error field, got %v", line[logrus.ErrorKey])
+	}
+
+	buf.Reset()
+	logger.WithError(errors.New("plain")).Error("failed")
+	if output := buf.String(); !strings.Contains(output, `"error":"plain"`) || strings.Contains(output, `"errors"`) {
+		t.Errorf("Expected a plain error field, got: %s", output)
+	}
+}
@@ -0,0 +1,95 @@
+package aloig
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FieldsError is implemented by errors carrying log fields, such as the ones
+// built with the aloigerr package. Their fields are merged into the entries
+// they are logged with.
+type FieldsError interface {
+	error
+	LogFields() map[string]interface{}
+}
+
+// SeverityError is implemented by errors carrying the level they should be
+// logged at
+type SeverityError interface {
+	error
+	Severity() logrus.Level
+}
+
+// ErrorFieldsHook is a hook that merges the fields carried by the entry error
+// and the errors it wraps into the entry. Fields already set on the entry and
+// fields of outer errors take precedence.
+type ErrorFieldsHook struct{}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *ErrorFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire merges the error fields into the entry
+func (hook *ErrorFieldsHook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+	for key, value := range ErrorFields(err) {
+		if _, exists := entry.Data[key]; !exists {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
+
+// ErrorFields returns the fields carried by err and the errors it wraps,
+// outer errors taking precedence
+func ErrorFields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	for ; err != nil; err = errors.Unwrap(err) {
+		carrier, ok := err.(FieldsError)
+		if !ok {
+			continue
+		}
+		for key, value := range carrier.LogFields() {
+			if fields == nil {
+				fields = make(map[string]interface{})
+			}
+			if _, exists := fields[key]; !exists {
+				fields[key] = value
+			}
+		}
+	}
+	return fields
+}
+
+// ErrorSeverity returns the level carried by err or the errors it wraps,
+// ErrorLevel if none carries one
+func ErrorSeverity(err error) logrus.Level {
+	var carrier SeverityError
+	if errors.As(err, &carrier) {
+		return carrier.Severity()
+	}
+	return logrus.ErrorLevel
+}
+
+// withErrorArg returns the entry of the logger with the error field set when
+// the only argument is an error carrying fields, so Error(err) and
+// ErrorContext(ctx, err) merge them like WithError(err)
+func (l *logrusLogger) withErrorArg(args []interface{}) *logrus.Entry {
+	entry := l.newEntry()
+	if len(args) != 1 {
+		return entry
+	}
+	err, ok := args[0].(error)
+	if !ok || len(ErrorFields(err)) == 0 {
+		return entry
+	}
+	if _, exists := entry.Data[logrus.ErrorKey]; exists {
+		return entry
+	}
+	return entry.WithError(err)
+}
@@ -0,0 +1,78 @@
+package aloig
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertField holds the kind of the alerts logged by aloig, e.g.
+// ErrorRateAlert
+const AlertField = "alert"
+
+// ErrorRateAlert is the AlertField of the error rate alerts
+const ErrorRateAlert = "error_rate"
+
+// DefaultErrorRateWindow is the window of the ErrorRateHook when none is
+// configured
+const DefaultErrorRateWindow = time.Minute
+
+// ErrorRateHook counts the error, fatal and panic entries per window and
+// logs a single critical alert when their number reaches the threshold
+// within a window. The next alert is only logged after a window below the
+// threshold, so a lasting incident doesn't flood the alerts.
+type ErrorRateHook struct {
+	logger    Logger
+	threshold int
+	window    time.Duration
+
+	mu       sync.Mutex
+	start    time.Time
+	count    int
+	alerting bool
+}
+
+// NewErrorRateHook creates a hook logging the alerts with logger (nil uses
+// the singleton logger) when threshold errors are logged within window (0
+// uses DefaultErrorRateWindow)
+func NewErrorRateHook(logger Logger, threshold int, window time.Duration) *ErrorRateHook {
+	if window <= 0 {
+		window = DefaultErrorRateWindow
+	}
+	return &ErrorRateHook{logger: logger, threshold: threshold, window: window}
+}
+
+// Levels returns the levels counted by the hook
+func (h *ErrorRateHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+// Fire counts the entry and logs the alert when it reaches the threshold
+func (h *ErrorRateHook) Fire(entry *logrus.Entry) error {
+	if entry.Data[AlertField] == ErrorRateAlert {
+		return nil
+	}
+
+	h.mu.Lock()
+	if elapsed := entry.Time.Sub(h.start); elapsed >= h.window {
+		// Rearm after a window below the threshold, or with no errors at all
+		if h.count < h.threshold || elapsed >= 2*h.window {
+			h.alerting = false
+		}
+		h.start, h.count = entry.Time, 0
+	}
+	h.count++
+	alert := h.count >= h.threshold && !h.alerting
+	if alert {
+		h.alerting = true
+	}
+	count := h.count
+	h.mu.Unlock()
+
+	if alert {
+		LogCustom(entry.Context, h.logger, CriticalLevel, "error rate threshold exceeded",
+			String(AlertField, ErrorRateAlert), Int("errors", count), Duration("window", h.window))
+	}
+	return nil
+}
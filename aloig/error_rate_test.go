@@ -0,0 +1,71 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestErrorRateHook tests that a single alert is logged when the errors of a
+// window reach the threshold, and again after a quiet window
+func TestErrorRateHook(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	hook := NewErrorRateHook(logger, 3, time.Minute)
+	logger.logger.AddHook(hook)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logAt := func(level logrus.Level, offset time.Duration, count int) {
+		for i := 0; i < count; i++ {
+			logger.logger.WithTime(start.Add(offset)).Log(level, "request failed")
+		}
+	}
+	alerts := func() int {
+		return strings.Count(buf.String(), `msg="error rate threshold exceeded"`)
+	}
+
+	logAt(logrus.ErrorLevel, 0, 2)
+	logAt(logrus.WarnLevel, 0, 5)
+	if alerts() != 0 {
+		t.Fatalf("Expected no alert below the threshold, got: %s", buf.String())
+	}
+	logAt(logrus.ErrorLevel, time.Second, 10)
+	if alerts() != 1 {
+		t.Fatalf("Expected a single alert, got: %s", buf.String())
+	}
+	output := buf.String()
+	if !strings.Contains(output, AlertField+"="+ErrorRateAlert) || !strings.Contains(output, "errors=3") ||
+		!strings.Contains(output, LevelNameField+"=critical") {
+		t.Errorf("Expected a critical error rate alert, got: %s", output)
+	}
+
+	// The incident lasts in the next window: no new alert
+	logAt(logrus.ErrorLevel, time.Minute+time.Second, 5)
+	if alerts() != 1 {
+		t.Errorf("Expected no new alert while the rate stays high, got %d", alerts())
+	}
+
+	// A quiet window rearms the alert
+	logAt(logrus.ErrorLevel, 2*time.Minute+2*time.Second, 1)
+	logAt(logrus.ErrorLevel, 3*time.Minute+3*time.Second, 3)
+	if alerts() != 2 {
+		t.Errorf("Expected a new alert after a quiet window, got %d", alerts())
+	}
+}
+
+// TestNewLoggerErrorRate tests that NewLogger installs the watchdog when
+// ErrorRateThreshold is set
+func TestNewLoggerErrorRate(t *testing.T) {
+	config := DefaultConfig()
+	config.ErrorRateThreshold = 2
+	logger := NewLogger(config).(*logrusLogger)
+	buf := &lockedBuffer{}
+	logger.logger.SetOutput(buf)
+
+	logger.Error("first")
+	logger.Error("second")
+	if !strings.Contains(buf.String(), "error rate threshold exceeded") {
+		t.Errorf("Expected an alert, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,221 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventNameField is the field holding the name of an emitted event
+const EventNameField = "event"
+
+// EventFieldType is the expected type of an event field
+type EventFieldType int
+
+// Event field types
+const (
+	// EventAny accepts any value
+	EventAny EventFieldType = iota
+	// EventString accepts strings
+	EventString
+	// EventInt accepts signed and unsigned integers
+	EventInt
+	// EventFloat accepts floats and integers
+	EventFloat
+	// EventBool accepts booleans
+	EventBool
+	// EventTime accepts time.Time values
+	EventTime
+	// EventDuration accepts time.Duration values
+	EventDuration
+)
+
+// String returns the name of the type
+func (t EventFieldType) String() string {
+	switch t {
+	case EventString:
+		return "string"
+	case EventInt:
+		return "int"
+	case EventFloat:
+		return "float"
+	case EventBool:
+		return "bool"
+	case EventTime:
+		return "time"
+	case EventDuration:
+		return "duration"
+	default:
+		return "any"
+	}
+}
+
+// EventSchema describes a registered event: its name, the level it is
+// logged at and the fields it must or may carry
+type EventSchema struct {
+	Name string
+
+	// Level is the level of the event entries (InfoLevel when zero,
+	// since PanicLevel is never a valid event level)
+	Level logrus.Level
+
+	// Required are the fields every event must carry with their type
+	Required map[string]EventFieldType
+
+	// Optional are the fields an event may carry with their type
+	Optional map[string]EventFieldType
+}
+
+// Event is an occurrence of a registered event
+type Event struct {
+	Name   string
+	Fields map[string]interface{}
+}
+
+var (
+	eventMu      sync.RWMutex
+	eventSchemas = make(map[string]EventSchema)
+)
+
+// RegisterEvent registers the schema of an event. Registering the same name
+// twice is an error.
+func RegisterEvent(schema EventSchema) error {
+	if schema.Name == "" {
+		return fmt.Errorf("event schema without name")
+	}
+	if schema.Level == logrus.PanicLevel {
+		schema.Level = logrus.InfoLevel
+	}
+
+	eventMu.Lock()
+	defer eventMu.Unlock()
+
+	if _, ok := eventSchemas[schema.Name]; ok {
+		return fmt.Errorf("event %q already registered", schema.Name)
+	}
+	eventSchemas[schema.Name] = schema
+	return nil
+}
+
+// SetEventValidation enables checking the events emitted with the singleton
+// logger against their schema. NewLogger enables it for the loggers of the
+// dev environment only, so production doesn't pay for it.
+func SetEventValidation(enabled bool) {
+	if l, ok := GetLogger().(*logrusLogger); ok {
+		l.setEventValidation(enabled)
+	}
+}
+
+// setEventValidation enables checking the events emitted with the logger and
+// its children against their schema
+func (l *logrusLogger) setEventValidation(enabled bool) {
+	if l.settings == nil {
+		return
+	}
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&l.settings.eventValidation, value)
+}
+
+// validatesEvents checks if the events emitted with logger are checked
+// against their schema
+func validatesEvents(logger Logger) bool {
+	l, ok := logger.(*logrusLogger)
+	return ok && l.settings != nil && atomic.LoadInt32(&l.settings.eventValidation) == 1
+}
+
+// Emit logs the event with the singleton logger at the level of its schema,
+// with the event field and the context fields. When validation is enabled,
+// an unregistered event or a missing, unexpected or mistyped field is
+// reported as a warning and returned as an error; the event is logged anyway.
+func Emit(ctx context.Context, ev Event) error {
+	eventMu.RLock()
+	schema, registered := eventSchemas[ev.Name]
+	eventMu.RUnlock()
+
+	logger := GetLogger()
+	var err error
+	if validatesEvents(logger) {
+		if !registered {
+			err = fmt.Errorf("event %q is not registered", ev.Name)
+		} else {
+			err = schema.validate(ev)
+		}
+	}
+
+	level := logrus.InfoLevel
+	if registered {
+		level = schema.Level
+	}
+
+	fields := make(map[string]interface{}, len(ev.Fields)+1)
+	for k, v := range ev.Fields {
+		fields[k] = v
+	}
+	fields[EventNameField] = ev.Name
+
+	if err != nil {
+		logger.WithField(EventNameField, ev.Name).WithError(err).WarnContext(ctx, "invalid event")
+	}
+	logContextAtLevel(ctx, logger.WithFields(fields), level, ev.Name)
+	return err
+}
+
+// validate checks the fields of an event against the schema
+func (schema EventSchema) validate(ev Event) error {
+	var problems []string
+
+	for name, fieldType := range schema.Required {
+		value, ok := ev.Fields[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("missing field %q", name))
+		} else if !fieldType.accepts(value) {
+			problems = append(problems, fmt.Sprintf("field %q is %T, expected %s", name, value, fieldType))
+		}
+	}
+	for name, value := range ev.Fields {
+		if _, ok := schema.Required[name]; ok {
+			continue
+		}
+		fieldType, ok := schema.Optional[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unexpected field %q", name))
+		} else if !fieldType.accepts(value) {
+			problems = append(problems, fmt.Sprintf("field %q is %T, expected %s", name, value, fieldType))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("event %q: %s", ev.Name, strings.Join(problems, ", "))
+}
+
+// accepts checks if value has the expected type
+func (t EventFieldType) accepts(value interface{}) bool {
+	switch value.(type) {
+	case string:
+		return t == EventAny || t == EventString
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return t == EventAny || t == EventInt || t == EventFloat
+	case float32, float64:
+		return t == EventAny || t == EventFloat
+	case bool:
+		return t == EventAny || t == EventBool
+	case time.Time:
+		return t == EventAny || t == EventTime
+	case time.Duration:
+		return t == EventAny || t == EventDuration
+	default:
+		return t == EventAny
+	}
+}
@@ -0,0 +1,82 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestEmit tests that events are logged consistently and validated when enabled
+func TestEmit(t *testing.T) {
+	originalLog := log
+	defer func() { log = originalLog }()
+	defer SetEventValidation(false)
+
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	log = logger
+
+	err := RegisterEvent(EventSchema{
+		Name:     "test_order_placed",
+		Required: map[string]EventFieldType{"order_id": EventString, "amount": EventFloat},
+		Optional: map[string]EventFieldType{"coupon": EventString, "elapsed": EventDuration},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register event: %v", err)
+	}
+	if err := RegisterEvent(EventSchema{Name: "test_order_placed"}); err == nil {
+		t.Error("Expected an error when registering an event twice")
+	}
+
+	ctx := WithTraceID(context.Background(), "trace-event")
+	SetEventValidation(true)
+
+	err = Emit(ctx, Event{Name: "test_order_placed", Fields: map[string]interface{}{"order_id": "o-1", "amount": 12, "elapsed": time.Second}})
+	if err != nil {
+		t.Errorf("Expected a valid event, got: %v", err)
+	}
+	output := buf.String()
+	for _, expected := range []string{"level=info", "msg=test_order_placed", "event=test_order_placed", "order_id=o-1", "amount=12", "trace_id=trace-event"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	err = Emit(ctx, Event{Name: "test_order_placed", Fields: map[string]interface{}{"amount": "12", "extra": true}})
+	if err == nil {
+		t.Fatal("Expected a validation error")
+	}
+	for _, expected := range []string{`missing field "order_id"`, `field "amount" is string, expected float`, `unexpected field "extra"`} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in error, got: %v", expected, err)
+		}
+	}
+	if output := buf.String(); !strings.Contains(output, `msg="invalid event"`) || !strings.Contains(output, "msg=test_order_placed") {
+		t.Errorf("Expected a warning and the event, got: %s", output)
+	}
+
+	if err := Emit(ctx, Event{Name: "test_unregistered"}); err == nil {
+		t.Error("Expected an error for an unregistered event")
+	}
+
+	// Without validation events are logged as they are
+	SetEventValidation(false)
+	buf.Reset()
+	if err := Emit(ctx, Event{Name: "test_unregistered"}); err != nil {
+		t.Errorf("Expected no validation, got: %v", err)
+	}
+	if output := buf.String(); strings.Contains(output, "invalid event") || !strings.Contains(output, "event=test_unregistered") {
+		t.Errorf("Expected only the event, got: %s", output)
+	}
+
+	// Validation is a setting of each logger
+	if validatesEvents(NewLogger(Config{Environment: "prod"})) || !validatesEvents(NewLogger(Config{Environment: "dev"})) {
+		t.Error("Expected validation for the dev loggers only")
+	}
+	if validatesEvents(logger) {
+		t.Error("Expected a new logger not to change the validation of the singleton")
+	}
+}
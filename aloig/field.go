@@ -0,0 +1,169 @@
+package aloig
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fieldKind is the type of the value held by a Field
+type fieldKind uint8
+
+const (
+	anyField fieldKind = iota
+	stringField
+	int64Field
+	uint64Field
+	float64Field
+	boolField
+	durationField
+	errorField
+)
+
+// Field is a typed key/value pair built with String, Int, Duration, Err,
+// Any, etc. Scalar values are stored without boxing them into an interface;
+// the fields passed to Log are only converted, and boxed into the entry,
+// when the level is enabled.
+type Field struct {
+	Key string
+
+	kind    fieldKind
+	integer int64
+	str     string
+	iface   interface{}
+}
+
+// String returns a field holding a string
+func String(key, value string) Field {
+	return Field{Key: key, kind: stringField, str: value}
+}
+
+// Int returns a field holding an int
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: int64Field, integer: int64(value)}
+}
+
+// Int64 returns a field holding an int64
+func Int64(key string, value int64) Field {
+	return Field{Key: key, kind: int64Field, integer: value}
+}
+
+// Uint64 returns a field holding a uint64
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: uint64Field, integer: int64(value)}
+}
+
+// Float64 returns a field holding a float64
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: float64Field, integer: int64(math.Float64bits(value))}
+}
+
+// Bool returns a field holding a bool
+func Bool(key string, value bool) Field {
+	var integer int64
+	if value {
+		integer = 1
+	}
+	return Field{Key: key, kind: boolField, integer: integer}
+}
+
+// Duration returns a field holding a time.Duration
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, kind: durationField, integer: int64(value)}
+}
+
+// Time returns a field holding a time.Time
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, iface: value}
+}
+
+// Err returns a field holding an error under the "error" key, like WithError
+func Err(err error) Field {
+	return Field{Key: logrus.ErrorKey, kind: errorField, iface: err}
+}
+
+// Any returns a field holding any value
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, iface: value}
+}
+
+// Group returns a field holding the given fields, written as a nested
+// object under key (e.g. {"db": {"query": ..., "rows": ...}})
+func Group(key string, fields ...Field) Field {
+	return Field{Key: key, iface: fieldsToMap(fields)}
+}
+
+// Value returns the value held by the field
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case stringField:
+		return f.str
+	case int64Field:
+		return f.integer
+	case uint64Field:
+		return uint64(f.integer)
+	case float64Field:
+		return math.Float64frombits(uint64(f.integer))
+	case boolField:
+		return f.integer == 1
+	case durationField:
+		return time.Duration(f.integer)
+	default:
+		return f.iface
+	}
+}
+
+// fieldsToMap converts typed fields into a map of fields
+func fieldsToMap(fields []Field) map[string]interface{} {
+	data := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		data[f.Key] = f.Value()
+	}
+	return data
+}
+
+// addFields adds typed fields to data, the fields of an entry of the
+// logger, with the prefix and the groups of the logger
+func (l *logrusLogger) addFields(data logrus.Fields, fields []Field) {
+	if len(l.groups) == 0 {
+		for _, f := range fields {
+			data[l.fieldKey(f.Key)] = f.Value()
+		}
+		return
+	}
+
+	values := make(logrus.Fields, len(fields))
+	for _, f := range fields {
+		values[l.fieldKey(f.Key)] = f.Value()
+	}
+	for k, v := range groupFields(data, l.groups, values) {
+		data[k] = v
+	}
+}
+
+// With returns a logger whose entries carry the given typed fields
+func (l *logrusLogger) With(fields ...Field) Logger {
+	entry := l.copyEntry(len(fields))
+	l.addFields(entry.Data, fields)
+	return l.child(entry)
+}
+
+// Log logs msg at level with the context fields and the given typed fields.
+// The fields are not converted when the level is disabled, and are added
+// with the context fields to a single copy of the fields of the logger.
+func (l *logrusLogger) Log(ctx context.Context, level logrus.Level, msg string, fields ...Field) {
+	if !l.IsLevelEnabled(level) {
+		return
+	}
+
+	var entry *logrus.Entry
+	if ctx == nil {
+		entry = l.copyEntry(len(fields))
+	} else {
+		entry = l.contextEntry(ctx, getContextFields(ctx), registeredContextFields(), len(fields))
+	}
+	l.addFields(entry.Data, fields)
+	logAtLevel(l.child(entry), level, msg)
+}
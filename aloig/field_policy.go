@@ -0,0 +1,109 @@
+package aloig
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FieldPolicy decides which fields the entries of an environment may carry
+type FieldPolicy struct {
+	// Allow, when not empty, drops every field not listed, except the
+	// fields aloig adds itself (error, trace and request IDs, logger name,
+	// ...) and the CustomFields
+	Allow []string
+
+	// Deny drops the listed fields
+	Deny []string
+}
+
+// DefaultFieldPolicies are the field policies of the environments when
+// Config.FieldPolicies is nil: production entries never carry the request
+// headers and bodies logged by the HTTP middleware for debugging.
+var DefaultFieldPolicies = map[string]FieldPolicy{
+	"prod": {Deny: []string{"request_body", "request_headers"}},
+}
+
+// policyExemptFields are the fields an allowlist always keeps
+var policyExemptFields = []string{
+	logrus.ErrorKey,
+	string(TraceIDKey),
+	string(RequestIDKey),
+	string(UserIDKey),
+	string(SessionIDKey),
+	LoggerNameField,
+	ErrorChainField,
+	StackTraceField,
+	LevelNameField,
+	BaseLevelField,
+	EventNameField,
+	DeadlineRemainingField,
+	PIDField,
+	GoroutineIDField,
+}
+
+// FieldPolicyHook enforces a FieldPolicy on the entries, so debug-only fields
+// can't reach the logs of an environment by accident. Field names are matched
+// case-insensitively.
+type FieldPolicyHook struct {
+	allow map[string]struct{}
+	deny  map[string]struct{}
+}
+
+// NewFieldPolicyHook creates a hook enforcing policy
+func NewFieldPolicyHook(policy FieldPolicy) *FieldPolicyHook {
+	hook := &FieldPolicyHook{deny: fieldSet(policy.Deny)}
+	if len(policy.Allow) > 0 {
+		hook.allow = fieldSet(policy.Allow)
+		for _, field := range policyExemptFields {
+			hook.allow[field] = struct{}{}
+		}
+	}
+	return hook
+}
+
+// fieldSet returns the lowercase names of fields
+func fieldSet(fields []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		set[strings.ToLower(field)] = struct{}{}
+	}
+	return set
+}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *FieldPolicyHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire drops the fields of the entry the policy doesn't allow
+func (hook *FieldPolicyHook) Fire(entry *logrus.Entry) error {
+	for key := range entry.Data {
+		if !hook.allowed(strings.ToLower(key)) {
+			delete(entry.Data, key)
+		}
+	}
+	return nil
+}
+
+// allowed reports whether the policy allows the lowercase field name
+func (hook *FieldPolicyHook) allowed(name string) bool {
+	if _, ok := hook.deny[name]; ok {
+		return false
+	}
+	if hook.allow == nil {
+		return true
+	}
+	_, ok := hook.allow[name]
+	return ok
+}
+
+// fieldPolicy returns the field policy of the configured environment
+func fieldPolicy(config Config) (FieldPolicy, bool) {
+	policies := config.FieldPolicies
+	if policies == nil {
+		policies = DefaultFieldPolicies
+	}
+	policy, ok := policies[config.Environment]
+	return policy, ok && (len(policy.Allow) > 0 || len(policy.Deny) > 0)
+}
@@ -0,0 +1,122 @@
+package aloig
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestFieldPolicyHookDeny tests that denied fields are dropped
+func TestFieldPolicyHookDeny(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(NewFieldPolicyHook(FieldPolicy{Deny: []string{"Request_Body"}}))
+
+	logger.WithFields(map[string]interface{}{"request_body": "{}", "status": 500}).Info("request")
+
+	output := buf.String()
+	if strings.Contains(output, "request_body") || !strings.Contains(output, "status=500") {
+		t.Errorf("Expected only the request body to be dropped, got: %s", output)
+	}
+}
+
+// TestFieldPolicyHookAllow tests that an allowlist keeps the listed fields
+// and the fields added by aloig
+func TestFieldPolicyHookAllow(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(NewFieldPolicyHook(FieldPolicy{Allow: []string{"order_id", "status"}, Deny: []string{"status"}}))
+
+	logger.WithFields(map[string]interface{}{"order_id": 42, "status": 500, "debug_dump": "..."}).WithField(string(TraceIDKey), "trace-123").Info("request")
+
+	output := buf.String()
+	if !strings.Contains(output, "order_id=42") || !strings.Contains(output, "trace_id=trace-123") {
+		t.Errorf("Expected the allowed fields to be kept, got: %s", output)
+	}
+	if strings.Contains(output, "status") || strings.Contains(output, "debug_dump") {
+		t.Errorf("Expected the other fields to be dropped, got: %s", output)
+	}
+}
+
+// TestNewLoggerFieldPolicies tests that the policy of the environment is
+// enforced, with request bodies dropped in prod by default
+func TestNewLoggerFieldPolicies(t *testing.T) {
+	policies := map[string]FieldPolicy{"staging": {Deny: []string{"headers"}}}
+	tests := []struct {
+		environment string
+		policies    map[string]FieldPolicy
+		dropped     string
+		kept        string
+	}{
+		{"prod", nil, "request_body", "headers"},
+		{"staging", policies, "headers", "request_body"},
+		{"prod", policies, "", "request_body"},
+	}
+
+	for _, tt := range tests {
+		logger := NewLogger(Config{Environment: tt.environment, Level: logrus.InfoLevel, FieldPolicies: tt.policies})
+		buf := &bytes.Buffer{}
+		logger.(*logrusLogger).logger.SetOutput(buf)
+
+		logger.WithFields(map[string]interface{}{"request_body": "{}", "headers": "{}"}).Info("request")
+
+		output := buf.String()
+		if tt.dropped != "" && strings.Contains(output, `"`+tt.dropped+`"`) {
+			t.Errorf("%s: expected %s to be dropped, got: %s", tt.environment, tt.dropped, output)
+		}
+		if !strings.Contains(output, `"`+tt.kept+`"`) {
+			t.Errorf("%s: expected %s to be kept, got: %s", tt.environment, tt.kept, output)
+		}
+	}
+}
+
+// TestNewLoggerFieldPolicyAllowsOwnFields tests that an allowlist keeps the
+// fields added by the hooks of NewLogger
+func TestNewLoggerFieldPolicyAllowsOwnFields(t *testing.T) {
+	logger := NewLogger(Config{
+		Environment:       "prod",
+		Level:             logrus.InfoLevel,
+		ProcessIDs:        true,
+		DeadlineRemaining: true,
+		FieldPolicies:     map[string]FieldPolicy{"prod": {Allow: []string{"order_id"}}},
+	})
+	buf := &bytes.Buffer{}
+	logger.(*logrusLogger).logger.SetOutput(buf)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	logger.WithFields(map[string]interface{}{"order_id": 42, "debug_dump": "..."}).InfoContext(ctx, "request")
+
+	output := buf.String()
+	for _, field := range []string{"order_id", PIDField, GoroutineIDField, DeadlineRemainingField} {
+		if !strings.Contains(output, `"`+field+`"`) {
+			t.Errorf("Expected %s to be kept, got: %s", field, output)
+		}
+	}
+	if strings.Contains(output, "debug_dump") {
+		t.Errorf("Expected the other fields to be dropped, got: %s", output)
+	}
+}
+
+// TestConfigFromEnvFieldPolicy tests that the field lists of the environment
+// variables apply to the configured environment
+func TestConfigFromEnvFieldPolicy(t *testing.T) {
+	t.Setenv("TEST_ENVIRONMENT", "staging")
+	t.Setenv("TEST_DENY_FIELDS", "headers, request_body")
+
+	config, err := ConfigFromEnv("TEST")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if deny := config.FieldPolicies["staging"].Deny; len(deny) != 2 || deny[0] != "headers" {
+		t.Errorf("Expected the staging deny list, got %v", config.FieldPolicies)
+	}
+	if len(config.FieldPolicies["prod"].Deny) == 0 {
+		t.Errorf("Expected the default prod policy to be kept, got %v", config.FieldPolicies)
+	}
+	if len(DefaultFieldPolicies) != 1 {
+		t.Errorf("Expected the default policies to be unchanged, got %v", DefaultFieldPolicies)
+	}
+}
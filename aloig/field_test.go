@@ -0,0 +1,110 @@
+package aloig
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestFieldValues tests that typed fields return their original values
+func TestFieldValues(t *testing.T) {
+	err := errors.New("boom")
+	now := time.Now()
+	tests := []struct {
+		field    Field
+		expected interface{}
+	}{
+		{String("s", "value"), "value"},
+		{Int("i", -3), int64(-3)},
+		{Int64("i64", 1<<40), int64(1 << 40)},
+		{Uint64("u64", 1<<63+1), uint64(1<<63 + 1)},
+		{Float64("f", 3.25), 3.25},
+		{Bool("b", true), true},
+		{Bool("b", false), false},
+		{Duration("d", 1500*time.Millisecond), 1500 * time.Millisecond},
+		{Time("t", now), now},
+		{Err(err), err},
+		{Any("a", []string{"x"}), []string{"x"}},
+	}
+	for _, test := range tests {
+		if value := test.field.Value(); !equalValues(value, test.expected) {
+			t.Errorf("Field %s: expected %v (%T), got %v (%T)", test.field.Key, test.expected, test.expected, value, value)
+		}
+	}
+	if Err(err).Key != logrus.ErrorKey {
+		t.Errorf("Expected Err to use the error key, got %s", Err(err).Key)
+	}
+}
+
+// equalValues compares field values, including slices
+func equalValues(a, b interface{}) bool {
+	if as, ok := a.([]string); ok {
+		bs, ok := b.([]string)
+		return ok && strings.Join(as, ",") == strings.Join(bs, ",")
+	}
+	return a == b
+}
+
+// TestLoggerWithTypedFields tests With and Log with typed fields
+func TestLoggerWithTypedFields(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	ctx := WithTraceID(context.Background(), "trace-fields")
+
+	logger.With(String("component", "billing")).Log(ctx, logrus.WarnLevel, "invoice retried",
+		Int("attempt", 2), Duration("backoff", time.Second), Err(errors.New("timeout")))
+
+	output := buf.String()
+	for _, expected := range []string{"level=warning", `msg="invoice retried"`, "component=billing", "attempt=2", "backoff=1s", "error=timeout", "trace_id=trace-fields"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	logger.Log(ctx, logrus.DebugLevel, "filtered", String("key", "value"))
+	if buf.Len() != 0 {
+		t.Errorf("Expected no entry below the logger level, got: %s", buf.String())
+	}
+}
+
+// TestLogDisabledLevelAllocations tests that disabled entries don't allocate
+func TestLogDisabledLevelAllocations(t *testing.T) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	ctx := context.Background()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Log(ctx, logrus.DebugLevel, "filtered", String("key", "value"), Int("count", 3), Duration("elapsed", time.Second))
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocation, got %.0f", allocs)
+	}
+}
+
+// BenchmarkLogFields measures the cost of a context log call with typed
+// fields, to compare with BenchmarkLogWithFields
+func BenchmarkLogFields(b *testing.B) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetOutput(io.Discard)
+	ctx := newBenchmarkContext()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Log(ctx, logrus.InfoLevel, "handled", String("method", "GET"), Int("status", 200), Duration("latency", time.Millisecond))
+	}
+}
+
+// BenchmarkLogWithFields measures the cost of the same log call as
+// BenchmarkLogFields with a map of fields
+func BenchmarkLogWithFields(b *testing.B) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetOutput(io.Discard)
+	ctx := newBenchmarkContext()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.WithFields(map[string]interface{}{"method": "GET", "status": 200, "latency": time.Millisecond}).InfoContext(ctx, "handled")
+	}
+}
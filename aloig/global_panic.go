@@ -0,0 +1,49 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// InstallGlobalPanicLogger returns a function that, deferred at the top of
+// main, logs and reports the panics unwinding main before the process dies:
+//
+//	func main() {
+//		defer aloig.InstallGlobalPanicLogger()()
+//		...
+//	}
+//
+// The panic is logged with the cleaned stack trace and the build info of the
+// binary, reported to Sentry, and the logs and Sentry events are flushed
+// before the panic is resumed, so the process still crashes with the usual
+// output and exit code. Go has no hook for the panics of other goroutines;
+// run them with RunJob or recover them with RecoveryMiddleware.
+func InstallGlobalPanicLogger() func() {
+	return func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+		logPanic(context.Background(), value, cleanStackTrace(debug.Stack(), "aloig.InstallGlobalPanicLogger", "runtime/panic.go", "panic("))
+		panic(value)
+	}
+}
+
+// logPanic logs and reports an unrecovered panic with its stack trace, then
+// flushes the logs and Sentry events
+func logPanic(ctx context.Context, value interface{}, stack string) {
+	fields := buildInfoFields()
+	fields["panic"] = fmt.Sprint(value)
+	fields[StackTraceField] = stack
+	if eventID := recoverToSentry(ctx, nil, value); eventID != "" {
+		fields[SentryEventIDField] = eventID
+	}
+	GetLogger().WithFields(fields).ErrorContext(ctx, "unrecovered panic")
+
+	sentryMu.Lock()
+	timeout := sentryFlushDefault
+	sentryMu.Unlock()
+	FlushSentryTimeout(timeout)
+	FlushLogs(timeout)
+}
@@ -0,0 +1,49 @@
+package aloig
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestInstallGlobalPanicLogger tests that panics are logged with the stack
+// and build info before being resumed
+func TestInstallGlobalPanicLogger(t *testing.T) {
+	buf, cleanup := setupTestLogger()
+	defer cleanup()
+
+	var resumed interface{}
+	func() {
+		defer func() { resumed = recover() }()
+		defer InstallGlobalPanicLogger()()
+		panic("main failed")
+	}()
+
+	if resumed != "main failed" {
+		t.Errorf("Expected the panic to be resumed, got %v", resumed)
+	}
+	output := buf.String()
+	for _, expected := range []string{`msg="unrecovered panic"`, `panic="main failed"`, "stack_trace=", "TestInstallGlobalPanicLogger", "go_version=" + runtime.Version()} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "runtime/panic.go") {
+		t.Errorf("Expected runtime panic frames to be removed, got: %s", output)
+	}
+}
+
+// TestInstallGlobalPanicLoggerNoPanic tests that nothing is logged when main
+// returns normally
+func TestInstallGlobalPanicLoggerNoPanic(t *testing.T) {
+	buf, cleanup := setupTestLogger()
+	defer cleanup()
+
+	func() {
+		defer InstallGlobalPanicLogger()()
+	}()
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,66 @@
+package aloig
+
+import "github.com/sirupsen/logrus"
+
+// WithGroup returns a child logger nesting the fields added to it, including
+// by its children, under the named object, like slog's Logger.WithGroup. The
+// groups of nested children are nested in turn; the fields added before keep
+// their place. The fields of the context, the error and the Sentry fields
+// stay at the top level. An empty name returns the logger itself.
+//
+//	logger.WithGroup("request").WithField("method", "GET").Info("handled")
+//	// {"msg":"handled","request":{"method":"GET"},...}
+func (l *logrusLogger) WithGroup(name string) Logger {
+	if name == "" {
+		return l
+	}
+	groups := append(l.groups[:len(l.groups):len(l.groups)], name)
+	child := *l
+	child.groups = groups
+	return &child
+}
+
+// groupFields returns the fields to add to an entry with data so the given
+// fields are nested under groups. The objects of the groups already in data
+// are copied before being extended, as they are shared with the parent
+// loggers.
+func groupFields(data logrus.Fields, groups []string, fields logrus.Fields) logrus.Fields {
+	grouped := make(logrus.Fields, 1)
+	var object map[string]interface{}
+	for k, v := range fields {
+		if _, ok := unprefixedFields[k]; ok {
+			grouped[k] = v
+			continue
+		}
+		if object == nil {
+			object = groupObject(data, groups, grouped)
+		}
+		object[k] = v
+	}
+	return grouped
+}
+
+// groupObject returns a copy of the object at the path of groups in data,
+// stored in grouped along with copies of its parents. A field holding
+// something else than an object along the path is replaced.
+func groupObject(data logrus.Fields, groups []string, grouped logrus.Fields) map[string]interface{} {
+	object := copyGroup(data[groups[0]])
+	grouped[groups[0]] = object
+	for _, name := range groups[1:] {
+		child := copyGroup(object[name])
+		object[name] = child
+		object = child
+	}
+	return object
+}
+
+// copyGroup returns a copy of value if it is an object, or a new object
+func copyGroup(value interface{}) map[string]interface{} {
+	switch object := value.(type) {
+	case map[string]interface{}:
+		return copyFields(object)
+	case logrus.Fields:
+		return copyFields(object)
+	}
+	return make(map[string]interface{})
+}
@@ -0,0 +1,58 @@
+package aloig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestWithGroup tests that the fields added after WithGroup are nested under
+// the group in JSON, except the error and the Sentry fields
+func TestWithGroup(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+
+	request := logger.WithField("service", "orders").WithGroup("request")
+	request.WithField("method", "GET").
+		WithFields(map[string]interface{}{"path": "/orders"}).
+		WithGroup("client").With(String("ip", "10.0.0.1")).
+		WithError(errors.New("timeout")).
+		Log(context.Background(), logrus.InfoLevel, "handled", Int("attempt", 2))
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON line, got %s", buf.String())
+	}
+	expected := map[string]interface{}{
+		"method": "GET",
+		"path":   "/orders",
+		"client": map[string]interface{}{"ip": "10.0.0.1", "attempt": float64(2)},
+	}
+	if !reflect.DeepEqual(line["request"], expected) {
+		t.Errorf("Expected request=%v, got %v", expected, line["request"])
+	}
+	if line["service"] != "orders" || line[logrus.ErrorKey] != "timeout" {
+		t.Errorf("Expected top-level service and error, got %v", line)
+	}
+
+	// The objects of the parent are not modified by its children
+	buf.Reset()
+	parent := request.WithField("method", "POST")
+	parent.WithField("path", "/carts")
+	parent.Info("parent")
+	line = nil
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON line, got %s", buf.String())
+	}
+	if !reflect.DeepEqual(line["request"], map[string]interface{}{"method": "POST"}) {
+		t.Errorf("Expected the parent group to be unchanged, got %v", line["request"])
+	}
+
+	if logger.WithGroup("") != Logger(logger) {
+		t.Error("Expected an empty group to return the logger")
+	}
+}
@@ -0,0 +1,140 @@
+package aloig
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SinkHealth is the state of a destination of the entries: the output of a
+// logger, a syslog server or Sentry
+type SinkHealth struct {
+	// Name is "output", "syslog" or "sentry"
+	Name string `json:"name"`
+
+	// LastWrite is the time of the last successful write
+	LastWrite time.Time `json:"last_write"`
+
+	// LastError is the error of the last failed write, if any
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorTime is the time of the last failed write
+	LastErrorTime time.Time `json:"last_error_time"`
+
+	// Errors is the number of failed writes
+	Errors uint64 `json:"errors"`
+
+	// QueueUtilization is the fraction (0.0 - 1.0) of the async buffer in
+	// use, for outputs with Config.AsyncBufferSize
+	QueueUtilization float64 `json:"queue_utilization"`
+}
+
+// Healthy reports whether the last write to the sink succeeded
+func (h SinkHealth) Healthy() bool {
+	return h.LastError == "" || h.LastWrite.After(h.LastErrorTime)
+}
+
+// sinkHook is implemented by the hooks writing to a sink
+type sinkHook interface {
+	sinkName() string
+}
+
+// sinkHealth records the writes to a sink
+type sinkHealth struct {
+	name  string
+	queue *AsyncWriter
+
+	mu            sync.Mutex
+	lastWrite     time.Time
+	lastError     error
+	lastErrorTime time.Time
+	errors        uint64
+}
+
+var (
+	sinksMu sync.Mutex
+	sinks   []*sinkHealth
+)
+
+// setSinks makes the sinks of a new logger the ones reported by Health,
+// replacing the sinks of the logger created before
+func setSinks(loggerSinks []*sinkHealth) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks = loggerSinks
+}
+
+// record records the result of a write
+func (s *sinkHealth) record(err error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.lastError = err
+		s.lastErrorTime = now
+		s.errors++
+		return
+	}
+	s.lastWrite = now
+}
+
+// health returns the state of the sink
+func (s *sinkHealth) health() SinkHealth {
+	s.mu.Lock()
+	h := SinkHealth{Name: s.name, LastWrite: s.lastWrite, LastErrorTime: s.lastErrorTime, Errors: s.errors}
+	if s.lastError != nil {
+		h.LastError = s.lastError.Error()
+	}
+	s.mu.Unlock()
+
+	if s.queue != nil {
+		h.QueueUtilization = float64(len(s.queue.queue)) / float64(cap(s.queue.queue))
+	}
+	return h
+}
+
+// Health returns the state of the sinks of the last logger created with
+// NewLogger, usually the singleton, so readiness probes and tooling can
+// detect a log pipeline failing silently
+func Health() []SinkHealth {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	health := make([]SinkHealth, 0, len(sinks))
+	for _, sink := range sinks {
+		health = append(health, sink.health())
+	}
+	return health
+}
+
+// HealthHandler returns a handler responding with the Health of the sinks in
+// JSON, with a 503 status when one of them is unhealthy
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := Health()
+		status := http.StatusOK
+		for _, sink := range health {
+			if !sink.Healthy() {
+				status = http.StatusServiceUnavailable
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(health)
+	})
+}
+
+// healthWriter records the writes to its output
+type healthWriter struct {
+	out  io.Writer
+	sink *sinkHealth
+}
+
+// Write writes p to the output and records the result
+func (w *healthWriter) Write(p []byte) (int, error) {
+	n, err := w.out.Write(p)
+	w.sink.record(err)
+	return n, err
+}
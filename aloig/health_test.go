@@ -0,0 +1,90 @@
+package aloig
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// failingWriter is an output whose writes fail until it is repaired
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	return len(p), nil
+}
+
+// TestHealth tests that the failed and successful writes of a sink are
+// recorded and reported by the handler
+func TestHealth(t *testing.T) {
+	out := &failingWriter{err: errors.New("disk full")}
+	sink := &sinkHealth{name: "test"}
+	setSinks([]*sinkHealth{sink})
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetOutput(&healthWriter{out: out, sink: sink})
+
+	logger.Info("lost")
+	health := sink.health()
+	if health.Healthy() || health.LastError != "disk full" || health.Errors != 1 {
+		t.Errorf("Expected the failure to be recorded, got %+v", health)
+	}
+	if status := serveHealth(t); status != http.StatusServiceUnavailable {
+		t.Errorf("Expected a 503 status, got %d", status)
+	}
+
+	out.err = nil
+	logger.Info("written")
+	if health := sink.health(); !health.Healthy() || health.LastWrite.IsZero() {
+		t.Errorf("Expected the sink to be healthy again, got %+v", health)
+	}
+	if status := serveHealth(t); status != http.StatusOK {
+		t.Errorf("Expected a 200 status, got %d", status)
+	}
+}
+
+// TestHealthReconfigure tests that the sinks of a new logger replace the ones
+// of the logger created before
+func TestHealthReconfigure(t *testing.T) {
+	NewLogger(Config{Environment: "dev"})
+	count := len(Health())
+	NewLogger(Config{Environment: "dev"})
+	if health := Health(); len(health) != count || health[0].Name != "output" {
+		t.Errorf("Expected the sinks of the last logger only, got %+v", health)
+	}
+}
+
+// serveHealth returns the status of the HealthHandler response, checking
+// that it lists the sinks
+func serveHealth(t *testing.T) int {
+	recorder := httptest.NewRecorder()
+	HealthHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/logs", nil))
+
+	var health []SinkHealth
+	if err := json.Unmarshal(recorder.Body.Bytes(), &health); err != nil || len(health) == 0 {
+		t.Errorf("Expected the sinks in JSON, got %s", recorder.Body.String())
+	}
+	return recorder.Code
+}
+
+// TestHealthAsyncQueue tests that the utilization of the async buffer is
+// reported
+func TestHealthAsyncQueue(t *testing.T) {
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, AsyncOptions{BufferSize: 4})
+	sink := &sinkHealth{name: "output", queue: w}
+
+	fillAsyncWriter(t, out, w, "2", "3")
+	if utilization := sink.health().QueueUtilization; utilization != 0.5 {
+		t.Errorf("Expected half of the buffer in use, got %v", utilization)
+	}
+	close(out.gate)
+	w.Close()
+}
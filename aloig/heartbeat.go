@@ -0,0 +1,101 @@
+package aloig
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultHeartbeatInterval is how often a Heartbeat logs by default
+const DefaultHeartbeatInterval = 5 * time.Minute
+
+// processStart is the time the process started, as far as the logs are
+// concerned
+var processStart = time.Now()
+
+// HeartbeatOptions configures a Heartbeat
+type HeartbeatOptions struct {
+	// Logger logs the heartbeats (nil uses the singleton logger)
+	Logger Logger
+
+	// Interval is the time between heartbeats (0 uses
+	// DefaultHeartbeatInterval)
+	Interval time.Duration
+
+	// Version is the version of the service (empty uses the version or
+	// revision of the main module, when the binary has them)
+	Version string
+}
+
+// Heartbeat logs a "still alive" entry at info level every interval with the
+// uptime and version of the service, so alerts on log silence can tell a
+// quiet service from a dead one
+type Heartbeat struct {
+	logger   Logger
+	interval time.Duration
+	version  string
+
+	stop    chan struct{}
+	stopped sync.Once
+	done    chan struct{}
+}
+
+// NewHeartbeat creates a heartbeat; call Start to begin logging
+func NewHeartbeat(options HeartbeatOptions) *Heartbeat {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	version := options.Version
+	if version == "" {
+		version = buildVersion()
+	}
+	return &Heartbeat{
+		logger:   options.Logger,
+		interval: interval,
+		version:  version,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start logs a heartbeat once and then every interval until Stop is called
+func (h *Heartbeat) Start() {
+	go func() {
+		defer close(h.done)
+
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			h.Beat(context.Background())
+			select {
+			case <-ticker.C:
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops logging and waits for a heartbeat in progress to finish
+func (h *Heartbeat) Stop() {
+	h.stopped.Do(func() {
+		close(h.stop)
+	})
+	<-h.done
+}
+
+// Beat logs a heartbeat once
+func (h *Heartbeat) Beat(ctx context.Context) {
+	logger := h.logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+	fields := []Field{Duration("uptime", time.Since(processStart).Round(time.Second))}
+	if h.version != "" {
+		fields = append(fields, String("version", h.version))
+	}
+	logger.Log(ctx, logrus.InfoLevel, "still alive", fields...)
+}
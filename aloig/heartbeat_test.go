@@ -0,0 +1,44 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestHeartbeat tests that a heartbeat carries the uptime and version
+func TestHeartbeat(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	NewHeartbeat(HeartbeatOptions{Logger: logger, Version: "1.4.2"}).Beat(context.Background())
+
+	output := buf.String()
+	if !strings.Contains(output, `msg="still alive"`) || !strings.Contains(output, "uptime=") ||
+		!strings.Contains(output, "version=1.4.2") {
+		t.Errorf("Expected a heartbeat with the uptime and version, got: %s", output)
+	}
+}
+
+// TestHeartbeatStart tests that the heartbeats are logged right away and
+// every interval until Stop
+func TestHeartbeatStart(t *testing.T) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	buf := &lockedBuffer{}
+	logger.logger.SetOutput(buf)
+
+	heartbeat := NewHeartbeat(HeartbeatOptions{Logger: logger, Interval: time.Millisecond})
+	heartbeat.Start()
+	for strings.Count(buf.String(), "still alive") < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	heartbeat.Stop()
+	heartbeat.Stop()
+
+	beats := strings.Count(buf.String(), "still alive")
+	time.Sleep(5 * time.Millisecond)
+	if count := strings.Count(buf.String(), "still alive"); count != beats {
+		t.Errorf("Expected no heartbeat after Stop, got %d more", count-beats)
+	}
+}
@@ -0,0 +1,108 @@
+package aloig
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HookErrorPolicy decides what happens when a hook, such as the Sentry or
+// syslog hook, fails. Failures are counted in Stats whatever the policy.
+type HookErrorPolicy int
+
+const (
+	// HookErrorStderr lets logrus print the failures to stderr
+	HookErrorStderr HookErrorPolicy = iota
+
+	// HookErrorCount only counts the failures
+	HookErrorCount
+
+	// HookErrorWriter writes the failures to Config.HookErrorWriter (stderr
+	// when nil), e.g. a file read when the log pipeline is down
+	HookErrorWriter
+
+	// HookErrorCallback passes the failures to Config.HookErrorHandler, e.g.
+	// to increment a metric
+	HookErrorCallback
+)
+
+// hookErrorPolicyNames are the names of the hook error policies
+var hookErrorPolicyNames = []string{"stderr", "count", "writer", "callback"}
+
+// String returns the name of the policy
+func (p HookErrorPolicy) String() string {
+	if p < 0 || int(p) >= len(hookErrorPolicyNames) {
+		return fmt.Sprintf("HookErrorPolicy(%d)", int(p))
+	}
+	return hookErrorPolicyNames[p]
+}
+
+// ParseHookErrorPolicy returns the policy with the given name ("stderr",
+// "count", "writer" or "callback")
+func ParseHookErrorPolicy(name string) (HookErrorPolicy, error) {
+	for i, policy := range hookErrorPolicyNames {
+		if strings.EqualFold(name, policy) {
+			return HookErrorPolicy(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown hook error policy %q", name)
+}
+
+// HookError is the failure of a hook to process an entry
+type HookError struct {
+	// Hook is the type of the hook, e.g. "*aloig.SentryHook"
+	Hook string
+
+	// Level and Message are those of the entry
+	Level   logrus.Level
+	Message string
+
+	// Err is the error returned by the hook
+	Err error
+}
+
+// Error describes the failure
+func (e *HookError) Error() string {
+	return fmt.Sprintf("hook %s failed on %s entry %q: %v", e.Hook, e.Level, e.Message, e.Err)
+}
+
+// Unwrap returns the error returned by the hook
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// hookErrorHandler returns the function handling the hook failures
+// according to the policy of config, or nil to let logrus print them
+func hookErrorHandler(config Config) func(*HookError) {
+	switch config.HookErrorPolicy {
+	case HookErrorCount:
+		return func(*HookError) {}
+	case HookErrorWriter:
+		out := config.HookErrorWriter
+		if out == nil {
+			out = os.Stderr
+		}
+		return hookErrorWriter(out)
+	case HookErrorCallback:
+		if config.HookErrorHandler != nil {
+			return config.HookErrorHandler
+		}
+	}
+	return nil
+}
+
+// hookErrorWriter returns a handler writing the failures to out, one per
+// line
+func hookErrorWriter(out io.Writer) func(*HookError) {
+	var mu sync.Mutex
+	return func(err *HookError) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(out, "%s aloig: %v\n", time.Now().Format(time.RFC3339), err)
+	}
+}
@@ -0,0 +1,54 @@
+package aloig
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestHookErrorPolicies tests that the hook failures are handled according
+// to the policy
+func TestHookErrorPolicies(t *testing.T) {
+	var handled []*HookError
+	fallback := &bytes.Buffer{}
+	tests := []struct {
+		config   Config
+		returned bool
+	}{
+		{Config{}, true},
+		{Config{HookErrorPolicy: HookErrorCount}, false},
+		{Config{HookErrorPolicy: HookErrorWriter, HookErrorWriter: fallback}, false},
+		{Config{HookErrorPolicy: HookErrorCallback, HookErrorHandler: func(err *HookError) { handled = append(handled, err) }}, false},
+	}
+
+	for _, tt := range tests {
+		hook := statsHook{Hook: failingHook{}, onError: hookErrorHandler(tt.config)}
+		err := hook.Fire(&logrus.Entry{Level: logrus.ErrorLevel, Message: "payment failed"})
+		if (err != nil) != tt.returned {
+			t.Errorf("%s: expected the error returned to logrus to be %v, got %v", tt.config.HookErrorPolicy, tt.returned, err)
+		}
+	}
+
+	if line := fallback.String(); !strings.Contains(line, `aloig: hook aloig.failingHook failed on error entry "payment failed": hook failed`) {
+		t.Errorf("Expected the failure in the fallback writer, got %q", line)
+	}
+	if len(handled) != 1 || handled[0].Hook != "aloig.failingHook" || !errors.Is(handled[0], handled[0].Err) {
+		t.Errorf("Expected the failure passed to the handler, got %v", handled)
+	}
+}
+
+// TestParseHookErrorPolicy tests parsing the policy names
+func TestParseHookErrorPolicy(t *testing.T) {
+	for _, policy := range []HookErrorPolicy{HookErrorStderr, HookErrorCount, HookErrorWriter, HookErrorCallback} {
+		parsed, err := ParseHookErrorPolicy(strings.ToUpper(policy.String()))
+		if err != nil || parsed != policy {
+			t.Errorf("Expected %s, got %v (%v)", policy, parsed, err)
+		}
+	}
+	if _, err := ParseHookErrorPolicy("ignore"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
@@ -0,0 +1,52 @@
+package aloig
+
+import (
+	"net"
+	"runtime"
+)
+
+// hostFields returns the fields describing the host: its primary IP address,
+// when it has one, its operating system and architecture
+func hostFields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"os":   runtime.GOOS,
+		"arch": runtime.GOARCH,
+	}
+	if ip := primaryIP(); ip != nil {
+		fields["host_ip"] = ip.String()
+	}
+	return fields
+}
+
+// primaryIP returns the first global unicast address of the up interfaces,
+// preferring IPv4, or nil when there is none. Nothing is sent on the
+// network.
+func primaryIP() net.IP {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	var ipv6 net.IP
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || !ipNet.IP.IsGlobalUnicast() {
+				continue
+			}
+			if ip := ipNet.IP.To4(); ip != nil {
+				return ip
+			}
+			if ipv6 == nil {
+				ipv6 = ipNet.IP
+			}
+		}
+	}
+	return ipv6
+}
@@ -0,0 +1,48 @@
+package aloig
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestHostFields tests the fields describing the host
+func TestHostFields(t *testing.T) {
+	fields := hostFields()
+	if fields["os"] != runtime.GOOS || fields["arch"] != runtime.GOARCH {
+		t.Errorf("Expected the OS and architecture, got %v", fields)
+	}
+	if ip := primaryIP(); (ip != nil) != (fields["host_ip"] != nil) || (ip != nil && ip.IsLoopback()) {
+		t.Errorf("Expected the non-loopback primary IP when there is one, got %v", fields)
+	}
+}
+
+// TestNewLoggerHostName tests that the host name falls back on the system's
+// and that the host fields are only added when enabled
+func TestNewLoggerHostName(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		t.Skip("no system host name")
+	}
+
+	for _, enabled := range []bool{false, true} {
+		logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel, HostFields: enabled}).(*logrusLogger)
+		var buf bytes.Buffer
+		logger.logger.SetOutput(&buf)
+		logger.logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+		logger.Info("started")
+
+		output := buf.String()
+		if !strings.Contains(output, "hostname="+hostname) {
+			t.Errorf("Expected the system host name, got: %s", output)
+		}
+		if added := strings.Contains(output, "os="+runtime.GOOS) && strings.Contains(output, "arch="+runtime.GOARCH); added != enabled {
+			t.Errorf("Expected the host fields only when enabled (%v), got: %s", enabled, output)
+		}
+	}
+}
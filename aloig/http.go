@@ -0,0 +1,285 @@
+package aloig
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Headers used by the HTTP middleware to propagate IDs
+const (
+	// TraceIDHeader carries the trace ID of a request
+	TraceIDHeader = "X-Trace-ID"
+	// RequestIDHeader carries the request ID of a request
+	RequestIDHeader = "X-Request-ID"
+)
+
+// RedactedValue replaces the values removed from logs
+const RedactedValue = "[REDACTED]"
+
+// LatencyBucketField holds the coarse latency bucket of a request
+const LatencyBucketField = "latency_bucket"
+
+// DefaultLatencyBuckets are the upper bounds of the latency buckets
+var DefaultLatencyBuckets = []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond, time.Second}
+
+// DefaultRedactedHeaders are the headers whose values are never logged
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// DefaultRedactedBodyFields are the body fields whose values are never logged
+var DefaultRedactedBodyFields = []string{"password", "passwd", "secret", "token", "access_token", "refresh_token", "api_key", "card_number", "cvv"}
+
+// HTTPOptions configures the HTTP middleware
+type HTTPOptions struct {
+	// Logger receives the access log entries. The singleton logger is used
+	// when nil.
+	Logger Logger
+
+	// CaptureHeaders adds the request headers to the access log entry
+	CaptureHeaders bool
+
+	// MaxBodySize adds up to MaxBodySize bytes of the request body to the
+	// access log entry. Zero disables body capture.
+	MaxBodySize int
+
+	// CaptureBodyOnError only adds the request body to the entries of the
+	// failed requests, logged at error level and so sent to Sentry, keeping
+	// it out of the others
+	CaptureBodyOnError bool
+
+	// RedactHeaders are the headers logged as [REDACTED].
+	// DefaultRedactedHeaders is used when nil.
+	RedactHeaders []string
+
+	// RedactBodyFields are the JSON and form fields logged as [REDACTED].
+	// DefaultRedactedBodyFields is used when nil.
+	RedactBodyFields []string
+
+	// LatencyBuckets are the increasing upper bounds of the latency buckets
+	// (see LatencyBucket). DefaultLatencyBuckets is used when nil, and an
+	// empty slice leaves the field out.
+	LatencyBuckets []time.Duration
+
+	// PropagateBaggage adds the context fields sent by the caller in
+	// BaggageHeader to the request context. Only enable it for internal
+	// callers, since they choose the fields logged.
+	PropagateBaggage bool
+}
+
+// HTTPMiddleware returns a middleware that adds the trace and request IDs
+// of the X-Trace-ID and X-Request-ID headers (generating them when missing)
+// to the request context, and emits one access log entry per request with
+// the method, path, status, response size and duration. Server errors are
+// logged at error level, client errors at warn level and the rest at info.
+func HTTPMiddleware(options HTTPOptions) func(http.Handler) http.Handler {
+	redactHeaders := options.RedactHeaders
+	if redactHeaders == nil {
+		redactHeaders = DefaultRedactedHeaders
+	}
+	redactFields := options.RedactBodyFields
+	if redactFields == nil {
+		redactFields = DefaultRedactedBodyFields
+	}
+	bodyPattern := redactedBodyPattern(redactFields)
+	buckets := options.LatencyBuckets
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ctx := r.Context()
+			if options.PropagateBaggage {
+				ctx = ExtractBaggage(ctx, r.Header)
+			}
+			traceID := r.Header.Get(TraceIDHeader)
+			if traceID == "" {
+				traceID = GenerateTraceID()
+			}
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = GenerateRequestID()
+			}
+			ctx = WithRequestID(WithTraceID(ctx, traceID), requestID)
+			w.Header().Set(TraceIDHeader, traceID)
+
+			var body []byte
+			if options.MaxBodySize > 0 && r.Body != nil && r.Body != http.NoBody {
+				body = peekBody(r, options.MaxBodySize)
+			}
+
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			duration := time.Since(start)
+			fields := map[string]interface{}{
+				"method":        r.Method,
+				"path":          r.URL.Path,
+				"status":        rw.status,
+				"response_size": rw.size,
+				"duration_ms":   float64(duration) / float64(time.Millisecond),
+				"remote_addr":   r.RemoteAddr,
+				"user_agent":    r.UserAgent(),
+			}
+			if bucket := LatencyBucket(duration, buckets); bucket != "" {
+				fields[LatencyBucketField] = bucket
+			}
+			if options.CaptureHeaders {
+				fields["request_headers"] = redactHeaderValues(r.Header, redactHeaders)
+			}
+			if len(body) > 0 && (!options.CaptureBodyOnError || rw.status >= 500) {
+				fields["request_body"] = redactBody(string(body), r.Header.Get("Content-Type"), redactFields, bodyPattern)
+			}
+
+			logger := options.Logger
+			if logger == nil {
+				logger = GetLogger()
+			}
+			entry := logger.WithFields(fields)
+			switch {
+			case rw.status >= 500:
+				entry.ErrorContext(ctx, "http request")
+			case rw.status >= 400:
+				entry.WarnContext(ctx, "http request")
+			default:
+				entry.InfoContext(ctx, "http request")
+			}
+		})
+	}
+}
+
+// LatencyBucket returns the name of the bucket of duration among the
+// increasing upper bounds, e.g. "lt_100ms", or "gte_1s" beyond the last one.
+// Grouping by bucket in log queries is cheaper than computing percentiles of
+// the exact durations. It returns "" without bounds.
+func LatencyBucket(duration time.Duration, bounds []time.Duration) string {
+	for _, bound := range bounds {
+		if duration < bound {
+			return "lt_" + bound.String()
+		}
+	}
+	if len(bounds) == 0 {
+		return ""
+	}
+	return "gte_" + bounds[len(bounds)-1].String()
+}
+
+// peekBody reads up to maxSize bytes of the request body and puts them back
+// so the handler still reads the whole body
+func peekBody(r *http.Request, maxSize int) []byte {
+	body, _ := io.ReadAll(io.LimitReader(r.Body, int64(maxSize)))
+	r.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	return body
+}
+
+// peekedBody is a request body whose beginning was already read
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// redactHeaderValues flattens the headers, redacting the sensitive ones
+func redactHeaderValues(header http.Header, redact []string) map[string]string {
+	values := make(map[string]string, len(header))
+	for name, value := range header {
+		values[name] = strings.Join(value, ", ")
+	}
+	for _, name := range redact {
+		name = http.CanonicalHeaderKey(name)
+		if _, ok := values[name]; ok {
+			values[name] = RedactedValue
+		}
+	}
+	return values
+}
+
+// redactedBodyPattern matches the JSON members whose key is one of fields
+func redactedBodyPattern(fields []string) *regexp.Regexp {
+	if len(fields) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = regexp.QuoteMeta(field)
+	}
+	return regexp.MustCompile(`(?i)("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+}
+
+// redactBody replaces the values of the sensitive fields of a form or JSON
+// body. Truncated bodies are redacted as far as they go.
+func redactBody(body, contentType string, fields []string, pattern *regexp.Regexp) string {
+	if len(fields) == 0 {
+		return body
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(body)
+		if err == nil {
+			for _, field := range fields {
+				for key := range values {
+					if strings.EqualFold(key, field) {
+						values[key] = []string{RedactedValue}
+					}
+				}
+			}
+			return values.Encode()
+		}
+	}
+
+	return pattern.ReplaceAllString(body, `${1}"`+RedactedValue+`"`)
+}
+
+// responseWriter records the status and size of a response
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the response size
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.size += n
+	return n, err
+}
+
+// Flush sends buffered data to the client when supported
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection when supported
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the original response writer
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,147 @@
+package aloig
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestHTTPMiddleware tests the access log entry and the request context IDs
+func TestHTTPMiddleware(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	var traceID, requestID string
+	handler := HTTPMiddleware(HTTPOptions{Logger: logger})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = GetTraceID(r.Context())
+		requestID = GetRequestID(r.Context())
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	req.Header.Set(TraceIDHeader, "trace-http")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if traceID != "trace-http" || requestID == "" {
+		t.Errorf("Expected trace and request IDs in context, got %q and %q", traceID, requestID)
+	}
+	if rec.Header().Get(TraceIDHeader) != "trace-http" {
+		t.Errorf("Expected trace ID response header, got %q", rec.Header().Get(TraceIDHeader))
+	}
+
+	output := buf.String()
+	for _, expected := range []string{"level=warning", `msg="http request"`, "method=GET", "path=/orders/1", "status=404", "response_size=9", "duration_ms=", "latency_bucket=lt_", "trace_id=trace-http", "request_id=" + requestID} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "request_headers") || strings.Contains(output, "request_body") {
+		t.Errorf("Expected no captured request by default, got: %s", output)
+	}
+}
+
+// TestHTTPMiddlewareCapture tests that captured headers and bodies are size-limited and redacted
+func TestHTTPMiddlewareCapture(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	var received string
+	handler := HTTPMiddleware(HTTPOptions{Logger: logger, CaptureHeaders: true, MaxBodySize: 64})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+
+	body := `{"email":"a@example.com","password":"hunter2","note":"` + strings.Repeat("x", 100) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if received != body {
+		t.Errorf("Expected the handler to read the whole body, got: %s", received)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "hunter2") || strings.Contains(output, "Bearer abc") {
+		t.Errorf("Expected secrets to be redacted, got: %s", output)
+	}
+	if !strings.Contains(output, "a@example.com") || !strings.Contains(output, "Content-Type:application/json") {
+		t.Errorf("Expected captured headers and body, got: %s", output)
+	}
+	if strings.Contains(output, strings.Repeat("x", 100)) {
+		t.Errorf("Expected the body to be truncated, got: %s", output)
+	}
+}
+
+// TestHTTPMiddlewareCaptureBodyOnError tests that the body is only added to
+// the entries of the failed requests
+func TestHTTPMiddlewareCaptureBodyOnError(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	handler := HTTPMiddleware(HTTPOptions{Logger: logger, MaxBodySize: 256, CaptureBodyOnError: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "fail"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.Contains(string(body), "invalid"):
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	for _, status := range []string{"ok", "invalid", "fail"} {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"status":"`+status+`","token":"s3cr3t"}`))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		output := buf.String()
+		if captured := strings.Contains(output, "request_body="); captured != (status == "fail") {
+			t.Errorf("Expected the body of the failed requests only (%s), got: %s", status, output)
+		}
+		if strings.Contains(output, "s3cr3t") {
+			t.Errorf("Expected the body to be redacted, got: %s", output)
+		}
+	}
+}
+
+// TestLatencyBucket tests the bucket names around the bounds
+func TestLatencyBucket(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		bucket   string
+	}{
+		{0, "lt_10ms"},
+		{9 * time.Millisecond, "lt_10ms"},
+		{10 * time.Millisecond, "lt_100ms"},
+		{499 * time.Millisecond, "lt_500ms"},
+		{999 * time.Millisecond, "lt_1s"},
+		{time.Second, "gte_1s"},
+		{time.Minute, "gte_1s"},
+	}
+	for _, tt := range tests {
+		if bucket := LatencyBucket(tt.duration, DefaultLatencyBuckets); bucket != tt.bucket {
+			t.Errorf("Expected %s for %s, got %s", tt.bucket, tt.duration, bucket)
+		}
+	}
+	if bucket := LatencyBucket(time.Second, nil); bucket != "" {
+		t.Errorf("Expected no bucket without bounds, got %s", bucket)
+	}
+}
+
+// TestRedactBody tests form and JSON body redaction
+func TestRedactBody(t *testing.T) {
+	fields := []string{"password", "token"}
+	pattern := redactedBodyPattern(fields)
+
+	if redacted := redactBody("user=bob&password=hunter2", "application/x-www-form-urlencoded", fields, pattern); redacted != "password=%5BREDACTED%5D&user=bob" {
+		t.Errorf("Unexpected form redaction: %s", redacted)
+	}
+	if redacted := redactBody(`{"token": 123, "Password":"a\"b"}`, "application/json", fields, pattern); redacted != `{"token": "[REDACTED]", "Password":"[REDACTED]"}` {
+		t.Errorf("Unexpected JSON redaction: %s", redacted)
+	}
+}
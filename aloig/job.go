@@ -0,0 +1,68 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Fields of the background job entries
+const (
+	// JobField holds the name of the job
+	JobField = "job"
+
+	// JobRunIDField holds the ID of the job run
+	JobRunIDField = "job_run_id"
+)
+
+// Job returns a function running fn as the named background job with a
+// background context (see RunJob), for cron schedulers:
+//
+//	c.AddFunc("@hourly", aloig.Job("expire_carts", expireCarts))
+func Job(name string, fn func(ctx context.Context) error) func() {
+	return func() {
+		_ = RunJob(context.Background(), name, fn)
+	}
+}
+
+// RunJob runs fn as one run of the named job. fn gets a context carrying a
+// trace ID and the job and job_run_id fields, so its context logs are
+// correlated with the run. "job started" is logged when it starts, and "job
+// finished" or "job failed" with the duration and error when it ends. A
+// panic is recovered, logged with its stack trace, reported to Sentry and
+// returned as an error.
+func RunJob(ctx context.Context, name string, fn func(ctx context.Context) error) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	runID := GenerateTraceID()
+	ctx, _ = EnsureTraceID(ctx)
+	ctx = ContextWithFields(ctx, map[string]interface{}{JobField: name, JobRunIDField: runID})
+
+	logger := GetLogger()
+	logger.InfoContext(ctx, "job started")
+	start := time.Now()
+
+	defer func() {
+		fields := map[string]interface{}{}
+		if value := recover(); value != nil {
+			err = fmt.Errorf("job %s panicked: %v", name, value)
+			fields["panic"] = fmt.Sprint(value)
+			fields[StackTraceField] = cleanStackTrace(debug.Stack(), "aloig.RunJob", "runtime/panic.go", "panic(")
+			if eventID := recoverToSentry(ctx, nil, value); eventID != "" {
+				fields[SentryEventIDField] = eventID
+			}
+		}
+
+		fields["duration_ms"] = float64(time.Since(start)) / float64(time.Millisecond)
+		fields["success"] = err == nil
+		if err != nil {
+			logger.WithFields(fields).WithError(err).ErrorContext(ctx, "job failed")
+			return
+		}
+		logger.WithFields(fields).InfoContext(ctx, "job finished")
+	}()
+
+	return fn(ctx)
+}
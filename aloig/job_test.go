@@ -0,0 +1,54 @@
+package aloig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRunJob tests the entries of successful, failed and panicking runs
+func TestRunJob(t *testing.T) {
+	buf, cleanup := setupTestLogger()
+	defer cleanup()
+
+	var runID string
+	err := RunJob(context.Background(), "expire_carts", func(ctx context.Context) error {
+		runID, _ = ExtractContextFields(ctx)[JobRunIDField].(string)
+		InfoContext(ctx, "expiring carts")
+		return nil
+	})
+	if err != nil || runID == "" {
+		t.Fatalf("Expected a successful run with a run ID, got %v and %q", err, runID)
+	}
+	output := buf.String()
+	if strings.Count(output, "job_run_id="+runID) != 3 || strings.Count(output, "job=expire_carts") != 3 {
+		t.Errorf("Expected every entry to carry the job and run ID, got: %s", output)
+	}
+	for _, expected := range []string{`msg="job started"`, `msg="expiring carts"`, `msg="job finished"`, "success=true", "duration_ms=", "trace_id="} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	err = RunJob(context.Background(), "sync_orders", func(ctx context.Context) error {
+		return errors.New("upstream timeout")
+	})
+	if err == nil || !strings.Contains(buf.String(), `msg="job failed"`) || !strings.Contains(buf.String(), `error="upstream timeout"`) {
+		t.Errorf("Expected a failed run, got %v and: %s", err, buf.String())
+	}
+
+	buf.Reset()
+	Job("reindex", func(ctx context.Context) error {
+		var m map[string]int
+		m["x"] = 1
+		return nil
+	})()
+	output = buf.String()
+	for _, expected := range []string{"level=error", `msg="job failed"`, "job=reindex", `panic="assignment to entry in nil map"`, "stack_trace=", "success=false"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
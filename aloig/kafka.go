@@ -0,0 +1,59 @@
+package aloig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// KafkaComponent is the component field value of Kafka client entries
+const KafkaComponent = "kafka"
+
+// KafkaLogger forwards the internal logs of Kafka clients to aloig. It
+// implements sarama's StdLogger interface (Print, Printf, Println) and
+// kafka-go's Logger interface (Printf), so it can be assigned to
+// sarama.Logger, sarama.DebugLogger, kafka.Writer.Logger or
+// kafka.ReaderConfig.ErrorLogger without aloig depending on either client.
+// Entries carry the logger's standard fields (env, appname, ...) and the
+// component field.
+type KafkaLogger struct {
+	logger Logger
+	level  logrus.Level
+}
+
+// NewKafkaLogger returns a Kafka client logger writing to logger at level.
+// A nil logger writes to the singleton logger.
+func NewKafkaLogger(logger Logger, level logrus.Level) *KafkaLogger {
+	return &KafkaLogger{logger: logger, level: level}
+}
+
+// NewKafkaErrorLogger returns a Kafka client logger writing to logger at
+// error level, for kafka-go's ErrorLogger
+func NewKafkaErrorLogger(logger Logger) *KafkaLogger {
+	return NewKafkaLogger(logger, logrus.ErrorLevel)
+}
+
+// Print logs the arguments like fmt.Sprint
+func (l *KafkaLogger) Print(v ...interface{}) {
+	l.log(fmt.Sprint(v...))
+}
+
+// Printf logs the arguments like fmt.Sprintf
+func (l *KafkaLogger) Printf(format string, v ...interface{}) {
+	l.log(fmt.Sprintf(format, v...))
+}
+
+// Println logs the arguments like fmt.Sprintln
+func (l *KafkaLogger) Println(v ...interface{}) {
+	l.log(fmt.Sprintln(v...))
+}
+
+// log writes msg without the trailing newline Kafka clients often add
+func (l *KafkaLogger) log(msg string) {
+	logger := l.logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+	logAtLevel(logger.WithField("component", KafkaComponent), l.level, strings.TrimRight(msg, "\n"))
+}
@@ -0,0 +1,58 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// saramaStdLogger mirrors sarama's StdLogger interface
+type saramaStdLogger interface {
+	Print(v ...interface{})
+	Printf(format string, v ...interface{})
+	Println(v ...interface{})
+}
+
+// kafkaGoLogger mirrors kafka-go's Logger interface
+type kafkaGoLogger interface {
+	Printf(string, ...interface{})
+}
+
+var (
+	_ saramaStdLogger = (*KafkaLogger)(nil)
+	_ kafkaGoLogger   = (*KafkaLogger)(nil)
+)
+
+// TestKafkaLogger tests that Kafka client logs are forwarded with the component field
+func TestKafkaLogger(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	kafkaLogger := NewKafkaLogger(logger, logrus.InfoLevel)
+	kafkaLogger.Printf("client/metadata fetching metadata for topic %s from broker %s\n", "orders", "b-1:9092")
+	kafkaLogger.Println("Connected to broker")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], `msg="client/metadata fetching metadata for topic orders from broker b-1:9092"`) {
+		t.Errorf("Expected the formatted message without newline, got: %s", lines[0])
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "level=info") || !strings.Contains(line, "component=kafka") {
+			t.Errorf("Expected an info entry with the component field, got: %s", line)
+		}
+	}
+}
+
+// TestKafkaErrorLogger tests that the error logger writes at error level
+func TestKafkaErrorLogger(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	NewKafkaErrorLogger(logger).Printf("failed to dial: %v", "connection refused")
+
+	if output := buf.String(); !strings.Contains(output, "level=error") || !strings.Contains(output, "failed to dial: connection refused") {
+		t.Errorf("Expected an error entry, got: %s", output)
+	}
+}
@@ -0,0 +1,158 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// LevelNameField is the field holding the name of the custom level of an
+// entry. The JSON formatter writes it as the level of the entry.
+const LevelNameField = "level_name"
+
+// BaseLevelField is the field the JSON formatter writes the logrus level of
+// custom level entries to, so pipelines filtering on standard levels keep
+// working
+const BaseLevelField = "base_level"
+
+// CustomLevel is a named level placed on top of a standard logrus level.
+// Entries are filtered, hooked and sent to Sentry according to Base, and
+// carry the name of the level in the JSON output, in syslog and in Sentry.
+type CustomLevel struct {
+	Name string
+
+	// Base is the logrus level the custom level behaves as. It can't be
+	// PanicLevel or FatalLevel.
+	Base logrus.Level
+
+	// Syslog is the severity of the entries in syslog output
+	Syslog SyslogSeverity
+
+	// Sentry is the level of the Sentry events and breadcrumbs
+	Sentry sentry.Level
+}
+
+// Built-in custom levels
+var (
+	// NoticeLevel is for normal but significant conditions
+	NoticeLevel = CustomLevel{Name: "notice", Base: logrus.InfoLevel, Syslog: SyslogSeverityNotice, Sentry: sentry.LevelInfo}
+	// CriticalLevel is for errors requiring immediate attention
+	CriticalLevel = CustomLevel{Name: "critical", Base: logrus.ErrorLevel, Syslog: SyslogSeverityCritical, Sentry: sentry.LevelFatal}
+	// AuditLevel is for audit trail records
+	AuditLevel = CustomLevel{Name: "audit", Base: logrus.InfoLevel, Syslog: SyslogSeverityNotice, Sentry: sentry.LevelInfo}
+)
+
+var (
+	customLevelMu sync.RWMutex
+	customLevels  = map[string]CustomLevel{
+		NoticeLevel.Name:   NoticeLevel,
+		CriticalLevel.Name: CriticalLevel,
+		AuditLevel.Name:    AuditLevel,
+	}
+)
+
+// RegisterLevel registers a custom level so formatters and hooks map its
+// entries. Registering a standard level name or the same name twice is an
+// error.
+func RegisterLevel(level CustomLevel) error {
+	if level.Name == "" {
+		return fmt.Errorf("custom level without name")
+	}
+	if _, err := logrus.ParseLevel(level.Name); err == nil {
+		return fmt.Errorf("level %q is a standard level", level.Name)
+	}
+	if level.Base <= logrus.FatalLevel {
+		return fmt.Errorf("custom level %q can't be based on %s", level.Name, level.Base)
+	}
+
+	customLevelMu.Lock()
+	defer customLevelMu.Unlock()
+
+	if _, ok := customLevels[level.Name]; ok {
+		return fmt.Errorf("level %q already registered", level.Name)
+	}
+	customLevels[level.Name] = level
+	return nil
+}
+
+// LookupLevel returns the registered custom level with the given name
+func LookupLevel(name string) (CustomLevel, bool) {
+	customLevelMu.RLock()
+	defer customLevelMu.RUnlock()
+	level, ok := customLevels[name]
+	return level, ok
+}
+
+// LogCustom logs msg at the custom level with the context fields and the
+// given typed fields. A nil logger means the singleton logger.
+func LogCustom(ctx context.Context, logger Logger, level CustomLevel, msg string, fields ...Field) {
+	if logger == nil {
+		logger = GetLogger()
+	}
+	logger.With(String(LevelNameField, level.Name)).Log(ctx, level.Base, msg, fields...)
+}
+
+// Notice logs msg at notice level using the singleton logger
+func Notice(ctx context.Context, msg string, fields ...Field) {
+	LogCustom(ctx, nil, NoticeLevel, msg, fields...)
+}
+
+// Critical logs msg at critical level using the singleton logger
+func Critical(ctx context.Context, msg string, fields ...Field) {
+	LogCustom(ctx, nil, CriticalLevel, msg, fields...)
+}
+
+// Audit logs msg at audit level using the singleton logger
+func Audit(ctx context.Context, msg string, fields ...Field) {
+	LogCustom(ctx, nil, AuditLevel, msg, fields...)
+}
+
+// entryCustomLevel returns the registered custom level of the entry
+func entryCustomLevel(entry *logrus.Entry) (CustomLevel, bool) {
+	name, ok := entry.Data[LevelNameField].(string)
+	if !ok {
+		return CustomLevel{}, false
+	}
+	return LookupLevel(name)
+}
+
+// entrySyslogSeverity returns the syslog severity of the entry
+func entrySyslogSeverity(entry *logrus.Entry) SyslogSeverity {
+	if level, ok := entryCustomLevel(entry); ok {
+		return level.Syslog
+	}
+	return SyslogLevelSeverity(entry.Level)
+}
+
+// entrySentryLevel returns the Sentry level of the entry
+func entrySentryLevel(entry *logrus.Entry) sentry.Level {
+	if level, ok := entryCustomLevel(entry); ok && level.Sentry != "" {
+		return level.Sentry
+	}
+	return sentryLevels[entry.Level]
+}
+
+// formatCustomLevel formats a custom level entry with a copy of formatter
+// writing the name of the level as the level and the logrus level to
+// BaseLevelField. Other entries are formatted as is.
+func formatCustomLevel(formatter *logrus.JSONFormatter, entry *logrus.Entry) ([]byte, error) {
+	name, ok := entry.Data[LevelNameField].(string)
+	if !ok {
+		return formatter.Format(entry)
+	}
+
+	levelKey := logrus.FieldKeyLevel
+	if key, ok := formatter.FieldMap[logrus.FieldKeyLevel]; ok {
+		levelKey = key
+	}
+	copied := getJSONFormatter(formatter)
+	defer putJSONFormatter(copied)
+	copied.FieldMap[logrus.FieldKeyLevel] = BaseLevelField
+
+	delete(entry.Data, LevelNameField)
+	entry.Data[levelKey] = name
+	return copied.Format(entry)
+}
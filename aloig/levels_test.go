@@ -0,0 +1,101 @@
+package aloig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// TestRegisterLevel tests custom level registration rules
+func TestRegisterLevel(t *testing.T) {
+	for _, level := range []CustomLevel{
+		{Name: "", Base: logrus.InfoLevel},
+		{Name: "warning", Base: logrus.InfoLevel},
+		{Name: "doom", Base: logrus.FatalLevel},
+		{Name: "notice", Base: logrus.InfoLevel},
+	} {
+		if err := RegisterLevel(level); err == nil {
+			t.Errorf("Expected an error registering %+v", level)
+		}
+	}
+
+	verbose := CustomLevel{Name: "verbose", Base: logrus.TraceLevel, Syslog: SyslogSeverityDebug, Sentry: sentry.LevelDebug}
+	if err := RegisterLevel(verbose); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		customLevelMu.Lock()
+		delete(customLevels, verbose.Name)
+		customLevelMu.Unlock()
+	}()
+	if level, ok := LookupLevel("verbose"); !ok || level != verbose {
+		t.Errorf("Expected the registered level, got %+v", level)
+	}
+}
+
+// TestLogCustom tests that custom levels are filtered by their base level
+func TestLogCustom(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.WarnLevel)
+	ctx := WithTraceID(context.Background(), "trace-level")
+
+	LogCustom(ctx, logger, NoticeLevel, "disabled")
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output below the logger level, got: %s", buf.String())
+	}
+
+	LogCustom(ctx, logger, CriticalLevel, "disk full", String("disk", "/data"))
+	output := buf.String()
+	for _, expected := range []string{"level=error", "level_name=critical", `msg="disk full"`, "disk=/data", "trace_id=trace-level"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
+
+// TestCustomLevelJSON tests that the JSON formatter writes the custom level name
+func TestCustomLevelJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logrusInstance := logrus.New()
+	logrusInstance.SetOutput(&buf)
+	logrusInstance.SetFormatter(&CallerJSONFormatter{JSONFormatter: &logrus.JSONFormatter{}})
+
+	LogCustom(context.Background(), FromLogrus(logrusInstance), AuditLevel, "user deleted")
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	if data["level"] != "audit" || data[BaseLevelField] != "info" {
+		t.Errorf("Expected audit level based on info, got: %s", buf.String())
+	}
+	if _, ok := data[LevelNameField]; ok {
+		t.Errorf("Expected no %s field, got: %s", LevelNameField, buf.String())
+	}
+}
+
+// TestCustomLevelMapping tests the syslog and Sentry levels of custom level entries
+func TestCustomLevelMapping(t *testing.T) {
+	entry := &logrus.Entry{Level: logrus.ErrorLevel, Data: logrus.Fields{LevelNameField: "critical"}}
+	if severity := entrySyslogSeverity(entry); severity != SyslogSeverityCritical {
+		t.Errorf("Expected critical syslog severity, got %d", severity)
+	}
+
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+	entry.Message = "disk full"
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if events := transport.Events(); len(events) != 1 || events[0].Level != sentry.LevelFatal {
+		t.Errorf("Expected a fatal Sentry event, got %+v", events)
+	}
+
+	if severity := entrySyslogSeverity(&logrus.Entry{Level: logrus.WarnLevel, Data: logrus.Fields{}}); severity != SyslogSeverityWarning {
+		t.Errorf("Expected the standard mapping, got %d", severity)
+	}
+}
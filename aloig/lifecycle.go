@@ -0,0 +1,94 @@
+package aloig
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// loggerResources are the resources of a logger created with NewLogger
+// beyond its logrus logger: its async writer and hooks, its deduplication
+// and sampling windows and its Sentry hook. They are released when the
+// process exits through logrus (Fatal), or when Reconfigure replaces the
+// logger; the logger keeps working afterwards, writing synchronously.
+type loggerResources struct {
+	mu       sync.Mutex
+	handlers []func()
+	closed   bool
+}
+
+var (
+	resourcesMu   sync.Mutex
+	liveResources []*loggerResources
+	exitOnce      sync.Once
+)
+
+// newLoggerResources returns the resources of a new logger, released on
+// exit along with those of the other live loggers
+func newLoggerResources() *loggerResources {
+	exitOnce.Do(func() {
+		logrus.RegisterExitHandler(closeLiveResources)
+	})
+
+	r := &loggerResources{}
+	resourcesMu.Lock()
+	defer resourcesMu.Unlock()
+	liveResources = append(liveResources, r)
+	return r
+}
+
+// onClose registers handler to run when the resources are released. The
+// handlers run in the order they were registered, except the deferred ones
+// which run before the others, in reverse order.
+func (r *loggerResources) onClose(handler func(), deferred bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if deferred {
+		r.handlers = append([]func(){handler}, r.handlers...)
+		return
+	}
+	r.handlers = append(r.handlers, handler)
+}
+
+// close runs the handlers once and forgets the resources
+func (r *loggerResources) close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	handlers := r.handlers
+	r.handlers = nil
+	r.mu.Unlock()
+
+	resourcesMu.Lock()
+	liveResources = removeItem(liveResources, r)
+	resourcesMu.Unlock()
+
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+// removeItem returns items without item, reusing their array
+func removeItem[T comparable](items []T, item T) []T {
+	for i, it := range items {
+		if it == item {
+			return append(items[:i], items[i+1:]...)
+		}
+	}
+	return items
+}
+
+// closeLiveResources releases the resources of the live loggers, when the
+// process exits
+func closeLiveResources() {
+	resourcesMu.Lock()
+	live := append([]*loggerResources(nil), liveResources...)
+	resourcesMu.Unlock()
+
+	for _, r := range live {
+		r.close()
+	}
+}
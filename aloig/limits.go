@@ -0,0 +1,70 @@
+package aloig
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Default size limits used by DefaultConfig
+const (
+	DefaultMaxMessageLength = 32 * 1024
+	DefaultMaxFieldLength   = 16 * 1024
+)
+
+// SizeLimitHook is a hook that truncates long messages and string, []byte
+// and error field values, appending a marker with the number of bytes
+// removed, so an accidental dump of a large payload can't flood the log
+// pipeline. A zero limit disables truncation.
+type SizeLimitHook struct {
+	MaxMessageLength int
+	MaxFieldLength   int
+}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *SizeLimitHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire truncates the message and field values of the entry
+func (hook *SizeLimitHook) Fire(entry *logrus.Entry) error {
+	if hook.MaxMessageLength > 0 {
+		entry.Message = truncate(entry.Message, hook.MaxMessageLength)
+	}
+	if hook.MaxFieldLength <= 0 {
+		return nil
+	}
+
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			if len(v) > hook.MaxFieldLength {
+				entry.Data[key] = truncate(v, hook.MaxFieldLength)
+			}
+		case []byte:
+			if len(v) > hook.MaxFieldLength {
+				entry.Data[key] = truncate(string(v), hook.MaxFieldLength)
+			}
+		case error:
+			if msg := v.Error(); len(msg) > hook.MaxFieldLength {
+				entry.Data[key] = truncate(msg, hook.MaxFieldLength)
+			}
+		}
+	}
+	return nil
+}
+
+// truncate cuts s to at most max bytes on a rune boundary and appends a
+// marker with the number of bytes removed
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + fmt.Sprintf("...[truncated %d bytes]", len(s)-cut)
+}
@@ -0,0 +1,46 @@
+package aloig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestSizeLimitHook tests that long messages and field values are truncated
+func TestSizeLimitHook(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(&SizeLimitHook{MaxMessageLength: 10, MaxFieldLength: 8})
+
+	logger.WithFields(map[string]interface{}{
+		"body":  strings.Repeat("a", 100),
+		"raw":   []byte(strings.Repeat("b", 20)),
+		"short": "ok",
+		"count": 123456789012,
+	}).WithError(errors.New(strings.Repeat("c", 30))).Info(strings.Repeat("m", 50))
+
+	output := buf.String()
+	for _, expected := range []string{
+		`msg="mmmmmmmmmm...[truncated 40 bytes]"`,
+		`body="aaaaaaaa...[truncated 92 bytes]"`,
+		`raw="bbbbbbbb...[truncated 12 bytes]"`,
+		`error="cccccccc...[truncated 22 bytes]"`,
+		"short=ok",
+		"count=123456789012",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
+
+// TestTruncateRuneBoundary tests that truncation doesn't split multi-byte characters
+func TestTruncateRuneBoundary(t *testing.T) {
+	if truncated := truncate("añb", 2); truncated != "a...[truncated 3 bytes]" {
+		t.Errorf("Unexpected truncation: %q", truncated)
+	}
+	if truncated := truncate("short", 10); truncated != "short" {
+		t.Errorf("Expected short strings to be kept, got %q", truncated)
+	}
+}
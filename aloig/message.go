@@ -0,0 +1,107 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+)
+
+// MessageCarrier is the metadata of a queued message carrying the logging
+// context from the producer to the consumer
+type MessageCarrier interface {
+	// Get returns the value of key, or "" when it is not set
+	Get(key string) string
+
+	// Set sets key to value, replacing any previous value
+	Set(key, value string)
+}
+
+// InjectMessage writes the trace and request IDs of ctx to the
+// TraceIDHeader and RequestIDHeader keys of carrier and its context fields
+// to the BaggageHeader key, when they are set
+func InjectMessage(ctx context.Context, carrier MessageCarrier) {
+	if traceID := GetTraceID(ctx); traceID != "" {
+		carrier.Set(TraceIDHeader, traceID)
+	}
+	if requestID := GetRequestID(ctx); requestID != "" {
+		carrier.Set(RequestIDHeader, requestID)
+	}
+	if baggage := EncodeBaggage(ctx); baggage != "" {
+		carrier.Set(BaggageHeader, baggage)
+	}
+}
+
+// ExtractMessage returns a context carrying the IDs and context fields
+// written by InjectMessage on top of ctx, for the logs of the consumer of a
+// message. A trace ID is generated when the message has none.
+func ExtractMessage(ctx context.Context, carrier MessageCarrier) context.Context {
+	ctx, _ = DecodeBaggage(ctx, carrier.Get(BaggageHeader))
+	if traceID := carrier.Get(TraceIDHeader); traceID != "" {
+		ctx = WithTraceID(ctx, traceID)
+	}
+	if requestID := carrier.Get(RequestIDHeader); requestID != "" {
+		ctx = WithRequestID(ctx, requestID)
+	}
+	ctx, _ = EnsureTraceID(ctx)
+	return ctx
+}
+
+// KafkaHeader is a Kafka record header. It has the layout of the kafka-go
+// and confluent-kafka-go headers, so they can be converted to and from it
+// (e.g. kafka.Header(h)); sarama headers need their key converted to and
+// from []byte.
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+// KafkaHeaders is a MessageCarrier for the headers of a Kafka record
+//
+//	headers := aloig.KafkaHeaders{}
+//	aloig.InjectMessage(ctx, &headers)
+type KafkaHeaders []KafkaHeader
+
+// Get returns the value of the last header named key
+func (h *KafkaHeaders) Get(key string) string {
+	for i := len(*h) - 1; i >= 0; i-- {
+		if strings.EqualFold((*h)[i].Key, key) {
+			return string((*h)[i].Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the headers named key with one set to value
+func (h *KafkaHeaders) Set(key, value string) {
+	headers := (*h)[:0]
+	for _, header := range *h {
+		if !strings.EqualFold(header.Key, key) {
+			headers = append(headers, header)
+		}
+	}
+	*h = append(headers, KafkaHeader{Key: key, Value: []byte(value)})
+}
+
+// MessageAttributes is a MessageCarrier for string message attributes, such
+// as SQS and SNS message attributes of the "String" data type:
+//
+//	attributes := aloig.MessageAttributes{}
+//	aloig.InjectMessage(ctx, attributes)
+//	for name, value := range attributes {
+//		input.MessageAttributes[name] = types.MessageAttributeValue{
+//			DataType:    aws.String("String"),
+//			StringValue: aws.String(value),
+//		}
+//	}
+//
+// It uses three of the ten attributes SQS allows per message.
+type MessageAttributes map[string]string
+
+// Get returns the value of the attribute named key
+func (a MessageAttributes) Get(key string) string {
+	return a[key]
+}
+
+// Set sets the attribute named key
+func (a MessageAttributes) Set(key, value string) {
+	a[key] = value
+}
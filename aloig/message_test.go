@@ -0,0 +1,47 @@
+package aloig
+
+import (
+	"context"
+	"testing"
+)
+
+// TestMessagePropagation tests that the logging context survives a queue
+// hop through each carrier
+func TestMessagePropagation(t *testing.T) {
+	ctx := WithRequestID(WithTraceID(context.Background(), "trace-123"), "request-456")
+	ctx = ContextWithFields(ctx, map[string]interface{}{"order_id": "42"})
+
+	stale := KafkaHeaders{{Key: "x-trace-id", Value: []byte("stale")}, {Key: "content-type", Value: []byte("json")}}
+	carriers := map[string]MessageCarrier{
+		"kafka":      &KafkaHeaders{},
+		"kafka with": &stale,
+		"attributes": MessageAttributes{},
+	}
+
+	for name, carrier := range carriers {
+		t.Run(name, func(t *testing.T) {
+			InjectMessage(ctx, carrier)
+			consumed := ExtractMessage(context.Background(), carrier)
+
+			if GetTraceID(consumed) != "trace-123" || GetRequestID(consumed) != "request-456" {
+				t.Errorf("Expected the IDs to be restored, got %q and %q", GetTraceID(consumed), GetRequestID(consumed))
+			}
+			if orderID := ExtractContextFields(consumed)["order_id"]; orderID != "42" {
+				t.Errorf("Expected the context fields to be restored, got order_id=%v", orderID)
+			}
+		})
+	}
+
+	if len(stale) != 4 || stale.Get("Content-Type") != "json" {
+		t.Errorf("Expected the previous header to be replaced and the others kept, got %v", stale)
+	}
+}
+
+// TestExtractMessageWithoutContext tests that consumers of messages without
+// IDs still get a trace ID
+func TestExtractMessageWithoutContext(t *testing.T) {
+	ctx := ExtractMessage(context.Background(), MessageAttributes{})
+	if GetTraceID(ctx) == "" {
+		t.Error("Expected a generated trace ID")
+	}
+}
@@ -0,0 +1,255 @@
+// Package mock provides a testify mock of the aloig.Logger interface so
+// services can set expectations on their logging without writing their own.
+//
+//	logger := &mock.Logger{}
+//	logger.On("WithField", "order_id", "o-1").Return(logger)
+//	logger.On("Info", []interface{}{"order created"}).Return()
+package mock
+
+import (
+	"context"
+
+	"github.com/aloi-tech/aloig_go/aloig"
+	"github.com/sirupsen/logrus"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// Logger is a mock implementation of aloig.Logger. Variadic arguments are
+// recorded as a single slice argument.
+type Logger struct {
+	testifymock.Mock
+}
+
+var _ aloig.Logger = (*Logger)(nil)
+
+func (m *Logger) Debug(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Debugf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Info(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Infof(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Warn(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Warnf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Warning(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Warningf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Error(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Errorf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Fatal(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Fatalf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Panic(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Panicf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Print(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) DPanic(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) DPanicf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Printf(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) Println(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Trace(args ...interface{}) {
+	m.Called(args)
+}
+
+func (m *Logger) Tracef(format string, args ...interface{}) {
+	m.Called(format, args)
+}
+
+func (m *Logger) WithField(key string, value interface{}) aloig.Logger {
+	args := m.Called(key, value)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) WithFields(fields map[string]interface{}) aloig.Logger {
+	args := m.Called(fields)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) WithError(err error) aloig.Logger {
+	args := m.Called(err)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) WithContext(ctx context.Context) aloig.Logger {
+	args := m.Called(ctx)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) WithFingerprint(parts ...string) aloig.Logger {
+	args := m.Called(parts)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) Named(name string) aloig.Logger {
+	args := m.Called(name)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) WithLevel(level logrus.Level) aloig.Logger {
+	args := m.Called(level)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) IsLevelEnabled(level logrus.Level) bool {
+	args := m.Called(level)
+	return args.Bool(0)
+}
+
+func (m *Logger) WithCallerSkip(n int) aloig.Logger {
+	args := m.Called(n)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) With(fields ...aloig.Field) aloig.Logger {
+	args := m.Called(fields)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) WithFieldPrefix(prefix string) aloig.Logger {
+	args := m.Called(prefix)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) WithGroup(name string) aloig.Logger {
+	args := m.Called(name)
+	return args.Get(0).(aloig.Logger)
+}
+
+func (m *Logger) Log(ctx context.Context, level logrus.Level, msg string, fields ...aloig.Field) {
+	m.Called(ctx, level, msg, fields)
+}
+
+func (m *Logger) DebugContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) DebugfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) InfoContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) InfofContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) WarnContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) WarnfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) WarningContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) WarningfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) ErrorContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) ErrorfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) FatalContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) FatalfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) PanicContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) PanicfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) DPanicContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) DPanicfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) PrintContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) PrintfContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
+
+func (m *Logger) PrintlnContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) TraceContext(ctx context.Context, args ...interface{}) {
+	m.Called(ctx, args)
+}
+
+func (m *Logger) TracefContext(ctx context.Context, format string, args ...interface{}) {
+	m.Called(ctx, format, args)
+}
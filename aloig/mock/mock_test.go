@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aloi-tech/aloig_go/aloig"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// orderService is an example consumer of aloig.Logger
+type orderService struct {
+	logger aloig.Logger
+}
+
+func (s *orderService) create(ctx context.Context, id string) error {
+	err := errors.New("out of stock")
+	s.logger.WithField("order_id", id).ErrorContext(ctx, "order failed")
+	return err
+}
+
+// TestLoggerExpectations tests setting expectations on the mock
+func TestLoggerExpectations(t *testing.T) {
+	logger := &Logger{}
+	ctx := context.Background()
+
+	logger.On("WithField", "order_id", "o-1").Return(logger)
+	logger.On("ErrorContext", ctx, []interface{}{"order failed"}).Return()
+
+	service := &orderService{logger: logger}
+	if err := service.create(ctx, "o-1"); err == nil {
+		t.Error("Expected an error")
+	}
+
+	logger.AssertExpectations(t)
+}
+
+// TestLoggerVariadicArguments tests that variadic arguments are recorded as a slice
+func TestLoggerVariadicArguments(t *testing.T) {
+	logger := &Logger{}
+	logger.On("Infof", "processed %d items", testifymock.Anything).Return()
+	logger.On("WithFingerprint", []string{"a", "b"}).Return(logger)
+
+	logger.Infof("processed %d items", 3)
+	logger.WithFingerprint("a", "b")
+
+	logger.AssertCalled(t, "Infof", "processed %d items", []interface{}{3})
+	logger.AssertExpectations(t)
+}
@@ -0,0 +1,62 @@
+package aloig
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LoggerNameField is the field holding the name of a named logger
+const LoggerNameField = "logger"
+
+var (
+	namedMu     sync.RWMutex
+	namedLevels = make(map[string]logrus.Level)
+)
+
+// Named returns a child of the singleton logger whose entries carry the
+// logger field and whose level can be set with SetNamedLevel
+func Named(name string) Logger {
+	return GetLogger().Named(name)
+}
+
+// SetNamedLevel sets the level of the loggers with the given name, including
+// the ones already created. Named levels are shared by the whole process.
+func SetNamedLevel(name string, level logrus.Level) {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	namedLevels[name] = level
+}
+
+// NamedLevel returns the level set for the given name, if any
+func NamedLevel(name string) (logrus.Level, bool) {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+
+	level, ok := namedLevels[name]
+	return level, ok
+}
+
+// Named returns a child logger named after the parent name and the given one.
+// It writes to the logger of the parent, at the level set for its name or
+// else at the level of the parent.
+func (l *logrusLogger) Named(name string) Logger {
+	entry := l.newEntry()
+	if parent, ok := entry.Data[LoggerNameField].(string); ok && parent != "" {
+		name = parent + "." + name
+	}
+
+	child := l.child(entry.WithField(LoggerNameField, name))
+	child.levelName = name
+	return child
+}
+
+// WithLevel returns a child logger with its own level that keeps the fields,
+// context, output and hooks of the parent. It writes to the logger of the
+// parent; the levels of the names no longer apply to it.
+func (l *logrusLogger) WithLevel(level logrus.Level) Logger {
+	child := *l
+	child.levelOverride = &level
+	child.levelName = ""
+	return &child
+}
@@ -0,0 +1,146 @@
+package aloig
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newBufferLogger creates a logger writing text entries to a buffer
+func newBufferLogger(level logrus.Level) (*logrusLogger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logrusInstance := logrus.New()
+	logrusInstance.SetOutput(&buf)
+	logrusInstance.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	return newRootLogger(logrusInstance, level), &buf
+}
+
+// TestNamedLogger tests that named loggers carry their name and own level
+func TestNamedLogger(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.WarnLevel)
+	defer func() {
+		namedMu.Lock()
+		delete(namedLevels, "test-payments")
+		namedMu.Unlock()
+	}()
+
+	payments := logger.WithField("key", "value").Named("test-payments")
+	payments.Warn("payment processed")
+	if output := buf.String(); !strings.Contains(output, "logger=test-payments") || !strings.Contains(output, "key=value") {
+		t.Errorf("Expected logger name and parent fields, got: %s", output)
+	}
+
+	// Raise verbosity for payments only
+	buf.Reset()
+	SetNamedLevel("test-payments", logrus.InfoLevel)
+	payments.Info("payments info")
+	logger.Info("root info")
+	output := buf.String()
+	if !strings.Contains(output, "payments info") {
+		t.Errorf("Expected payments info entry, got: %s", output)
+	}
+	if strings.Contains(output, "root info") {
+		t.Errorf("Expected root info entry to be filtered, got: %s", output)
+	}
+
+	// Restrict payments only
+	buf.Reset()
+	SetNamedLevel("test-payments", logrus.ErrorLevel)
+	logger.Named("test-payments").Warn("payments warning")
+	logger.Warn("root warning")
+	output = buf.String()
+	if strings.Contains(output, "payments warning") || !strings.Contains(output, "root warning") {
+		t.Errorf("Expected only the root warning, got: %s", output)
+	}
+
+	if level, ok := NamedLevel("test-payments"); !ok || level != logrus.ErrorLevel {
+		t.Errorf("Expected named level error, got %s (%v)", level, ok)
+	}
+}
+
+// TestNamedLoggerNesting tests that nested names are joined with dots
+func TestNamedLoggerNesting(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	logger.Named("test-billing").Named("stripe").Info("nested")
+	if output := buf.String(); !strings.Contains(output, "logger=test-billing.stripe") {
+		t.Errorf("Expected nested logger name, got: %s", output)
+	}
+
+	if logger.Named("test-billing").(*logrusLogger).logger != logger.logger {
+		t.Error("Expected named loggers to write to the logger of the parent")
+	}
+}
+
+// TestNamedLoggerSharesParent tests that named loggers follow the output of
+// their parent and write to it without racing with the parent
+func TestNamedLoggerSharesParent(t *testing.T) {
+	logger, _ := newBufferLogger(logrus.WarnLevel)
+	defer func() {
+		namedMu.Lock()
+		delete(namedLevels, "test-shipping")
+		namedMu.Unlock()
+	}()
+	SetNamedLevel("test-shipping", logrus.InfoLevel)
+	shipping := logger.Named("test-shipping")
+
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			logger.Warn("parent entry")
+		}()
+		go func() {
+			defer wg.Done()
+			shipping.Info("shipping entry")
+		}()
+	}
+	wg.Wait()
+
+	output := buf.String()
+	if strings.Count(output, "parent entry") != 10 || strings.Count(output, "shipping entry") != 10 {
+		t.Errorf("Expected both loggers to write to the new output, got: %s", output)
+	}
+}
+
+// TestWithLevel tests that child loggers inherit fields but keep their own level
+func TestWithLevel(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.WarnLevel)
+
+	component := logger.WithField("component", "importer")
+	verbose := component.WithLevel(logrus.InfoLevel).WithField("worker", 1)
+	quiet := component.WithLevel(logrus.ErrorLevel)
+
+	verbose.Info("child info")
+	quiet.Warn("child warning")
+	component.Info("parent info")
+
+	output := buf.String()
+	if !strings.Contains(output, "child info") || !strings.Contains(output, "component=importer") || !strings.Contains(output, "worker=1") {
+		t.Errorf("Expected child info entry with inherited and own fields, got: %s", output)
+	}
+	if strings.Contains(output, "child warning") || strings.Contains(output, "parent info") {
+		t.Errorf("Expected entries below each logger level to be filtered, got: %s", output)
+	}
+	if logger.level() != logrus.WarnLevel {
+		t.Error("Expected parent level to be unchanged")
+	}
+	if verbose.(*logrusLogger).logger != logger.logger {
+		t.Error("Expected the child to write to the logger of the parent")
+	}
+
+	// The child follows the output set on the parent afterwards
+	var out bytes.Buffer
+	logger.logger.SetOutput(&out)
+	verbose.Info("moved")
+	if !strings.Contains(out.String(), "moved") {
+		t.Errorf("Expected the child to write to the new output, got: %s", out.String())
+	}
+}
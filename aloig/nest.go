@@ -0,0 +1,94 @@
+package aloig
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FieldSeparator separates the names of the nested objects in dotted field
+// keys (e.g. "db.query")
+const FieldSeparator = "."
+
+// nestFields moves the fields with dotted keys into nested objects, so
+// "db.query" and "db.duration_ms" are written as {"db": {"query": ...,
+// "duration_ms": ...}}. The nested maps of the fields are copied before being
+// extended, as they may be shared with other entries. A key whose parent is
+// a field holding something else than an object is left as it is.
+func nestFields(data logrus.Fields) {
+	var keys []string
+	for key := range data {
+		if strings.Contains(key, FieldSeparator) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	// Parents come first, so the result doesn't depend on the map order
+	sort.Strings(keys)
+
+	owned := make(map[string]map[string]interface{})
+	for _, key := range keys {
+		parts := strings.Split(key, FieldSeparator)
+		if hasEmptyPart(parts) {
+			continue
+		}
+		if parent, ok := nestedParent(data, parts[:len(parts)-1], owned); ok {
+			parent[parts[len(parts)-1]] = data[key]
+			delete(data, key)
+		}
+	}
+}
+
+// nestedParent returns the object at path, creating or copying the objects
+// along it. owned holds the objects already created or copied by their path.
+func nestedParent(data logrus.Fields, path []string, owned map[string]map[string]interface{}) (map[string]interface{}, bool) {
+	parent := map[string]interface{}(data)
+	for i, name := range path {
+		prefix := strings.Join(path[:i+1], FieldSeparator)
+		if object, ok := owned[prefix]; ok {
+			parent = object
+			continue
+		}
+
+		var object map[string]interface{}
+		switch value := parent[name].(type) {
+		case nil:
+			if _, ok := parent[name]; ok {
+				return nil, false
+			}
+			object = make(map[string]interface{})
+		case map[string]interface{}:
+			object = copyFields(value)
+		case logrus.Fields:
+			object = copyFields(value)
+		default:
+			return nil, false
+		}
+		parent[name] = object
+		owned[prefix] = object
+		parent = object
+	}
+	return parent, true
+}
+
+// hasEmptyPart reports whether a dotted key has an empty name
+func hasEmptyPart(parts []string) bool {
+	for _, part := range parts {
+		if part == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// copyFields returns a copy of the fields
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	object := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		object[k] = v
+	}
+	return object
+}
@@ -0,0 +1,66 @@
+package aloig
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestNestFields tests that dotted keys are moved into nested objects
+func TestNestFields(t *testing.T) {
+	shared := map[string]interface{}{"system": "postgres"}
+	data := logrus.Fields{
+		"db":          shared,
+		"db.query":    "SELECT 1",
+		"db.pool.max": 10,
+		"status":      "ok",
+		"status.code": 200,
+		"a..b":        1,
+	}
+
+	nestFields(data)
+
+	expected := logrus.Fields{
+		"db": map[string]interface{}{
+			"system": "postgres",
+			"query":  "SELECT 1",
+			"pool":   map[string]interface{}{"max": 10},
+		},
+		"status":      "ok",
+		"status.code": 200,
+		"a..b":        1,
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	if len(shared) != 1 {
+		t.Errorf("Expected the field map not to be modified, got %v", shared)
+	}
+}
+
+// TestNestedFieldsJSON tests that the JSON formatter writes groups and
+// dotted keys as nested objects when enabled
+func TestNestedFieldsJSON(t *testing.T) {
+	for _, nested := range []bool{true, false} {
+		logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel, NestedFields: nested})
+		buf := &bytes.Buffer{}
+		logger.(*logrusLogger).logger.SetOutput(buf)
+
+		logger.With(Group("db", String("query", "SELECT 1"))).WithField("db.rows", 3).Info("query")
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse the entry: %v", err)
+		}
+		db, _ := entry["db"].(map[string]interface{})
+		if db["query"] != "SELECT 1" {
+			t.Errorf("Expected the group to be nested, got %v", entry)
+		}
+		if _, ok := entry["db.rows"]; ok == nested || (db["rows"] == float64(3)) != nested {
+			t.Errorf("Expected db.rows nested to be %v, got %v", nested, entry)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build aloig_nodebug
+
+package aloig
+
+// debugStripped is set by the aloig_nodebug build tag, which compiles the
+// debug and trace logging calls to no-ops
+const debugStripped = true
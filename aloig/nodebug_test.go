@@ -0,0 +1,33 @@
+//go:build aloig_nodebug
+
+package aloig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestNoDebug tests that the aloig_nodebug build tag strips the debug and
+// trace entries whatever the level
+func TestNoDebug(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.TraceLevel)
+	ctx := context.Background()
+
+	logger.Debug("debug")
+	logger.Tracef("trace %d", 1)
+	logger.DebugContext(ctx, "debug context")
+	logger.Log(ctx, logrus.TraceLevel, "log trace")
+	if buf.Len() != 0 {
+		t.Errorf("Expected debug and trace entries to be stripped, got: %s", buf.String())
+	}
+	if logger.IsLevelEnabled(logrus.DebugLevel) {
+		t.Error("Expected the debug level to be disabled")
+	}
+
+	logger.Info("info")
+	if buf.Len() == 0 {
+		t.Error("Expected info entries to be written")
+	}
+}
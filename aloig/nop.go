@@ -0,0 +1,117 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// nopLogger is a Logger that discards every entry
+type nopLogger struct{}
+
+// NewNopLogger returns a Logger that discards every entry, for benchmarks,
+// optional dependencies and libraries that may run without a logger.
+// Panic and Fatal keep their control flow: they still panic and exit.
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(args ...interface{})                   {}
+func (nopLogger) Debugf(format string, args ...interface{})   {}
+func (nopLogger) Info(args ...interface{})                    {}
+func (nopLogger) Infof(format string, args ...interface{})    {}
+func (nopLogger) Warn(args ...interface{})                    {}
+func (nopLogger) Warnf(format string, args ...interface{})    {}
+func (nopLogger) Warning(args ...interface{})                 {}
+func (nopLogger) Warningf(format string, args ...interface{}) {}
+func (nopLogger) Error(args ...interface{})                   {}
+func (nopLogger) Errorf(format string, args ...interface{})   {}
+func (nopLogger) Print(args ...interface{})                   {}
+func (nopLogger) Printf(format string, args ...interface{})   {}
+func (nopLogger) Println(args ...interface{})                 {}
+func (nopLogger) Trace(args ...interface{})                   {}
+func (nopLogger) Tracef(format string, args ...interface{})   {}
+
+func (nopLogger) Fatal(args ...interface{}) {
+	os.Exit(1)
+}
+
+func (nopLogger) Fatalf(format string, args ...interface{}) {
+	os.Exit(1)
+}
+
+func (nopLogger) Panic(args ...interface{}) {
+	panic(fmt.Sprint(args...))
+}
+
+func (nopLogger) Panicf(format string, args ...interface{}) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+// DPanic only panics in development, which a nopLogger never is
+func (nopLogger) DPanic(args ...interface{})                 {}
+func (nopLogger) DPanicf(format string, args ...interface{}) {}
+
+func (l nopLogger) WithField(key string, value interface{}) Logger  { return l }
+func (l nopLogger) WithFields(fields map[string]interface{}) Logger { return l }
+func (l nopLogger) WithError(err error) Logger                      { return l }
+func (l nopLogger) WithContext(ctx context.Context) Logger          { return l }
+func (l nopLogger) WithFingerprint(parts ...string) Logger          { return l }
+func (l nopLogger) Named(name string) Logger                        { return l }
+func (l nopLogger) WithLevel(level logrus.Level) Logger             { return l }
+func (l nopLogger) WithCallerSkip(n int) Logger                     { return l }
+func (nopLogger) IsLevelEnabled(level logrus.Level) bool            { return false }
+func (l nopLogger) With(fields ...Field) Logger                     { return l }
+func (l nopLogger) WithFieldPrefix(prefix string) Logger            { return l }
+func (l nopLogger) WithGroup(name string) Logger                    { return l }
+
+func (nopLogger) Log(ctx context.Context, level logrus.Level, msg string, fields ...Field) {
+	switch level {
+	case logrus.PanicLevel:
+		panic(msg)
+	case logrus.FatalLevel:
+		os.Exit(1)
+	}
+}
+
+func (nopLogger) DebugContext(ctx context.Context, args ...interface{})                   {}
+func (nopLogger) DebugfContext(ctx context.Context, format string, args ...interface{})   {}
+func (nopLogger) InfoContext(ctx context.Context, args ...interface{})                    {}
+func (nopLogger) InfofContext(ctx context.Context, format string, args ...interface{})    {}
+func (nopLogger) WarnContext(ctx context.Context, args ...interface{})                    {}
+func (nopLogger) WarnfContext(ctx context.Context, format string, args ...interface{})    {}
+func (nopLogger) WarningContext(ctx context.Context, args ...interface{})                 {}
+func (nopLogger) WarningfContext(ctx context.Context, format string, args ...interface{}) {}
+func (nopLogger) ErrorContext(ctx context.Context, args ...interface{})                   {}
+func (nopLogger) ErrorfContext(ctx context.Context, format string, args ...interface{})   {}
+func (nopLogger) PrintContext(ctx context.Context, args ...interface{})                   {}
+func (nopLogger) PrintfContext(ctx context.Context, format string, args ...interface{})   {}
+func (nopLogger) PrintlnContext(ctx context.Context, args ...interface{})                 {}
+func (nopLogger) TraceContext(ctx context.Context, args ...interface{})                   {}
+func (nopLogger) TracefContext(ctx context.Context, format string, args ...interface{})   {}
+
+func (nopLogger) FatalContext(ctx context.Context, args ...interface{}) {
+	os.Exit(1)
+}
+
+func (nopLogger) FatalfContext(ctx context.Context, format string, args ...interface{}) {
+	os.Exit(1)
+}
+
+func (nopLogger) PanicContext(ctx context.Context, args ...interface{}) {
+	panic(fmt.Sprint(args...))
+}
+
+func (nopLogger) PanicfContext(ctx context.Context, format string, args ...interface{}) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+func (l nopLogger) DPanicContext(ctx context.Context, args ...interface{}) {
+	l.DPanic(args...)
+}
+
+func (l nopLogger) DPanicfContext(ctx context.Context, format string, args ...interface{}) {
+	l.DPanicf(format, args...)
+}
@@ -0,0 +1,38 @@
+package aloig
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestNopLogger tests that the nop logger accepts every call without output
+func TestNopLogger(t *testing.T) {
+	logger := NewNopLogger()
+	ctx := WithTraceID(context.Background(), "trace-123")
+
+	logger.Info("ignored")
+	logger.WithField("key", "value").WithError(errors.New("x")).Named("nop").ErrorContext(ctx, "ignored")
+
+	if logger.WithFields(map[string]interface{}{"a": 1}) != logger {
+		t.Error("Expected nop logger to return itself")
+	}
+
+	defer func() {
+		if r := recover(); r != "nop panic" {
+			t.Errorf("Expected Panic to keep panicking, got %v", r)
+		}
+	}()
+	logger.Panicf("nop %s", "panic")
+}
+
+// BenchmarkNopLogger measures the cost of logging through the nop logger
+func BenchmarkNopLogger(b *testing.B) {
+	logger := NewNopLogger()
+	ctx := WithTraceID(context.Background(), "trace-123")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.InfoContext(ctx, "message")
+	}
+}
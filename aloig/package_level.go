@@ -2,6 +2,8 @@ package aloig
 
 import (
 	"context"
+
+	"github.com/sirupsen/logrus"
 )
 
 // This file contains package-level convenience functions
@@ -9,11 +11,17 @@ import (
 
 // Debug logs a debug level message using the singleton logger
 func Debug(args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().Debug(args...)
 }
 
 // Debugf logs a formatted debug level message using the singleton logger
 func Debugf(format string, args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().Debugf(format, args...)
 }
 
@@ -81,6 +89,17 @@ func Panicf(format string, args ...interface{}) {
 	GetLogger().Panicf(format, args...)
 }
 
+// DPanic logs a message at panic level and panics in development, and logs
+// it at error level otherwise, using the singleton logger
+func DPanic(args ...interface{}) {
+	GetLogger().DPanic(args...)
+}
+
+// DPanicf logs a formatted message like DPanic using the singleton logger
+func DPanicf(format string, args ...interface{}) {
+	GetLogger().DPanicf(format, args...)
+}
+
 // Printf prints a formatted message using the singleton logger
 func Printf(format string, args ...interface{}) {
 	GetLogger().Printf(format, args...)
@@ -98,11 +117,17 @@ func Println(args ...interface{}) {
 
 // Trace logs a trace level message using the singleton logger
 func Trace(args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().Trace(args...)
 }
 
 // Tracef logs a formatted trace level message using the singleton logger
 func Tracef(format string, args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().Tracef(format, args...)
 }
 
@@ -126,13 +151,65 @@ func WithContext(ctx context.Context) Logger {
 	return GetLogger().WithContext(ctx)
 }
 
+// WithFingerprint returns a new log entry whose Sentry events are grouped
+// by the given parts instead of the message
+func WithFingerprint(parts ...string) Logger {
+	return GetLogger().WithFingerprint(parts...)
+}
+
+// WithFieldPrefix returns a child of the singleton logger prefixing the keys
+// of the fields added to it
+func WithFieldPrefix(prefix string) Logger {
+	return GetLogger().WithFieldPrefix(prefix)
+}
+
+// WithGroup returns a child of the singleton logger nesting the fields added
+// to it under the named object
+func WithGroup(name string) Logger {
+	return GetLogger().WithGroup(name)
+}
+
+// WithLevel returns a child of the singleton logger with its own level
+func WithLevel(level logrus.Level) Logger {
+	return GetLogger().WithLevel(level)
+}
+
+// IsLevelEnabled checks if the singleton logger writes the entries logged at
+// level
+func IsLevelEnabled(level logrus.Level) bool {
+	return GetLogger().IsLevelEnabled(level)
+}
+
+// WithCallerSkip returns a child of the singleton logger whose entries report
+// the caller n frames further up the stack
+func WithCallerSkip(n int) Logger {
+	return GetLogger().WithCallerSkip(n)
+}
+
+// With returns a logger with the given typed fields using the singleton logger
+func With(fields ...Field) Logger {
+	return GetLogger().With(fields...)
+}
+
+// Log logs msg at level with the context fields and the given typed fields
+// using the singleton logger
+func Log(ctx context.Context, level logrus.Level, msg string, fields ...Field) {
+	GetLogger().Log(ctx, level, msg, fields...)
+}
+
 // DebugContext logs a debug message using the given context
 func DebugContext(ctx context.Context, args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().DebugContext(ctx, args...)
 }
 
 // DebugfContext logs a formatted debug message using the given context
 func DebugfContext(ctx context.Context, format string, args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().DebugfContext(ctx, format, args...)
 }
 
@@ -196,6 +273,16 @@ func PanicfContext(ctx context.Context, format string, args ...interface{}) {
 	GetLogger().PanicfContext(ctx, format, args...)
 }
 
+// DPanicContext logs a message like DPanic with context information
+func DPanicContext(ctx context.Context, args ...interface{}) {
+	GetLogger().DPanicContext(ctx, args...)
+}
+
+// DPanicfContext logs a formatted message like DPanic with context information
+func DPanicfContext(ctx context.Context, format string, args ...interface{}) {
+	GetLogger().DPanicfContext(ctx, format, args...)
+}
+
 // PrintContext prints a message using the given context
 func PrintContext(ctx context.Context, args ...interface{}) {
 	GetLogger().PrintContext(ctx, args...)
@@ -213,10 +300,32 @@ func PrintlnContext(ctx context.Context, args ...interface{}) {
 
 // TraceContext logs a trace message using the given context
 func TraceContext(ctx context.Context, args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().TraceContext(ctx, args...)
 }
 
 // TracefContext logs a formatted trace message using the given context
 func TracefContext(ctx context.Context, format string, args ...interface{}) {
+	if debugStripped {
+		return
+	}
 	GetLogger().TracefContext(ctx, format, args...)
 }
+
+// SetLevel sets the level of the singleton logger at runtime. Named and
+// child loggers keep their own level.
+func SetLevel(level logrus.Level) {
+	if l, ok := GetLogger().(*logrusLogger); ok {
+		l.setLevel(level)
+	}
+}
+
+// GetLevel returns the level of the singleton logger
+func GetLevel() logrus.Level {
+	if l, ok := GetLogger().(*logrusLogger); ok {
+		return l.level()
+	}
+	return logrus.InfoLevel
+}
@@ -0,0 +1,71 @@
+package aloig
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxPooledBufferSize is the capacity above which buffers are dropped
+// instead of pooled, so a few huge entries don't pin memory
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool reuses the buffers of the hooks building their own output
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to the pool; it must not be used afterwards
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// keysPool reuses the slices used to sort field names
+var keysPool = sync.Pool{
+	New: func() interface{} {
+		keys := make([]string, 0, 16)
+		return &keys
+	},
+}
+
+// jsonFormatterPool reuses the JSON formatter copies, and their field maps,
+// made to format custom levels and epoch timestamps
+var jsonFormatterPool = sync.Pool{
+	New: func() interface{} {
+		return &logrus.JSONFormatter{FieldMap: make(logrus.FieldMap, 4)}
+	},
+}
+
+// getJSONFormatter returns a pooled copy of formatter whose FieldMap can be
+// modified
+func getJSONFormatter(formatter *logrus.JSONFormatter) *logrus.JSONFormatter {
+	copied := jsonFormatterPool.Get().(*logrus.JSONFormatter)
+	fieldMap := copied.FieldMap
+	*copied = *formatter
+	for k, v := range formatter.FieldMap {
+		fieldMap[k] = v
+	}
+	copied.FieldMap = fieldMap
+	return copied
+}
+
+// putJSONFormatter returns a copy made by getJSONFormatter to the pool
+func putJSONFormatter(formatter *logrus.JSONFormatter) {
+	for k := range formatter.FieldMap {
+		delete(formatter.FieldMap, k)
+	}
+	jsonFormatterPool.Put(formatter)
+}
@@ -0,0 +1,101 @@
+package aloig
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// benchmarkLogger is the logger of the benchmark entries
+var benchmarkLogger = &logrus.Logger{Out: io.Discard, Level: logrus.InfoLevel}
+
+// newBenchmarkEntry returns an entry with the fields of a typical request log
+func newBenchmarkEntry() *logrus.Entry {
+	return &logrus.Entry{
+		Logger:  benchmarkLogger,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   logrus.InfoLevel,
+		Message: "request handled",
+		Caller:  &runtime.Frame{File: "/src/handler.go", Line: 42, Function: "main.handle"},
+		Data: logrus.Fields{
+			"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+			"method":   "GET",
+			"path":     "/orders/42",
+			"status":   200,
+			"error":    errors.New("not found"),
+		},
+	}
+}
+
+// TestPooledJSONFormatters tests that the pooled formatter copies don't leak
+// into the configured formatter or the following entries
+func TestPooledJSONFormatters(t *testing.T) {
+	base := &logrus.JSONFormatter{TimestampFormat: TimestampEpochMillis}
+	formatter := &CallerJSONFormatter{JSONFormatter: base}
+
+	for i := 0; i < 3; i++ {
+		entry := newBenchmarkEntry()
+		entry.Data[LevelNameField] = NoticeLevel.Name
+		output, err := formatter.Format(entry)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.Contains(string(output), `"level":"notice"`) || !strings.Contains(string(output), `"time":1704164645000`) {
+			t.Errorf("Expected the custom level and epoch time, got: %s", output)
+		}
+
+		output, err = formatter.Format(newBenchmarkEntry())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.Contains(string(output), `"level":"info"`) || strings.Contains(string(output), BaseLevelField) {
+			t.Errorf("Expected a standard level, got: %s", output)
+		}
+	}
+	if base.FieldMap != nil || base.DisableTimestamp {
+		t.Errorf("Expected the configured formatter to be unchanged, got: %+v", base)
+	}
+}
+
+// benchmarkFormatter measures the cost of formatting an entry
+func benchmarkFormatter(b *testing.B, formatter logrus.Formatter, prepare func(*logrus.Entry)) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry := newBenchmarkEntry()
+		if prepare != nil {
+			prepare(entry)
+		}
+		if _, err := formatter.Format(entry); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCallerJSONFormatter measures the cost of the production formatter
+func BenchmarkCallerJSONFormatter(b *testing.B) {
+	benchmarkFormatter(b, &CallerJSONFormatter{JSONFormatter: &logrus.JSONFormatter{}}, nil)
+}
+
+// BenchmarkCallerJSONFormatterCustomLevel measures the cost of formatting
+// entries logged at a custom level with epoch timestamps
+func BenchmarkCallerJSONFormatterCustomLevel(b *testing.B) {
+	formatter := &CallerJSONFormatter{JSONFormatter: &logrus.JSONFormatter{TimestampFormat: TimestampEpochMillis}}
+	benchmarkFormatter(b, formatter, func(entry *logrus.Entry) {
+		entry.Data[LevelNameField] = NoticeLevel.Name
+	})
+}
+
+// BenchmarkSyslogFormat measures the cost of building syslog messages
+func BenchmarkSyslogFormat(b *testing.B) {
+	hook := &SyslogHook{network: "tcp", hostname: "host", appName: "app", facility: SyslogFacilityUser}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := hook.format(newBenchmarkEntry())
+		putBuffer(buf)
+	}
+}
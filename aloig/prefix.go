@@ -0,0 +1,38 @@
+package aloig
+
+import "github.com/sirupsen/logrus"
+
+// unprefixedFields are the fields read by aloig and the Sentry hook, whose
+// keys are kept under a field prefix
+var unprefixedFields = map[string]struct{}{
+	logrus.ErrorKey:        {},
+	SentryFieldRequest:     {},
+	SentryFieldUser:        {},
+	SentryFieldTransaction: {},
+	SentryFieldFingerprint: {},
+}
+
+// WithFieldPrefix returns a child logger prefixing the keys of the fields
+// added to it, including by its children, so the fields of a component can't
+// collide with the standard fields (env, hostname, ...). The prefixes of
+// nested children are concatenated. The fields of the context, the error and
+// the Sentry fields keep their keys.
+//
+//	payments := logger.WithFieldPrefix("payments.")
+//	payments.WithField("amount", 42).Info("charged") // payments.amount=42
+func (l *logrusLogger) WithFieldPrefix(prefix string) Logger {
+	child := *l
+	child.prefix += prefix
+	return &child
+}
+
+// fieldKey returns the key of a field added to the logger
+func (l *logrusLogger) fieldKey(key string) string {
+	if l.prefix == "" {
+		return key
+	}
+	if _, ok := unprefixedFields[key]; ok {
+		return key
+	}
+	return l.prefix + key
+}
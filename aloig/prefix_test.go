@@ -0,0 +1,37 @@
+package aloig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestWithFieldPrefix tests that the fields of a prefixed logger and of its
+// children are prefixed, except the error and the Sentry fields
+func TestWithFieldPrefix(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	payments := logger.WithFieldPrefix("payments.")
+
+	payments.WithField("env", "sandbox").
+		WithFields(map[string]interface{}{"amount": 42}).
+		With(String("currency", "EUR")).
+		WithFieldPrefix("stripe.").WithField("charge_id", "ch_1").
+		WithError(errors.New("declined")).
+		WithFingerprint("payments").
+		Info("charge failed")
+
+	output := buf.String()
+	for _, expected := range []string{"payments.env=sandbox", "payments.amount=42", "payments.currency=EUR", "payments.stripe.charge_id=ch_1", "error=declined", "fingerprint="} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s, got: %s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	logger.WithField("env", "dev").Info("unprefixed")
+	if !strings.Contains(buf.String(), " env=dev") {
+		t.Errorf("Expected the parent fields not to be prefixed, got: %s", buf.String())
+	}
+}
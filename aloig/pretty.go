@@ -0,0 +1,213 @@
+package aloig
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPrettyTimestampFormat is the timestamp format of the PrettyFormatter
+const DefaultPrettyTimestampFormat = "15:04:05.000"
+
+// prettyMessageWidth is the width the message is padded to so that the
+// fields of consecutive entries line up
+const prettyMessageWidth = 44
+
+// ANSI color codes used by the PrettyFormatter
+const (
+	colorRed     = 31
+	colorYellow  = 33
+	colorBlue    = 36
+	colorGray    = 37
+	colorMagenta = 35
+)
+
+// prettyMultilineFields are rendered on their own indented lines after the
+// entry instead of inline
+var prettyMultilineFields = []string{logrus.ErrorKey, ErrorChainField, StackTraceField}
+
+// PrettyFormatter renders entries for humans reading a terminal: colored
+// level, inline trace ID, short caller, aligned fields, and errors, error
+// chains and stack traces on their own lines. NewLogger uses it in the dev
+// environment unless Config.PlainDevFormatter is set.
+type PrettyFormatter struct {
+	// DisableColors disables the ANSI colors
+	DisableColors bool
+
+	// TimestampFormat is the layout of the entry time or
+	// TimestampEpochMillis (empty uses DefaultPrettyTimestampFormat)
+	TimestampFormat string
+}
+
+// Format renders the entry
+func (f *PrettyFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := entry.Buffer
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	timestampFormat := f.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = DefaultPrettyTimestampFormat
+	}
+	color := levelColor(entry.Level)
+
+	if !entry.Time.IsZero() {
+		f.colored(b, colorGray, formatTimestamp(entry.Time, timestampFormat))
+		b.WriteByte(' ')
+	}
+
+	label := strings.ToUpper(entry.Level.String())
+	if entry.Level == logrus.WarnLevel {
+		label = "WARN"
+	}
+	if name, ok := entry.Data[LevelNameField].(string); ok {
+		label = strings.ToUpper(name)
+	}
+	f.colored(b, color, fmt.Sprintf("%-5s", label))
+	b.WriteByte(' ')
+
+	if traceID, ok := entry.Data[string(TraceIDKey)]; ok {
+		f.colored(b, colorGray, fmt.Sprintf("[%v]", traceID))
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(fmt.Sprintf("%-*s", prettyMessageWidth, entry.Message))
+
+	if caller := prettyCaller(entry); caller != "" {
+		b.WriteByte(' ')
+		f.colored(b, colorGray, caller)
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		if !isPrettyInlineField(key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b.WriteByte(' ')
+		f.colored(b, color, key)
+		b.WriteByte('=')
+		b.WriteString(prettyValue(entry.Data[key]))
+	}
+	b.WriteByte('\n')
+
+	for _, key := range prettyMultilineFields {
+		value, ok := entry.Data[key]
+		if !ok {
+			continue
+		}
+		b.WriteString("    ")
+		f.colored(b, color, key)
+		b.WriteString(":")
+		for _, line := range prettyLines(value) {
+			b.WriteString("\n        ")
+			b.WriteString(line)
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes(), nil
+}
+
+// colored writes s to b in the given color unless colors are disabled
+func (f *PrettyFormatter) colored(b *bytes.Buffer, color int, s string) {
+	if f.DisableColors {
+		b.WriteString(s)
+		return
+	}
+	fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m", color, s)
+}
+
+// levelColor returns the color of the level
+func levelColor(level logrus.Level) int {
+	switch level {
+	case logrus.TraceLevel, logrus.DebugLevel:
+		return colorGray
+	case logrus.WarnLevel:
+		return colorYellow
+	case logrus.ErrorLevel:
+		return colorRed
+	case logrus.FatalLevel, logrus.PanicLevel:
+		return colorMagenta
+	default:
+		return colorBlue
+	}
+}
+
+// isPrettyInlineField checks if the field is rendered among the inline
+// fields rather than in the entry prefix or on its own lines
+func isPrettyInlineField(key string) bool {
+	switch key {
+	case LevelNameField, string(TraceIDKey), "caller", "function", "full_function", "file", "line":
+		return false
+	}
+	for _, multiline := range prettyMultilineFields {
+		if key == multiline {
+			return false
+		}
+	}
+	return true
+}
+
+// prettyCaller returns the caller as file:line, from the entry caller or the
+// caller field of entries parsed from the JSON format
+func prettyCaller(entry *logrus.Entry) string {
+	if entry.Caller != nil {
+		return fmt.Sprintf("%s:%d", filepath.Base(entry.Caller.File), entry.Caller.Line)
+	}
+	if caller, ok := entry.Data["caller"].(string); ok {
+		return caller
+	}
+	return ""
+}
+
+// prettyValue renders an inline field value, quoting it when needed
+func prettyValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// prettyLines splits a multi-line field value into the lines to render
+func prettyLines(value interface{}) []string {
+	switch v := value.(type) {
+	case []ErrorChainLink:
+		lines := make([]string, len(v))
+		for i, link := range v {
+			lines[i] = fmt.Sprintf("%s: %s", link.Type, link.Message)
+		}
+		return lines
+	case []interface{}:
+		lines := make([]string, len(v))
+		for i, item := range v {
+			if link, ok := item.(map[string]interface{}); ok {
+				lines[i] = fmt.Sprintf("%v: %v", link["type"], link["message"])
+			} else {
+				lines[i] = fmt.Sprint(item)
+			}
+		}
+		return lines
+	case error:
+		return strings.Split(v.Error(), "\n")
+	default:
+		return strings.Split(strings.TrimRight(fmt.Sprint(v), "\n"), "\n")
+	}
+}
@@ -0,0 +1,76 @@
+package aloig
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestPrettyFormatter tests the layout of pretty entries
+func TestPrettyFormatter(t *testing.T) {
+	formatter := &PrettyFormatter{DisableColors: true}
+	entry := &logrus.Entry{
+		Time:    time.Date(2024, 1, 2, 15, 4, 5, 6000000, time.UTC),
+		Level:   logrus.WarnLevel,
+		Message: "slow query",
+		Caller:  &runtime.Frame{File: "/src/service/db.go", Line: 42},
+		Data: logrus.Fields{
+			string(TraceIDKey): "trace-pretty",
+			"table":            "orders",
+			"query":            "SELECT 1",
+		},
+	}
+
+	out, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := fmt.Sprintf("15:04:05.006 WARN  [trace-pretty] %-44s db.go:42 query=\"SELECT 1\" table=orders\n", "slow query")
+	if string(out) != expected {
+		t.Errorf("Unexpected output:\n%q\nexpected:\n%q", out, expected)
+	}
+}
+
+// TestPrettyFormatterMultiline tests that errors, chains and stacks get their own lines
+func TestPrettyFormatterMultiline(t *testing.T) {
+	formatter := &PrettyFormatter{DisableColors: true}
+	entry := &logrus.Entry{
+		Level:   logrus.ErrorLevel,
+		Message: "request failed",
+		Data: logrus.Fields{
+			logrus.ErrorKey: errors.New("boom"),
+			ErrorChainField: []ErrorChainLink{{Type: "*fmt.wrapError", Message: "load: boom"}, {Type: "*errors.errorString", Message: "boom"}},
+			"stack_trace":   "main.main()\n\t/src/main.go:10",
+			LevelNameField:  "critical",
+		},
+	}
+
+	out, _ := formatter.Format(entry)
+	output := string(out)
+	for _, expected := range []string{
+		"CRITICAL request failed",
+		"    error:\n        boom\n",
+		"    error_chain:\n        *fmt.wrapError: load: boom\n        *errors.errorString: boom\n",
+		"    stack_trace:\n        main.main()\n        \t/src/main.go:10\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "level_name") {
+		t.Errorf("Expected the level name in the prefix only, got:\n%s", output)
+	}
+}
+
+// TestPrettyFormatterColors tests that the level is colored
+func TestPrettyFormatterColors(t *testing.T) {
+	out, _ := (&PrettyFormatter{}).Format(&logrus.Entry{Level: logrus.ErrorLevel, Message: "boom", Data: logrus.Fields{}})
+	if !strings.Contains(string(out), "\x1b[31mERROR\x1b[0m") {
+		t.Errorf("Expected a red level, got: %q", out)
+	}
+}
@@ -0,0 +1,51 @@
+package aloig
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields added by the ProcessIDHook
+const (
+	PIDField         = "pid"
+	GoroutineIDField = "goroutine_id"
+)
+
+// ProcessIDHook adds the process ID and the ID of the goroutine logging to
+// every entry, to tell apart the interleaved entries of concurrent
+// goroutines when debugging a single process. Reading the goroutine ID
+// formats the header of the goroutine's stack, so the hook is meant for
+// debugging sessions rather than hot paths. NewLogger adds it when
+// Config.ProcessIDs is set.
+type ProcessIDHook struct{}
+
+// Levels returns the levels to which the hook will be applied
+func (ProcessIDHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the process and goroutine IDs
+func (ProcessIDHook) Fire(entry *logrus.Entry) error {
+	entry.Data[PIDField] = os.Getpid()
+	if id, ok := goroutineID(); ok {
+		entry.Data[GoroutineIDField] = id
+	}
+	return nil
+}
+
+// goroutineID returns the ID of the calling goroutine, read from the header
+// of its stack trace ("goroutine 42 [running]:")
+func goroutineID() (uint64, bool) {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if end := bytes.IndexByte(header, ' '); end > 0 {
+		header = header[:end]
+	}
+	id, err := strconv.ParseUint(string(header), 10, 64)
+	return id, err == nil
+}
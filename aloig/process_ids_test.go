@@ -0,0 +1,48 @@
+package aloig
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestGoroutineID tests that goroutines get distinct IDs
+func TestGoroutineID(t *testing.T) {
+	id, ok := goroutineID()
+	if !ok || id == 0 {
+		t.Fatalf("Expected the goroutine ID, got %d", id)
+	}
+
+	var other uint64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		other, _ = goroutineID()
+	}()
+	wg.Wait()
+	if other == 0 || other == id {
+		t.Errorf("Expected another ID for another goroutine, got %d and %d", id, other)
+	}
+}
+
+// TestNewLoggerProcessIDs tests that the IDs are added when enabled
+func TestNewLoggerProcessIDs(t *testing.T) {
+	logger := NewLogger(Config{Environment: "dev", Level: logrus.InfoLevel, ProcessIDs: true}).(*logrusLogger)
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+	logger.logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+	logger.Info("reserved")
+
+	id, _ := goroutineID()
+	output := buf.String()
+	if !strings.Contains(output, PIDField+"="+strconv.Itoa(os.Getpid())) || !strings.Contains(output, GoroutineIDField+"="+strconv.FormatUint(id, 10)) {
+		t.Errorf("Expected the process and goroutine IDs, got: %s", output)
+	}
+}
@@ -0,0 +1,104 @@
+package aloig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPseudonymizedFields are the fields pseudonymized when no fields are
+// configured
+var DefaultPseudonymizedFields = []string{string(UserIDKey), string(SessionIDKey)}
+
+// pseudonymSize is the number of bytes of the HMAC kept in pseudonyms
+const pseudonymSize = 16
+
+// PseudonymizeHook replaces the values of the given fields with their
+// HMAC-SHA256 under a secret key, so the entries of a user or session can
+// still be correlated without the logs storing the raw identifiers. The
+// pseudonyms are the first 16 bytes of the HMAC in hex. Field names are
+// matched case-insensitively.
+type PseudonymizeHook struct {
+	fields map[string]struct{}
+	hashes sync.Pool
+}
+
+// NewPseudonymizeHook creates a hook pseudonymizing the given fields with key.
+// No fields pseudonymizes DefaultPseudonymizedFields.
+func NewPseudonymizeHook(key []byte, fields []string) *PseudonymizeHook {
+	if len(fields) == 0 {
+		fields = DefaultPseudonymizedFields
+	}
+	key = append([]byte(nil), key...)
+	hook := &PseudonymizeHook{fields: make(map[string]struct{}, len(fields))}
+	hook.hashes.New = func() interface{} { return hmac.New(sha256.New, key) }
+	for _, field := range fields {
+		hook.fields[strings.ToLower(field)] = struct{}{}
+	}
+	return hook
+}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *PseudonymizeHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire pseudonymizes the fields of the entry
+func (hook *PseudonymizeHook) Fire(entry *logrus.Entry) error {
+	for key, value := range entry.Data {
+		if _, ok := hook.fields[strings.ToLower(key)]; !ok || value == nil {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			s = fmt.Sprint(value)
+		}
+		if s != "" {
+			entry.Data[key] = hook.Pseudonymize(s)
+		}
+	}
+	return nil
+}
+
+// Pseudonymize returns the pseudonym of value. The same value always gets
+// the same pseudonym under the same key.
+func (hook *PseudonymizeHook) Pseudonymize(value string) string {
+	mac := hook.hashes.Get().(hash.Hash)
+	defer hook.hashes.Put(mac)
+	mac.Reset()
+	mac.Write([]byte(value))
+	var sum [sha256.Size]byte
+	return hex.EncodeToString(mac.Sum(sum[:0])[:pseudonymSize])
+}
+
+var (
+	sentryPseudonymizerMu sync.RWMutex
+	sentryPseudonymizer   *PseudonymizeHook
+)
+
+// setSentryPseudonymizer sets the hook pseudonymizing the user IDs of the
+// errors captured with CaptureError; nil keeps them as they are
+func setSentryPseudonymizer(hook *PseudonymizeHook) {
+	sentryPseudonymizerMu.Lock()
+	defer sentryPseudonymizerMu.Unlock()
+	sentryPseudonymizer = hook
+}
+
+// sentryUserID returns the user ID reported to Sentry for userID
+func sentryUserID(userID string) string {
+	sentryPseudonymizerMu.RLock()
+	defer sentryPseudonymizerMu.RUnlock()
+	if sentryPseudonymizer == nil || userID == "" {
+		return userID
+	}
+	if _, ok := sentryPseudonymizer.fields[string(UserIDKey)]; !ok {
+		return userID
+	}
+	return sentryPseudonymizer.Pseudonymize(userID)
+}
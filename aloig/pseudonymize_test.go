@@ -0,0 +1,51 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestPseudonymizeHook tests that the user and session IDs are replaced with
+// stable pseudonyms
+func TestPseudonymizeHook(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	hook := NewPseudonymizeHook([]byte("secret"), nil)
+	logger.logger.AddHook(hook)
+
+	ctx := WithSessionID(WithUserID(context.Background(), "user-789"), "session-1")
+	logger.InfoContext(ctx, "first")
+	logger.WithField("user_id", "user-789").Info("second")
+
+	output := buf.String()
+	if strings.Contains(output, "user-789") || strings.Contains(output, "session-1") {
+		t.Errorf("Expected no raw identifier, got: %s", output)
+	}
+	pseudonym := hook.Pseudonymize("user-789")
+	if len(pseudonym) != 32 || strings.Count(output, "user_id="+pseudonym) != 2 {
+		t.Errorf("Expected both entries to carry the pseudonym %s, got: %s", pseudonym, output)
+	}
+	if !strings.Contains(output, "session_id="+hook.Pseudonymize("session-1")) {
+		t.Errorf("Expected the session ID to be pseudonymized, got: %s", output)
+	}
+
+	if other := NewPseudonymizeHook([]byte("other"), nil).Pseudonymize("user-789"); other == pseudonym {
+		t.Error("Expected the pseudonym to depend on the key")
+	}
+}
+
+// TestPseudonymizeHookFields tests that only the configured fields are
+// pseudonymized
+func TestPseudonymizeHookFields(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(NewPseudonymizeHook([]byte("secret"), []string{"Email"}))
+
+	logger.WithFields(map[string]interface{}{"email": "bob@example.com", "user_id": "user-789"}).Info("signup")
+
+	output := buf.String()
+	if strings.Contains(output, "bob@example.com") || !strings.Contains(output, "user_id=user-789") {
+		t.Errorf("Expected only the email to be pseudonymized, got: %s", output)
+	}
+}
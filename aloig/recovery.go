@@ -0,0 +1,83 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// RecoveryMiddleware returns a middleware that recovers the panics of the
+// handlers, logs the panic value with the cleaned stack trace and the context
+// fields, reports it to Sentry with the request attached and responds with a
+// 500 if nothing was written yet. A nil logger writes to the singleton
+// logger. Wrap it with HTTPMiddleware so the entries carry the request IDs.
+// http.ErrAbortHandler panics are propagated to the server untouched.
+func RecoveryMiddleware(logger Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				stack := cleanStackTrace(debug.Stack(), "aloig.RecoveryMiddleware", "runtime/panic.go", "panic(")
+
+				fields := map[string]interface{}{
+					"panic":         fmt.Sprint(value),
+					StackTraceField: stack,
+					"method":        r.Method,
+					"path":          r.URL.Path,
+				}
+				if eventID := recoverToSentry(r.Context(), r, value); eventID != "" {
+					fields[SentryEventIDField] = eventID
+				}
+
+				l := logger
+				if l == nil {
+					l = GetLogger()
+				}
+				l.WithFields(fields).ErrorContext(r.Context(), "panic recovered")
+
+				if !rw.wroteHeader {
+					http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// recoverToSentry reports a recovered panic to the hub of the context, or
+// the current hub, with the context fields and the request, when not nil,
+// attached. It returns the event ID, or an empty string if the event was not
+// sent.
+func recoverToSentry(ctx context.Context, r *http.Request, value interface{}) string {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	if hub == nil || hub.Client() == nil {
+		return ""
+	}
+
+	local := scopedHub(hub, ctx)
+	if r != nil {
+		local.Scope().SetRequest(r)
+	}
+
+	id := local.RecoverWithContext(ctx, value)
+	if id == nil {
+		return ""
+	}
+	return string(*id)
+}
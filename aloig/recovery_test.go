@@ -0,0 +1,69 @@
+package aloig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// TestRecoveryMiddleware tests that panics are logged, reported and answered with a 500
+func TestRecoveryMiddleware(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+
+	handler := HTTPMiddleware(HTTPOptions{Logger: logger})(RecoveryMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map write")
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(TraceIDHeader, "trace-panic")
+	req = req.WithContext(sentry.SetHubOnContext(req.Context(), hub))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 Sentry event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Request == nil || !strings.HasSuffix(event.Request.URL, "/orders") {
+		t.Errorf("Expected the request on the event, got %+v", event.Request)
+	}
+	if event.Tags["trace_id"] != "trace-panic" {
+		t.Errorf("Expected the trace_id tag, got %v", event.Tags)
+	}
+
+	output := buf.String()
+	for _, expected := range []string{`msg="panic recovered"`, `panic="nil map write"`, "stack_trace=", "trace_id=trace-panic", SentryEventIDField + "=" + string(event.EventID), "status=500"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "runtime/panic.go") {
+		t.Errorf("Expected runtime panic frames to be removed, got: %s", output)
+	}
+}
+
+// TestRecoveryMiddlewareAfterWrite tests that a started response is not overwritten
+func TestRecoveryMiddlewareAfterWrite(t *testing.T) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+
+	handler := RecoveryMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late failure")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,38 @@
+package aloig
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactHook replaces the values of the given fields with RedactedValue, so
+// secrets logged by mistake never reach the output. Field names are matched
+// case-insensitively.
+type RedactHook struct {
+	fields map[string]struct{}
+}
+
+// NewRedactHook creates a hook redacting the given fields
+func NewRedactHook(fields []string) *RedactHook {
+	hook := &RedactHook{fields: make(map[string]struct{}, len(fields))}
+	for _, field := range fields {
+		hook.fields[strings.ToLower(field)] = struct{}{}
+	}
+	return hook
+}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redacts the fields of the entry
+func (hook *RedactHook) Fire(entry *logrus.Entry) error {
+	for key := range entry.Data {
+		if _, ok := hook.fields[strings.ToLower(key)]; ok {
+			entry.Data[key] = RedactedValue
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestRedactHook tests that configured fields are redacted case-insensitively
+func TestRedactHook(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(NewRedactHook([]string{"password"}))
+
+	logger.WithFields(map[string]interface{}{"Password": "hunter2", "user": "bob"}).Info("login")
+
+	output := buf.String()
+	if strings.Contains(output, "hunter2") || !strings.Contains(output, `Password="[REDACTED]"`) || !strings.Contains(output, "user=bob") {
+		t.Errorf("Expected the password to be redacted, got: %s", output)
+	}
+}
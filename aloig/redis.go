@@ -0,0 +1,98 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// RedisCmd is the part of a go-redis command (redis.Cmder) read by
+// RedisLogger, so aloig doesn't depend on the client
+type RedisCmd interface {
+	Name() string
+	Args() []interface{}
+	Err() error
+}
+
+// redisNil is the message of go-redis' redis.Nil, returned for missing keys
+const redisNil = "redis: nil"
+
+// RedisLogger logs the Redis commands exceeding a latency threshold and the
+// ones that failed, with the trace fields of the command context. It is meant
+// to be called from a go-redis hook:
+//
+//	func (h hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+//		return func(ctx context.Context, cmd redis.Cmder) error {
+//			start := time.Now()
+//			err := next(ctx, cmd)
+//			h.logger.LogCommand(ctx, cmd, time.Since(start))
+//			return err
+//		}
+//	}
+type RedisLogger struct {
+	// Logger receives the entries. The singleton logger is used when nil.
+	Logger Logger
+
+	// SlowThreshold logs the commands taking longer at warn level.
+	// Zero disables slow command warnings.
+	SlowThreshold time.Duration
+}
+
+// LogCommand logs a command that failed or was slow. Missing keys
+// (redis.Nil) are not considered failures.
+func (l *RedisLogger) LogCommand(ctx context.Context, cmd RedisCmd, duration time.Duration) {
+	l.log(ctx, []RedisCmd{cmd}, duration)
+}
+
+// LogPipeline logs a pipeline in which a command failed or that was slow
+func (l *RedisLogger) LogPipeline(ctx context.Context, cmds []RedisCmd, duration time.Duration) {
+	l.log(ctx, cmds, duration)
+}
+
+// log logs the commands at error level if one failed, at warn level if they
+// were slow and not at all otherwise
+func (l *RedisLogger) log(ctx context.Context, cmds []RedisCmd, duration time.Duration) {
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && cmdErr.Error() != redisNil {
+			err = cmdErr
+			break
+		}
+	}
+
+	slow := l.SlowThreshold > 0 && duration > l.SlowThreshold
+	if err == nil && !slow {
+		return
+	}
+
+	logger := l.Logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+
+	fields := map[string]interface{}{
+		"redis_cmd":   redisCommandNames(cmds),
+		"duration_ms": float64(duration) / float64(time.Millisecond),
+	}
+	if len(cmds) == 1 {
+		fields["redis_args"] = len(cmds[0].Args())
+	} else {
+		fields["redis_pipeline"] = len(cmds)
+	}
+
+	entry := logger.WithFields(fields)
+	if err != nil {
+		entry.WithError(err).ErrorContext(ctx, "redis command failed")
+		return
+	}
+	entry.WarnContext(ctx, "slow redis command")
+}
+
+// redisCommandNames joins the command names, without their arguments
+func redisCommandNames(cmds []RedisCmd) string {
+	names := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		names[i] = cmd.Name()
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,59 @@
+package aloig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeRedisCmd is a command with the same methods as redis.Cmder
+type fakeRedisCmd struct {
+	name string
+	args []interface{}
+	err  error
+}
+
+func (c fakeRedisCmd) Name() string        { return c.name }
+func (c fakeRedisCmd) Args() []interface{} { return c.args }
+func (c fakeRedisCmd) Err() error          { return c.err }
+
+// TestRedisLogger tests that only failed and slow commands are logged
+func TestRedisLogger(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.DebugLevel)
+	redisLogger := &RedisLogger{Logger: logger, SlowThreshold: 50 * time.Millisecond}
+	ctx := WithTraceID(context.Background(), "trace-redis")
+
+	// Fast, successful and missing keys are not logged
+	redisLogger.LogCommand(ctx, fakeRedisCmd{name: "get", args: []interface{}{"get", "session:1"}}, time.Millisecond)
+	redisLogger.LogCommand(ctx, fakeRedisCmd{name: "get", err: errors.New("redis: nil")}, time.Millisecond)
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no entries, got: %s", buf.String())
+	}
+
+	redisLogger.LogCommand(ctx, fakeRedisCmd{name: "set", args: []interface{}{"set", "session:1", "secret"}}, 80*time.Millisecond)
+	output := buf.String()
+	for _, expected := range []string{"level=warning", `msg="slow redis command"`, "redis_cmd=set", "redis_args=3", "trace_id=trace-redis"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "secret") {
+		t.Errorf("Expected arguments not to be logged, got: %s", output)
+	}
+
+	buf.Reset()
+	redisLogger.LogPipeline(ctx, []RedisCmd{
+		fakeRedisCmd{name: "incr"},
+		fakeRedisCmd{name: "expire", err: errors.New("ERR invalid expire time")},
+	}, time.Millisecond)
+	output = buf.String()
+	for _, expected := range []string{"level=error", `redis_cmd="incr expire"`, "redis_pipeline=2", `error="ERR invalid expire time"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
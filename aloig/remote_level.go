@@ -0,0 +1,194 @@
+package aloig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRemoteLevelInterval is how often a RemoteLevelPoller polls by default
+const DefaultRemoteLevelInterval = 30 * time.Second
+
+// RemoteLevel is the desired logging setup published by a remote source.
+// Empty values leave the current setup unchanged.
+type RemoteLevel struct {
+	// Level is the level of the singleton logger
+	Level string `json:"level"`
+
+	// SampleRate is the fraction of entries less severe than warning that
+	// are written, see SetSampleRate
+	SampleRate *float64 `json:"sample_rate"`
+
+	// NamedLevels are the levels of named loggers by name
+	NamedLevels map[string]string `json:"named_levels"`
+}
+
+// RemoteLevelSource fetches the desired logging setup, e.g. from an HTTP
+// endpoint or an etcd or Consul key
+type RemoteLevelSource func(ctx context.Context) (RemoteLevel, error)
+
+// HTTPLevelSource returns a source reading the RemoteLevel JSON document
+// served at url. A nil client uses http.DefaultClient. Consul keys can be
+// read with the raw KV endpoint, e.g.
+// http://consul:8500/v1/kv/logging/orders?raw.
+func HTTPLevelSource(url string, client *http.Client) RemoteLevelSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (RemoteLevel, error) {
+		var level RemoteLevel
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return level, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return level, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return level, fmt.Errorf("remote level: unexpected status %s", resp.Status)
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&level)
+		return level, err
+	}
+}
+
+// RemoteLevelOptions configures a RemoteLevelPoller
+type RemoteLevelOptions struct {
+	// Source fetches the desired logging setup
+	Source RemoteLevelSource
+
+	// Interval is the time between polls (0 uses DefaultRemoteLevelInterval)
+	Interval time.Duration
+
+	// Timeout bounds each poll (0 uses the interval)
+	Timeout time.Duration
+}
+
+// RemoteLevelPoller periodically applies the level, sample rate and named
+// levels published by a remote source to the singleton logger, so the
+// verbosity of a whole fleet can be raised during an incident without a
+// redeploy. Changes are logged at info level and failed polls at warning
+// level; the current setup is kept when a poll fails.
+type RemoteLevelPoller struct {
+	source   RemoteLevelSource
+	interval time.Duration
+	timeout  time.Duration
+
+	stop    chan struct{}
+	stopped sync.Once
+	done    chan struct{}
+}
+
+// NewRemoteLevelPoller creates a poller; call Start to begin polling
+func NewRemoteLevelPoller(options RemoteLevelOptions) *RemoteLevelPoller {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultRemoteLevelInterval
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = interval
+	}
+	return &RemoteLevelPoller{
+		source:   options.Source,
+		interval: interval,
+		timeout:  timeout,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start polls once and then every interval until Stop is called
+func (p *RemoteLevelPoller) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			p.pollAndLog()
+			select {
+			case <-ticker.C:
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops polling and waits for a poll in progress to finish
+func (p *RemoteLevelPoller) Stop() {
+	p.stopped.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// Poll fetches the remote setup once and applies it
+func (p *RemoteLevelPoller) Poll(ctx context.Context) error {
+	remote, err := p.source(ctx)
+	if err != nil {
+		return err
+	}
+	return remote.apply()
+}
+
+// pollAndLog polls with the poll timeout and logs failures
+func (p *RemoteLevelPoller) pollAndLog() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	if err := p.Poll(ctx); err != nil {
+		GetLogger().WithError(err).Warn("remote level poll failed")
+	}
+}
+
+// apply applies the remote setup to the singleton logger. Nothing is applied
+// when a level is invalid.
+func (r RemoteLevel) apply() error {
+	var level logrus.Level
+	if r.Level != "" {
+		parsed, err := logrus.ParseLevel(r.Level)
+		if err != nil {
+			return err
+		}
+		level = parsed
+	}
+	named := make(map[string]logrus.Level, len(r.NamedLevels))
+	for name, value := range r.NamedLevels {
+		parsed, err := logrus.ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("named level %q: %w", name, err)
+		}
+		named[name] = parsed
+	}
+
+	if from := GetLevel(); r.Level != "" && level != from {
+		// Log the change at the more verbose of both levels so it is seen
+		if level < from {
+			defer SetLevel(level)
+		} else {
+			SetLevel(level)
+		}
+		GetLogger().WithFields(map[string]interface{}{"from": from.String(), "to": level.String()}).Info("log level changed")
+	}
+	if r.SampleRate != nil && *r.SampleRate != SampleRate() {
+		SetSampleRate(*r.SampleRate)
+		GetLogger().WithField("sample_rate", SampleRate()).Info("log sample rate changed")
+	}
+	for name, level := range named {
+		if current, ok := NamedLevel(name); !ok || current != level {
+			SetNamedLevel(name, level)
+			GetLogger().WithFields(map[string]interface{}{LoggerNameField: name, "to": level.String()}).Info("named log level changed")
+		}
+	}
+	return nil
+}
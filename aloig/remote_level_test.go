@@ -0,0 +1,85 @@
+package aloig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestRemoteLevelPoller tests that the remote level, sample rate and named levels are applied
+func TestRemoteLevelPoller(t *testing.T) {
+	originalLog := log
+	defer func() { log = originalLog }()
+	defer SetSampleRate(1)
+	defer func() {
+		namedMu.Lock()
+		delete(namedLevels, "test-remote")
+		namedMu.Unlock()
+	}()
+
+	logger, buf := newBufferLogger(logrus.WarnLevel)
+	log = logger
+
+	var body atomic.Value
+	body.Store(`{"level": "debug", "sample_rate": 0.25, "named_levels": {"test-remote": "error"}}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+
+	poller := NewRemoteLevelPoller(RemoteLevelOptions{Source: HTTPLevelSource(server.URL, nil)})
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if GetLevel() != logrus.DebugLevel || SampleRate() != 0.25 {
+		t.Errorf("Expected debug level and 0.25 sample rate, got %s and %v", GetLevel(), SampleRate())
+	}
+	if level, ok := NamedLevel("test-remote"); !ok || level != logrus.ErrorLevel {
+		t.Errorf("Expected the named level to be set, got %s", level)
+	}
+	if output := buf.String(); !strings.Contains(output, `msg="log level changed" from=warning to=debug`) {
+		t.Errorf("Expected the level change to be logged, got: %s", output)
+	}
+
+	// An invalid document changes nothing
+	body.Store(`{"level": "loud", "sample_rate": 1}`)
+	if err := poller.Poll(context.Background()); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+	if GetLevel() != logrus.DebugLevel || SampleRate() != 0.25 {
+		t.Errorf("Expected the setup to be kept, got %s and %v", GetLevel(), SampleRate())
+	}
+}
+
+// TestRemoteLevelPollerStart tests background polling and failed polls
+func TestRemoteLevelPollerStart(t *testing.T) {
+	originalLog := log
+	defer func() { log = originalLog }()
+
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	log = logger
+
+	var polls int32
+	poller := NewRemoteLevelPoller(RemoteLevelOptions{
+		Interval: time.Millisecond,
+		Source: func(ctx context.Context) (RemoteLevel, error) {
+			atomic.AddInt32(&polls, 1)
+			return RemoteLevel{}, context.DeadlineExceeded
+		},
+	})
+	poller.Start()
+	for atomic.LoadInt32(&polls) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	poller.Stop()
+
+	if output := buf.String(); !strings.Contains(output, `msg="remote level poll failed"`) {
+		t.Errorf("Expected failed polls to be logged, got: %s", output)
+	}
+}
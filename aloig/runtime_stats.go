@@ -0,0 +1,120 @@
+package aloig
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRuntimeStatsInterval is how often a RuntimeStatsReporter reports by
+// default
+const DefaultRuntimeStatsInterval = time.Minute
+
+// RuntimeStatsOptions configures a RuntimeStatsReporter
+type RuntimeStatsOptions struct {
+	// Logger logs the stats (nil uses the singleton logger)
+	Logger Logger
+
+	// Interval is the time between reports (0 uses
+	// DefaultRuntimeStatsInterval)
+	Interval time.Duration
+}
+
+// RuntimeStatsReporter periodically logs the memory, GC, goroutine and open
+// file descriptor stats of the process at info level, for environments
+// without a metrics stack. Reading the memory stats briefly stops the world,
+// so the interval shouldn't be too short.
+type RuntimeStatsReporter struct {
+	logger   Logger
+	interval time.Duration
+
+	stop    chan struct{}
+	stopped sync.Once
+	done    chan struct{}
+}
+
+// NewRuntimeStatsReporter creates a reporter; call Start to begin reporting
+func NewRuntimeStatsReporter(options RuntimeStatsOptions) *RuntimeStatsReporter {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultRuntimeStatsInterval
+	}
+	return &RuntimeStatsReporter{
+		logger:   options.Logger,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start reports every interval until Stop is called
+func (r *RuntimeStatsReporter) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.Report(context.Background())
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops reporting and waits for a report in progress to finish
+func (r *RuntimeStatsReporter) Stop() {
+	r.stopped.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+// Report logs the stats once
+func (r *RuntimeStatsReporter) Report(ctx context.Context) {
+	logger := r.logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+	logger.Log(ctx, logrus.InfoLevel, "runtime stats", runtimeStatsFields()...)
+}
+
+// runtimeStatsFields returns the fields of a runtime stats entry. The open
+// file descriptors are only reported where /proc is available.
+func runtimeStatsFields() []Field {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	fields := []Field{
+		Int("goroutines", runtime.NumGoroutine()),
+		Uint64("heap_alloc_bytes", mem.HeapAlloc),
+		Uint64("heap_inuse_bytes", mem.HeapInuse),
+		Uint64("heap_objects", mem.HeapObjects),
+		Uint64("sys_bytes", mem.Sys),
+		Uint64("gc_count", uint64(mem.NumGC)),
+		Duration("gc_pause_total", time.Duration(mem.PauseTotalNs)),
+		Duration("gc_pause_last", time.Duration(mem.PauseNs[(mem.NumGC+255)%256])),
+		Float64("gc_cpu_fraction", mem.GCCPUFraction),
+	}
+	if fds, ok := openFDs(); ok {
+		fields = append(fields, Int("open_fds", fds))
+	}
+	return fields
+}
+
+// openFDs returns the number of file descriptors open by the process
+func openFDs() (int, bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	// Reading the directory opens one more
+	return len(entries) - 1, true
+}
@@ -0,0 +1,51 @@
+package aloig
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestRuntimeStatsReporter tests that a report carries the memory, GC and
+// goroutine stats
+func TestRuntimeStatsReporter(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	reporter := NewRuntimeStatsReporter(RuntimeStatsOptions{Logger: logger})
+	reporter.Report(context.Background())
+
+	output := buf.String()
+	if !strings.Contains(output, `msg="runtime stats"`) {
+		t.Fatalf("Expected a runtime stats entry, got: %s", output)
+	}
+	for _, field := range []string{"goroutines=", "heap_alloc_bytes=", "heap_objects=", "sys_bytes=", "gc_count=", "gc_pause_total=", "gc_cpu_fraction="} {
+		if !strings.Contains(output, field) {
+			t.Errorf("Expected the %s field, got: %s", field, output)
+		}
+	}
+	if _, ok := openFDs(); ok != strings.Contains(output, "open_fds=") {
+		t.Errorf("Expected the open_fds field where /proc is available, got: %s", output)
+	}
+}
+
+// TestRuntimeStatsReporterStart tests background reporting
+func TestRuntimeStatsReporterStart(t *testing.T) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	buf := &lockedBuffer{}
+	logger.logger.SetOutput(buf)
+	reporter := NewRuntimeStatsReporter(RuntimeStatsOptions{Logger: logger, Interval: time.Millisecond})
+	reporter.Start()
+	for strings.Count(buf.String(), "runtime stats") < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	reporter.Stop()
+	reporter.Stop()
+
+	reports := strings.Count(buf.String(), "runtime stats")
+	time.Sleep(5 * time.Millisecond)
+	if count := strings.Count(buf.String(), "runtime stats"); count != reports {
+		t.Errorf("Expected no report after Stop, got %d more", count-reports)
+	}
+}
@@ -0,0 +1,56 @@
+package aloig
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sampleRate holds the bits of the fraction of entries less severe than
+// warning that are written, see SetSampleRate
+var sampleRate = math.Float64bits(1)
+
+// SetSampleRate sets the fraction (0.0 - 1.0) of the info, debug and trace
+// entries written by the loggers created with NewLogger; warnings and more
+// severe entries are always written. It can be changed at any time, e.g. by
+// a RemoteLevelPoller during an incident.
+func SetSampleRate(rate float64) {
+	if rate < 0 || math.IsNaN(rate) {
+		rate = 0
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	atomic.StoreUint64(&sampleRate, math.Float64bits(rate))
+}
+
+// SampleRate returns the fraction of the entries less severe than warning
+// that are written
+func SampleRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&sampleRate))
+}
+
+// sampled decides if an entry at level is written
+func sampled(level logrus.Level) bool {
+	if level <= logrus.WarnLevel {
+		return true
+	}
+	rate := SampleRate()
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// samplingFormatter writes nothing for the entries dropped by the sample
+// rate. Hooks still see them, so breadcrumbs keep the full history.
+type samplingFormatter struct {
+	logrus.Formatter
+}
+
+// Format formats the entry unless it is sampled out
+func (f *samplingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if !sampled(entry.Level) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
@@ -0,0 +1,49 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestSampleRate tests that only entries less severe than warning are sampled
+func TestSampleRate(t *testing.T) {
+	defer SetSampleRate(1)
+	logger, buf := newBufferLogger(logrus.TraceLevel)
+	logger.logger.SetFormatter(&samplingFormatter{Formatter: logger.logger.Formatter})
+
+	SetSampleRate(0)
+	logger.Info("dropped")
+	logger.Warn("kept")
+	output := buf.String()
+	if strings.Contains(output, "dropped") || !strings.Contains(output, "kept") {
+		t.Errorf("Expected only the warning, got: %s", output)
+	}
+
+	SetSampleRate(2)
+	if SampleRate() != 1 {
+		t.Errorf("Expected the rate to be clamped to 1, got %v", SampleRate())
+	}
+	buf.Reset()
+	logger.Info("written")
+	if !strings.Contains(buf.String(), "written") {
+		t.Errorf("Expected every entry at rate 1, got: %s", buf.String())
+	}
+}
+
+// TestNewLoggerKeepsSampleRate tests that a logger without a configured rate
+// keeps the rate set before, e.g. by a RemoteLevelPoller
+func TestNewLoggerKeepsSampleRate(t *testing.T) {
+	defer SetSampleRate(1)
+	SetSampleRate(0.25)
+
+	NewLogger(Config{Environment: "dev"})
+	if SampleRate() != 0.25 {
+		t.Errorf("Expected the rate to be kept, got %v", SampleRate())
+	}
+	NewLogger(Config{Environment: "dev", SampleRate: 0.5})
+	if SampleRate() != 0.5 {
+		t.Errorf("Expected the configured rate, got %v", SampleRate())
+	}
+}
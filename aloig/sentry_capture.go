@@ -0,0 +1,88 @@
+package aloig
+
+import (
+	"context"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// SentryEventIDField is the field holding the ID of the Sentry event created
+// for an entry, added by the SentryHook and CaptureException so log search
+// and Sentry issues link to each other. Entries that already carry it are
+// not reported again.
+const SentryEventIDField = "sentry_event_id"
+
+// CaptureException reports err to Sentry with the context fields as tags and
+// logs it at error level with the resulting event ID, so support teams can
+// jump from the log line to the Sentry issue. The exception goes through the
+// Sentry hook of the logger, so its ignore rules and routes apply as to the
+// logged errors; without hook it is sent to the current hub. It returns the
+// event ID, or an empty string if Sentry is not configured or the event was
+// not sent.
+func CaptureException(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	root := GetLogger()
+	eventID := captureLoggerException(root, ctx, err)
+
+	logger := root.WithError(err)
+	if eventID != "" {
+		logger = logger.WithField(SentryEventIDField, eventID)
+	}
+	logger.ErrorContext(ctx, err.Error())
+
+	return eventID
+}
+
+// captureLoggerException sends err to Sentry through the Sentry hook of
+// logger, matching the entry logged for err against its filter and routes,
+// or to the current hub when the logger has no hook
+func captureLoggerException(logger Logger, ctx context.Context, err error) string {
+	l, ok := logger.(*logrusLogger)
+	if !ok || l.settings == nil || l.settings.sentryHook == nil {
+		return captureException(sentry.CurrentHub(), ctx, err)
+	}
+
+	hook := l.settings.sentryHook
+	entry := l.contextEntry(ctx, getContextFields(ctx), registeredContextFields(), 1)
+	entry.Data[logrus.ErrorKey] = err
+	entry.Level = logrus.ErrorLevel
+	entry.Message = err.Error()
+	if hook.filter.Ignore(entry) {
+		return ""
+	}
+	return captureException(hook.hubFor(entry), ctx, err)
+}
+
+// captureException sends err to Sentry on a copy of hub scoped to the context
+func captureException(hub *sentry.Hub, ctx context.Context, err error) string {
+	if hub == nil || hub.Client() == nil {
+		return ""
+	}
+
+	id := scopedHub(hub, ctx).CaptureException(err)
+	if id == nil {
+		return ""
+	}
+	return string(*id)
+}
+
+// scopedHub returns a copy of hub whose scope carries the context fields as
+// tags and the context user
+func scopedHub(hub *sentry.Hub, ctx context.Context) *sentry.Hub {
+	local := hub.Clone()
+	local.ConfigureScope(func(scope *sentry.Scope) {
+		for key, value := range ExtractContextFields(ctx) {
+			if s, ok := value.(string); ok {
+				scope.SetTag(key, s)
+			}
+		}
+		if userID := GetUserID(ctx); userID != "" {
+			scope.SetUser(sentry.User{ID: sentryUserID(userID)})
+		}
+	})
+	return local
+}
@@ -0,0 +1,107 @@
+package aloig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// TestCaptureException tests that the exception is reported and its event ID logged
+func TestCaptureException(t *testing.T) {
+	buf, cleanup := setupTestLogger()
+	defer cleanup()
+
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	previousClient := sentry.CurrentHub().Client()
+	sentry.CurrentHub().BindClient(hub.Client())
+	defer sentry.CurrentHub().BindClient(previousClient)
+
+	ctx := WithTraceID(context.Background(), "trace-123")
+	ctx = WithUserID(ctx, "user-789")
+
+	eventID := CaptureException(ctx, errors.New("payment failed"))
+	if eventID == "" {
+		t.Fatal("Expected an event ID")
+	}
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	if string(events[0].EventID) != eventID {
+		t.Errorf("Expected event ID %s, got %s", eventID, events[0].EventID)
+	}
+	if events[0].Tags["trace_id"] != "trace-123" || events[0].User.ID != "user-789" {
+		t.Errorf("Expected context tags and user, got %v / %v", events[0].Tags, events[0].User)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "sentry_event_id="+eventID) || !strings.Contains(output, "trace_id=trace-123") {
+		t.Errorf("Expected log line with event ID and trace ID, got: %s", output)
+	}
+}
+
+// TestCaptureExceptionWithoutSentry tests that nothing is reported without a client
+func TestCaptureExceptionWithoutSentry(t *testing.T) {
+	if id := captureException(sentry.NewHub(nil, sentry.NewScope()), context.Background(), errors.New("x")); id != "" {
+		t.Errorf("Expected empty event ID, got %s", id)
+	}
+	if id := CaptureException(context.Background(), nil); id != "" {
+		t.Errorf("Expected empty event ID for nil error, got %s", id)
+	}
+}
+
+// TestCaptureExceptionSentryHook tests that exceptions go through the ignore
+// rules and routes of the Sentry hook of the logger
+func TestCaptureExceptionSentryHook(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	paymentsHub, paymentsTransport := newSentryTestHub(t, sentry.ClientOptions{})
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+	hook.SetRoutes("team", map[string]*sentry.Hub{"payments": paymentsHub})
+	filter, _ := NewSentryFilter([]error{context.Canceled}, nil, nil)
+	hook.SetFilter(filter)
+
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(hook)
+	logger.settings.sentryHook = hook
+	originalLog := log
+	log = logger
+	defer func() { log = originalLog }()
+
+	if id := CaptureException(context.Background(), context.Canceled); id != "" {
+		t.Errorf("Expected ignored error not to be reported, got event %s", id)
+	}
+	if len(transport.Events()) != 0 {
+		t.Errorf("Expected no event for the ignored error, got %d", len(transport.Events()))
+	}
+	if !strings.Contains(buf.String(), "context canceled") {
+		t.Errorf("Expected the ignored error to be logged, got: %s", buf.String())
+	}
+
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{"team": "payments"})
+	id := CaptureException(ctx, errors.New("card declined"))
+	if id == "" {
+		t.Fatal("Expected an event ID")
+	}
+	if events := paymentsTransport.Events(); len(events) != 1 || string(events[0].EventID) != id {
+		t.Errorf("Expected the event on the routed hub, got %v", events)
+	}
+	if len(transport.Events()) != 0 {
+		t.Errorf("Expected no event on the default hub, got %d", len(transport.Events()))
+	}
+}
+
+// TestSentryHookSkipsReportedEntries tests that captured exceptions are not reported twice
+func TestSentryHookSkipsReportedEntries(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+
+	hook.Fire(&logrus.Entry{Message: "already reported", Level: logrus.ErrorLevel, Data: logrus.Fields{SentryEventIDField: "abc"}})
+	if len(transport.Events()) != 0 {
+		t.Error("Expected entry with event ID not to be reported again")
+	}
+}
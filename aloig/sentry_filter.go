@@ -0,0 +1,63 @@
+package aloig
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SentryFilter decides which entries are expected noise that should not be
+// reported to Sentry, so quota is not consumed by them
+type SentryFilter struct {
+	// Errors are matched with errors.Is against the entry error
+	Errors []error
+
+	// Messages are matched against the entry message and error message
+	Messages []*regexp.Regexp
+
+	// Fields drop entries where any of the fields has the given value
+	Fields map[string]interface{}
+}
+
+// NewSentryFilter creates a filter compiling the given message patterns
+func NewSentryFilter(errs []error, messagePatterns []string, fields map[string]interface{}) (*SentryFilter, error) {
+	filter := &SentryFilter{Errors: errs, Fields: fields}
+	for _, pattern := range messagePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.Messages = append(filter.Messages, re)
+	}
+	return filter, nil
+}
+
+// Ignore checks if the entry matches any of the filter rules
+func (f *SentryFilter) Ignore(entry *logrus.Entry) bool {
+	if f == nil {
+		return false
+	}
+
+	err, _ := entry.Data[logrus.ErrorKey].(error)
+	for _, target := range f.Errors {
+		if err != nil && errors.Is(err, target) {
+			return true
+		}
+	}
+
+	for _, re := range f.Messages {
+		if re.MatchString(entry.Message) || (err != nil && re.MatchString(err.Error())) {
+			return true
+		}
+	}
+
+	for key, value := range f.Fields {
+		if fieldValue, ok := entry.Data[key]; ok && reflect.DeepEqual(fieldValue, value) {
+			return true
+		}
+	}
+
+	return false
+}
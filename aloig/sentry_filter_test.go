@@ -0,0 +1,70 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// TestSentryFilterIgnore tests the error, message and field ignore rules
+func TestSentryFilterIgnore(t *testing.T) {
+	filter, err := NewSentryFilter(
+		[]error{context.Canceled},
+		[]string{`^client disconnected`, `broken pipe`},
+		map[string]interface{}{"status": 499},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		entry  *logrus.Entry
+		ignore bool
+	}{
+		{"wrapped canceled", &logrus.Entry{Message: "request failed", Data: logrus.Fields{logrus.ErrorKey: fmt.Errorf("query: %w", context.Canceled)}}, true},
+		{"message match", &logrus.Entry{Message: "client disconnected early", Data: logrus.Fields{}}, true},
+		{"error message match", &logrus.Entry{Message: "write failed", Data: logrus.Fields{logrus.ErrorKey: fmt.Errorf("write: broken pipe")}}, true},
+		{"field match", &logrus.Entry{Message: "request failed", Data: logrus.Fields{"status": 499}}, true},
+		{"field mismatch", &logrus.Entry{Message: "request failed", Data: logrus.Fields{"status": 500}}, false},
+		{"other error", &logrus.Entry{Message: "request failed", Data: logrus.Fields{logrus.ErrorKey: fmt.Errorf("timeout")}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := filter.Ignore(tc.entry); result != tc.ignore {
+				t.Errorf("Expected Ignore = %v, got %v", tc.ignore, result)
+			}
+		})
+	}
+
+	var nilFilter *SentryFilter
+	if nilFilter.Ignore(&logrus.Entry{Data: logrus.Fields{}}) {
+		t.Error("Expected nil filter not to ignore entries")
+	}
+}
+
+// TestNewSentryFilterInvalidPattern tests that invalid patterns are reported
+func TestNewSentryFilterInvalidPattern(t *testing.T) {
+	if _, err := NewSentryFilter(nil, []string{"("}, nil); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+// TestSentryHookFilter tests that filtered entries are not sent to Sentry
+func TestSentryHookFilter(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+	hook.SetFilter(&SentryFilter{Errors: []error{context.Canceled}})
+
+	hook.Fire(&logrus.Entry{Message: "canceled", Level: logrus.ErrorLevel, Data: logrus.Fields{logrus.ErrorKey: context.Canceled}})
+	hook.Fire(&logrus.Entry{Message: "real failure", Level: logrus.ErrorLevel, Data: logrus.Fields{}})
+
+	events := transport.Events()
+	if len(events) != 1 || events[0].Message != "real failure" {
+		t.Errorf("Expected only 'real failure' to be sent, got %d events", len(events))
+	}
+}
@@ -0,0 +1,116 @@
+package aloig
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+var (
+	sentryMu           sync.Mutex
+	sentryHooks        []*SentryHook
+	sentryFlushDefault = 2 * time.Second
+)
+
+// registerSentryHook makes the hook flushed by FlushSentry and sets the
+// default flush timeout
+func registerSentryHook(hook *SentryHook, flushTimeout time.Duration) {
+	sentryMu.Lock()
+	defer sentryMu.Unlock()
+	sentryHooks = append(sentryHooks, hook)
+	sentryFlushDefault = flushTimeout
+}
+
+// unregisterSentryHook stops flushing the hook with FlushSentry
+func unregisterSentryHook(hook *SentryHook) {
+	sentryMu.Lock()
+	defer sentryMu.Unlock()
+	sentryHooks = removeItem(sentryHooks, hook)
+}
+
+// FlushSentry ensures that all pending events are sent to Sentry, waiting
+// at most the configured SentryFlushTimeout (2 seconds by default)
+func FlushSentry() {
+	sentryMu.Lock()
+	timeout := sentryFlushDefault
+	sentryMu.Unlock()
+
+	FlushSentryTimeout(timeout)
+}
+
+// FlushSentryTimeout waits until pending events are sent to Sentry, including
+// the entries buffered by the async hooks, blocking for at most timeout. It
+// returns false if the timeout was reached.
+func FlushSentryTimeout(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	if !flushAsyncHooks(deadline) {
+		return false
+	}
+	return flushSentryHubs(sentryHubs(), time.Until(deadline))
+}
+
+// sentryFlushInterval is the longest flush of FlushSentryContext, which
+// retries until the context is done
+const sentryFlushInterval = 100 * time.Millisecond
+
+// FlushSentryContext waits until pending events are sent to Sentry or the
+// context is done. It returns false if the context ended first. The events
+// are flushed in short intervals, so no flush outlives the context by more
+// than one interval.
+func FlushSentryContext(ctx context.Context) bool {
+	for {
+		interval := sentryFlushInterval
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline); remaining < interval {
+				interval = remaining
+			}
+		}
+		if ctx.Err() != nil || interval <= 0 {
+			return false
+		}
+		if FlushSentryTimeout(interval) {
+			return true
+		}
+	}
+}
+
+// sentryHubs returns the hubs in use, one per Sentry client
+func sentryHubs() []*sentry.Hub {
+	sentryMu.Lock()
+	defer sentryMu.Unlock()
+
+	candidates := []*sentry.Hub{sentry.CurrentHub()}
+	for _, hook := range sentryHooks {
+		candidates = append(candidates, hook.hubs()...)
+	}
+
+	seen := make(map[*sentry.Client]bool)
+	var hubs []*sentry.Hub
+	for _, hub := range candidates {
+		client := hub.Client()
+		if client == nil || seen[client] {
+			continue
+		}
+		seen[client] = true
+		hubs = append(hubs, hub)
+	}
+	return hubs
+}
+
+// flushSentryHubs flushes the hubs concurrently
+func flushSentryHubs(hubs []*sentry.Hub, timeout time.Duration) bool {
+	results := make(chan bool, len(hubs))
+	for _, hub := range hubs {
+		go func(hub *sentry.Hub) {
+			results <- hub.Flush(timeout)
+		}(hub)
+	}
+
+	flushed := true
+	for range hubs {
+		flushed = <-results && flushed
+	}
+	return flushed
+}
@@ -0,0 +1,95 @@
+package aloig
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// blockingTransport is a Sentry transport whose flush never completes in time
+type blockingTransport struct {
+	sentryTestTransport
+	flushing int32
+}
+
+func (t *blockingTransport) Flush(timeout time.Duration) bool {
+	atomic.AddInt32(&t.flushing, 1)
+	defer atomic.AddInt32(&t.flushing, -1)
+	time.Sleep(timeout)
+	return false
+}
+
+// TestFlushSentryTimeout tests flushing the registered hooks with a timeout
+func TestFlushSentryTimeout(t *testing.T) {
+	hub, _ := newSentryTestHub(t, sentry.ClientOptions{})
+
+	sentryMu.Lock()
+	previousHooks, previousDefault := sentryHooks, sentryFlushDefault
+	sentryHooks = nil
+	sentryMu.Unlock()
+	defer func() {
+		sentryMu.Lock()
+		sentryHooks, sentryFlushDefault = previousHooks, previousDefault
+		sentryMu.Unlock()
+	}()
+
+	registerSentryHook(NewSentryHook(hub, DefaultSentryLevels), 5*time.Second)
+	if sentryFlushDefault != 5*time.Second {
+		t.Errorf("Expected configured default flush timeout, got %v", sentryFlushDefault)
+	}
+
+	found := false
+	for _, h := range sentryHubs() {
+		found = found || h.Client() == hub.Client()
+	}
+	if !found {
+		t.Error("Expected registered hub to be flushed")
+	}
+
+	if !FlushSentryTimeout(time.Second) {
+		t.Error("Expected flush to succeed")
+	}
+}
+
+// TestFlushSentryContext tests that flushing stops when the context is done,
+// including without deadline
+func TestFlushSentryContext(t *testing.T) {
+	transport := &blockingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://public@example.com/1", Transport: transport})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	sentryMu.Lock()
+	previousHooks := sentryHooks
+	sentryHooks = []*SentryHook{NewSentryHook(sentry.NewHub(client, sentry.NewScope()), DefaultSentryLevels)}
+	sentryMu.Unlock()
+	defer func() {
+		sentryMu.Lock()
+		sentryHooks = previousHooks
+		sentryMu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if FlushSentryContext(ctx) {
+		t.Error("Expected flush of a blocked transport to fail")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected FlushSentryContext to return once the context is done, took %v", elapsed)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if FlushSentryContext(ctx) {
+		t.Error("Expected flush of a blocked transport to fail")
+	}
+	if flushing := atomic.LoadInt32(&transport.flushing); flushing != 0 {
+		t.Errorf("Expected no flush to outlive the context, got %d", flushing)
+	}
+}
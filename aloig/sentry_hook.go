@@ -0,0 +1,297 @@
+package aloig
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// These field keys are converted into Sentry metadata when found in an entry
+// with the expected type, instead of being sent as event extra data
+const (
+	// SentryFieldRequest holds an *http.Request
+	SentryFieldRequest = "request"
+	// SentryFieldUser holds a sentry.User or *sentry.User value
+	SentryFieldUser = "user"
+	// SentryFieldTransaction holds a transaction name as a string
+	SentryFieldTransaction = "transaction"
+	// SentryFieldFingerprint holds a []string used to group the event
+	SentryFieldFingerprint = "fingerprint"
+)
+
+// sentryLevels maps logrus levels to Sentry levels
+var sentryLevels = map[logrus.Level]sentry.Level{
+	logrus.TraceLevel: sentry.LevelDebug,
+	logrus.DebugLevel: sentry.LevelDebug,
+	logrus.InfoLevel:  sentry.LevelInfo,
+	logrus.WarnLevel:  sentry.LevelWarning,
+	logrus.ErrorLevel: sentry.LevelError,
+	logrus.FatalLevel: sentry.LevelFatal,
+	logrus.PanicLevel: sentry.LevelFatal,
+}
+
+// SentryHook is a hook that sends log entries to Sentry. Entries at one of
+// the event levels are captured as Sentry events; less severe entries down to
+// the breadcrumb level are recorded as breadcrumbs, so events arrive with the
+// activity trail that preceded them.
+type SentryHook struct {
+	hub             *sentry.Hub
+	eventLevels     []logrus.Level
+	breadcrumbLevel logrus.Level
+	breadcrumbs     bool
+	tagFields       []string
+	filter          *SentryFilter
+	routeField      string
+	routes          map[string]*sentry.Hub
+}
+
+// NewSentryHook creates a hook that captures entries at the given levels
+// as events on the given hub
+func NewSentryHook(hub *sentry.Hub, levels []logrus.Level) *SentryHook {
+	return &SentryHook{hub: hub, eventLevels: levels}
+}
+
+// SetBreadcrumbLevel records entries at level or more severe as breadcrumbs
+// when they are not captured as events. Breadcrumbs are recorded on the hub
+// of the entry context, e.g. the request hub set by sentryhttp, and attached
+// to the events logged with the same context; entries without hub in their
+// context are not recorded, so requests don't see each other's breadcrumbs.
+// The number of breadcrumbs kept is bounded by the client's MaxBreadcrumbs
+// option.
+func (hook *SentryHook) SetBreadcrumbLevel(level logrus.Level) {
+	hook.breadcrumbLevel = level
+	hook.breadcrumbs = true
+}
+
+// SetTagFields sends the given entry fields as Sentry tags instead of
+// event extra data, making them searchable in Sentry
+func (hook *SentryHook) SetTagFields(fields []string) {
+	hook.tagFields = fields
+}
+
+// SetFilter drops the events matched by filter instead of sending them
+func (hook *SentryHook) SetFilter(filter *SentryFilter) {
+	hook.filter = filter
+}
+
+// SetRoutes sends the entries whose field has one of the given values to the
+// matching hub instead of the default one
+func (hook *SentryHook) SetRoutes(field string, routes map[string]*sentry.Hub) {
+	hook.routeField = field
+	hook.routes = routes
+}
+
+// sinkName names the sink of the hook in Health
+func (hook *SentryHook) sinkName() string {
+	return "sentry"
+}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *SentryHook) Levels() []logrus.Level {
+	levels := append([]logrus.Level{}, hook.eventLevels...)
+	if hook.breadcrumbs {
+		for _, level := range logrus.AllLevels {
+			if level <= hook.breadcrumbLevel && !hook.isEventLevel(level) {
+				levels = append(levels, level)
+			}
+		}
+	}
+	return levels
+}
+
+// Fire sends the entry to Sentry as an event or a breadcrumb. The ID of the
+// event is added to the entry as SentryEventIDField.
+func (hook *SentryHook) Fire(entry *logrus.Entry) error {
+	hub := hook.hubFor(entry)
+	contextHub := entryHub(entry)
+
+	if !hook.isEventLevel(entry.Level) {
+		if contextHub != nil {
+			contextHub.AddBreadcrumb(hook.entryToBreadcrumb(entry), nil)
+		}
+		return nil
+	}
+
+	if _, reported := entry.Data[SentryEventIDField]; reported || hook.filter.Ignore(entry) {
+		return nil
+	}
+
+	event := hook.entryToEvent(hub, entry)
+	var id *sentry.EventID
+	if client := hub.Client(); client != nil && contextHub != nil {
+		// Send with the scope of the context: its breadcrumbs, user, ...
+		id = client.CaptureEvent(event, nil, contextHub.Scope())
+	} else {
+		id = hub.CaptureEvent(event)
+	}
+	if id == nil {
+		// Without ID, the client dropped the event on purpose, sampled out
+		// or filtered by BeforeSend, unless there is no client at all
+		if hub.Client() == nil {
+			return errors.New("failed to send to sentry: no client")
+		}
+		return nil
+	}
+	// Hooks fire before the entry is written, so the log line links to the
+	// event. Entries fired by an AsyncHook are copies written already.
+	entry.Data[SentryEventIDField] = string(*id)
+	return nil
+}
+
+// Flush waits until buffered events are sent, blocking for at most timeout.
+// It returns false if the timeout was reached.
+func (hook *SentryHook) Flush(timeout time.Duration) bool {
+	flushed := hook.hub.Flush(timeout)
+	for _, hub := range hook.routes {
+		flushed = hub.Flush(timeout) && flushed
+	}
+	return flushed
+}
+
+// hubs returns the default hub and the hubs of every route
+func (hook *SentryHook) hubs() []*sentry.Hub {
+	hubs := []*sentry.Hub{hook.hub}
+	for _, hub := range hook.routes {
+		hubs = append(hubs, hub)
+	}
+	return hubs
+}
+
+// hubFor returns the hub receiving the entry according to the routes
+func (hook *SentryHook) hubFor(entry *logrus.Entry) *sentry.Hub {
+	if hook.routeField != "" {
+		if value, ok := entry.Data[hook.routeField]; ok {
+			if hub, ok := hook.routes[fmt.Sprint(value)]; ok {
+				return hub
+			}
+		}
+	}
+	return hook.hub
+}
+
+// entryHub returns the hub of the entry context, if any
+func entryHub(entry *logrus.Entry) *sentry.Hub {
+	if entry.Context == nil {
+		return nil
+	}
+	return sentry.GetHubFromContext(entry.Context)
+}
+
+// isEventLevel checks if entries at level are captured as events
+func (hook *SentryHook) isEventLevel(level logrus.Level) bool {
+	for _, l := range hook.eventLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// entryToBreadcrumb converts a log entry into a Sentry breadcrumb
+func (hook *SentryHook) entryToBreadcrumb(entry *logrus.Entry) *sentry.Breadcrumb {
+	data := make(map[string]interface{}, len(entry.Data))
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		data[k] = v
+	}
+
+	return &sentry.Breadcrumb{
+		Type:      "default",
+		Category:  "log",
+		Message:   entry.Message,
+		Data:      data,
+		Level:     entrySentryLevel(entry),
+		Timestamp: entry.Time,
+	}
+}
+
+// entryToEvent converts a log entry into a Sentry event
+func (hook *SentryHook) entryToEvent(hub *sentry.Hub, entry *logrus.Entry) *sentry.Event {
+	extra := make(map[string]interface{}, len(entry.Data))
+	for k, v := range entry.Data {
+		extra[k] = v
+	}
+
+	event := sentry.NewEvent()
+	event.Level = entrySentryLevel(entry)
+	event.Message = entry.Message
+	event.Timestamp = entry.Time
+	event.Extra = extra
+
+	for _, field := range hook.tagFields {
+		if value, ok := extra[field]; ok {
+			delete(extra, field)
+			event.Tags[field] = fmt.Sprint(value)
+		}
+	}
+	if req, ok := extra[SentryFieldRequest].(*http.Request); ok {
+		delete(extra, SentryFieldRequest)
+		event.Request = sentry.NewRequest(req)
+	}
+	if err, ok := extra[logrus.ErrorKey].(error); ok {
+		delete(extra, logrus.ErrorKey)
+		event.Exception = exceptions(hub, err)
+	}
+	if user, ok := extra[SentryFieldUser].(sentry.User); ok {
+		delete(extra, SentryFieldUser)
+		event.User = user
+	}
+	if user, ok := extra[SentryFieldUser].(*sentry.User); ok {
+		delete(extra, SentryFieldUser)
+		event.User = *user
+	}
+	if event.User.ID == "" {
+		event.User.ID = entryUserID(entry)
+	}
+	if transaction, ok := extra[SentryFieldTransaction].(string); ok {
+		delete(extra, SentryFieldTransaction)
+		event.Transaction = transaction
+	}
+	if fingerprint, ok := extra[SentryFieldFingerprint].([]string); ok {
+		delete(extra, SentryFieldFingerprint)
+		event.Fingerprint = fingerprint
+	}
+	if entry.Level == logrus.PanicLevel {
+		enrichPanicEvent(hub, event, entry)
+	}
+
+	return event
+}
+
+// entryUserID returns the user ID carried by the entry fields or context.
+// The fields come first as they are pseudonymized by the PseudonymizeHook.
+func entryUserID(entry *logrus.Entry) string {
+	if userID, _ := entry.Data[string(UserIDKey)].(string); userID != "" {
+		return userID
+	}
+	return sentryUserID(GetUserID(entry.Context))
+}
+
+// exceptions converts an error and the errors it wraps into Sentry exceptions,
+// ordered from the innermost cause to the outermost error
+func exceptions(hub *sentry.Hub, err error) []sentry.Exception {
+	client := hub.Client()
+	if client == nil || !client.Options().AttachStacktrace {
+		return []sentry.Exception{{Type: "error", Value: err.Error()}}
+	}
+
+	var exceptions []sentry.Exception
+	for ; err != nil; err = errors.Unwrap(err) {
+		exceptions = append(exceptions, sentry.Exception{
+			Type:       "error",
+			Value:      err.Error(),
+			Stacktrace: sentry.ExtractStacktrace(err),
+		})
+	}
+
+	for i, j := 0, len(exceptions)-1; i < j; i, j = i+1, j-1 {
+		exceptions[i], exceptions[j] = exceptions[j], exceptions[i]
+	}
+	return exceptions
+}
@@ -0,0 +1,346 @@
+package aloig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// sentryTestTransport is a Sentry transport that keeps events in memory
+type sentryTestTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *sentryTestTransport) Configure(options sentry.ClientOptions) {}
+
+func (t *sentryTestTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *sentryTestTransport) Flush(timeout time.Duration) bool {
+	return true
+}
+
+func (t *sentryTestTransport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentry.Event{}, t.events...)
+}
+
+// newSentryTestHub creates a hub whose events are kept in the returned transport
+func newSentryTestHub(t *testing.T, options sentry.ClientOptions) (*sentry.Hub, *sentryTestTransport) {
+	transport := &sentryTestTransport{}
+	options.Dsn = "https://public@example.com/1"
+	options.Transport = transport
+	client, err := sentry.NewClient(options)
+	if err != nil {
+		t.Fatalf("Failed to create Sentry client: %v", err)
+	}
+	return sentry.NewHub(client, sentry.NewScope()), transport
+}
+
+// TestSentryHookCapturesEvents tests that event level entries become Sentry events
+func TestSentryHookCapturesEvents(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+
+	entry := &logrus.Entry{
+		Message: "test error",
+		Level:   logrus.ErrorLevel,
+		Data: logrus.Fields{
+			logrus.ErrorKey:        errors.New("boom"),
+			SentryFieldFingerprint: []string{"group"},
+			"key":                  "value",
+		},
+	}
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Message != "test error" || event.Level != sentry.LevelError {
+		t.Errorf("Unexpected event message/level: %s/%s", event.Message, event.Level)
+	}
+	if len(event.Exception) != 1 || event.Exception[0].Value != "boom" {
+		t.Errorf("Expected exception 'boom', got %+v", event.Exception)
+	}
+	if len(event.Fingerprint) != 1 || event.Fingerprint[0] != "group" {
+		t.Errorf("Expected fingerprint [group], got %v", event.Fingerprint)
+	}
+	if event.Extra["key"] != "value" {
+		t.Errorf("Expected extra key=value, got %v", event.Extra)
+	}
+}
+
+// TestSentryHookEventIDInLogLine tests that the written entry carries the ID
+// of its Sentry event
+func TestSentryHookEventIDInLogLine(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.logger.AddHook(NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel}))
+
+	logger.Error("payment failed")
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON line, got %s", buf.String())
+	}
+	if line[SentryEventIDField] != string(events[0].EventID) {
+		t.Errorf("Expected %s=%s, got %v", SentryEventIDField, events[0].EventID, line[SentryEventIDField])
+	}
+}
+
+// TestSentryHookBreadcrumbs tests that lower level entries are attached as breadcrumbs
+func TestSentryHookBreadcrumbs(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{MaxBreadcrumbs: 2})
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+	hook.SetBreadcrumbLevel(logrus.InfoLevel)
+
+	levels := hook.Levels()
+	for _, level := range []logrus.Level{logrus.ErrorLevel, logrus.WarnLevel, logrus.InfoLevel} {
+		found := false
+		for _, l := range levels {
+			found = found || l == level
+		}
+		if !found {
+			t.Errorf("Expected hook levels to include %s", level)
+		}
+	}
+	for _, l := range levels {
+		if l == logrus.DebugLevel {
+			t.Error("Expected hook levels to exclude debug")
+		}
+	}
+
+	// Each request records its breadcrumbs on its own hub
+	ctx := sentry.SetHubOnContext(context.Background(), hub.Clone())
+	other := sentry.SetHubOnContext(context.Background(), hub.Clone())
+	for _, msg := range []string{"first", "second", "third"} {
+		hook.Fire(&logrus.Entry{Message: msg, Level: logrus.InfoLevel, Data: logrus.Fields{"step": msg}, Context: ctx})
+	}
+	hook.Fire(&logrus.Entry{Message: "other request", Level: logrus.InfoLevel, Data: logrus.Fields{}, Context: other})
+	hook.Fire(&logrus.Entry{Message: "no request", Level: logrus.InfoLevel, Data: logrus.Fields{}})
+	hook.Fire(&logrus.Entry{Message: "failure", Level: logrus.ErrorLevel, Data: logrus.Fields{}, Context: ctx})
+	hook.Fire(&logrus.Entry{Message: "other failure", Level: logrus.ErrorLevel, Data: logrus.Fields{}, Context: other})
+
+	events := transport.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if breadcrumbs := events[1].Breadcrumbs; len(breadcrumbs) != 1 || breadcrumbs[0].Message != "other request" {
+		t.Errorf("Expected only the breadcrumb of the other request, got %v", breadcrumbs)
+	}
+	breadcrumbs := events[0].Breadcrumbs
+	if len(breadcrumbs) != 2 {
+		t.Fatalf("Expected 2 breadcrumbs (max), got %d", len(breadcrumbs))
+	}
+	if breadcrumbs[0].Message != "second" || breadcrumbs[1].Message != "third" {
+		t.Errorf("Expected most recent breadcrumbs, got %s, %s", breadcrumbs[0].Message, breadcrumbs[1].Message)
+	}
+	if breadcrumbs[1].Data["step"] != "third" {
+		t.Errorf("Expected breadcrumb data to include fields, got %v", breadcrumbs[1].Data)
+	}
+}
+
+// TestSentryEventLevels tests the configurable Sentry level threshold
+func TestSentryEventLevels(t *testing.T) {
+	if levels := sentryEventLevels(Config{}); len(levels) != len(DefaultSentryLevels) {
+		t.Errorf("Expected default levels, got %v", levels)
+	}
+
+	config := Config{SentryLevels: []logrus.Level{logrus.FatalLevel}}
+	levels := sentryEventLevels(config)
+	if len(levels) != 1 || levels[0] != logrus.FatalLevel {
+		t.Errorf("Expected [fatal], got %v", levels)
+	}
+
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	hook := NewSentryHook(hub, levels)
+	hook.Fire(&logrus.Entry{Message: "not sent", Level: logrus.ErrorLevel, Data: logrus.Fields{}})
+	if len(transport.Events()) != 0 {
+		t.Error("Expected error entries not to be sent when restricted to fatal")
+	}
+}
+
+// TestSentryHookUserFromContext tests that events are attributed to the context user
+func TestSentryHookUserFromContext(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+
+	logrusInstance := logrus.New()
+	logrusInstance.SetOutput(io.Discard)
+	logrusInstance.AddHook(NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel}))
+	logger := &logrusLogger{logger: logrusInstance}
+
+	ctx := WithUserID(context.Background(), "user-789")
+	logger.ErrorContext(ctx, "context error")
+	logger.WithContext(WithUserID(context.Background(), "user-123")).Error("with context error")
+	logger.Error("anonymous error")
+
+	events := transport.Events()
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
+	}
+	if events[0].User.ID != "user-789" {
+		t.Errorf("Expected user 'user-789', got '%s'", events[0].User.ID)
+	}
+	if events[1].User.ID != "user-123" {
+		t.Errorf("Expected user 'user-123', got '%s'", events[1].User.ID)
+	}
+	if events[2].User.ID != "" {
+		t.Errorf("Expected no user, got '%s'", events[2].User.ID)
+	}
+}
+
+// TestSentryHookTagFields tests that allowlisted fields become Sentry tags
+func TestSentryHookTagFields(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+	hook.SetTagFields([]string{"tenant", "region", "missing"})
+
+	hook.Fire(&logrus.Entry{
+		Message: "tagged error",
+		Level:   logrus.ErrorLevel,
+		Data:    logrus.Fields{"tenant": "acme", "region": 1, "other": "extra"},
+	})
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Tags["tenant"] != "acme" || event.Tags["region"] != "1" {
+		t.Errorf("Expected tenant and region tags, got %v", event.Tags)
+	}
+	if _, ok := event.Tags["missing"]; ok {
+		t.Error("Expected absent fields not to be tagged")
+	}
+	if _, ok := event.Extra["tenant"]; ok {
+		t.Error("Expected tagged field to be removed from extra")
+	}
+	if event.Extra["other"] != "extra" {
+		t.Errorf("Expected other fields to stay in extra, got %v", event.Extra)
+	}
+}
+
+// TestWithFingerprint tests that the logger fingerprint controls Sentry grouping
+func TestWithFingerprint(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{})
+
+	logrusInstance := logrus.New()
+	logrusInstance.SetOutput(io.Discard)
+	logrusInstance.AddHook(NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel}))
+	logger := &logrusLogger{logger: logrusInstance}
+
+	logger.WithFingerprint("payment", "E1001").Errorf("payment %s failed", "p-1")
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	fingerprint := events[0].Fingerprint
+	if len(fingerprint) != 2 || fingerprint[0] != "payment" || fingerprint[1] != "E1001" {
+		t.Errorf("Expected fingerprint [payment E1001], got %v", fingerprint)
+	}
+	if _, ok := events[0].Extra[SentryFieldFingerprint]; ok {
+		t.Error("Expected fingerprint not to be sent as extra data")
+	}
+}
+
+// TestSentryBeforeSend tests that the user supplied BeforeSend can scrub and drop events
+func TestSentryBeforeSend(t *testing.T) {
+	config := Config{
+		SentryBeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			if event.Message == "drop me" {
+				return nil
+			}
+			delete(event.Extra, "password")
+			return event
+		},
+	}
+
+	options := sentryClientOptions(config)
+	hub, transport := newSentryTestHub(t, options)
+	hook := NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel})
+
+	// A dropped event is not a failure of the sink
+	if err := hook.Fire(&logrus.Entry{Message: "drop me", Level: logrus.ErrorLevel, Data: logrus.Fields{}}); err != nil {
+		t.Errorf("Expected a dropped event not to fail, got %v", err)
+	}
+	hook.Fire(&logrus.Entry{Message: "keep me", Level: logrus.ErrorLevel, Data: logrus.Fields{"password": "secret"}})
+
+	events := transport.Events()
+	if len(events) != 1 || events[0].Message != "keep me" {
+		t.Fatalf("Expected only 'keep me' to be sent, got %d events", len(events))
+	}
+	if _, ok := events[0].Extra["password"]; ok {
+		t.Error("Expected password to be scrubbed")
+	}
+
+	if sentryClientOptions(Config{}).BeforeSend == nil {
+		t.Error("Expected default BeforeSend to be set")
+	}
+}
+
+// TestSentryTracesSampler tests that the traces sampler is passed to the client
+func TestSentryTracesSampler(t *testing.T) {
+	sampler := sentry.TracesSampler(func(ctx sentry.SamplingContext) float64 {
+		if ctx.Span != nil && ctx.Span.Name == "GET /health" {
+			return 0
+		}
+		return 1
+	})
+
+	options := sentryClientOptions(Config{TracesSampler: sampler})
+	if options.TracesSampler == nil {
+		t.Fatal("Expected traces sampler to be set")
+	}
+	if rate := options.TracesSampler(sentry.SamplingContext{Span: &sentry.Span{Name: "GET /health"}}); rate != 0 {
+		t.Errorf("Expected rate 0 for health checks, got %v", rate)
+	}
+}
+
+// TestSentryClientOptionsPassthrough tests that client tuning options reach Sentry
+func TestSentryClientOptionsPassthrough(t *testing.T) {
+	config := Config{
+		SentryHTTPSProxy:     "http://proxy.internal:3128",
+		SentryDebug:          true,
+		SentryMaxBreadcrumbs: 50,
+		SentrySampleRate:     0.5,
+	}
+
+	options := sentryClientOptions(config)
+	if options.HTTPSProxy != config.SentryHTTPSProxy || !options.Debug || options.MaxBreadcrumbs != 50 || options.SampleRate != 0.5 {
+		t.Errorf("Expected options to be passed through, got %+v", options)
+	}
+
+	proxyURL, err := sentryProxy(options)(nil)
+	if err != nil || proxyURL.Host != "proxy.internal:3128" {
+		t.Errorf("Expected proxy.internal:3128, got %v (%v)", proxyURL, err)
+	}
+
+	if timeout := sentryFlushTimeout(Config{}); timeout != 2*time.Second {
+		t.Errorf("Expected default flush timeout of 2s, got %v", timeout)
+	}
+	if timeout := sentryFlushTimeout(Config{SentryFlushTimeout: 5 * time.Second}); timeout != 5*time.Second {
+		t.Errorf("Expected flush timeout of 5s, got %v", timeout)
+	}
+}
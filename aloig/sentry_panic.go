@@ -0,0 +1,52 @@
+package aloig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// enrichPanicEvent tags the Sentry event of a panic entry with the context
+// fields of the entry, so it can be searched by trace or user like the
+// errors reported with CaptureError, and reports the panic as its main
+// exception with the stack of the logging call
+func enrichPanicEvent(hub *sentry.Hub, event *sentry.Event, entry *logrus.Entry) {
+	for key := range ExtractContextFields(entry.Context) {
+		// The fields hold the values processed by the hooks (e.g.
+		// pseudonymized IDs), without those removed by a FieldPolicy
+		value, ok := entry.Data[key]
+		if !ok {
+			continue
+		}
+		delete(event.Extra, key)
+		event.Tags[key] = fmt.Sprint(value)
+	}
+
+	exception := sentry.Exception{Type: "panic", Value: entry.Message, Mechanism: &sentry.Mechanism{Type: "panic"}}
+	if client := hub.Client(); client != nil && client.Options().AttachStacktrace {
+		exception.Stacktrace = callerStacktrace()
+	}
+	event.Exception = append(event.Exception, exception)
+}
+
+// callerStacktrace returns the current stack trace without the frames of
+// logrus and of this package
+func callerStacktrace() *sentry.Stacktrace {
+	stacktrace := sentry.NewStacktrace()
+	if stacktrace == nil {
+		return nil
+	}
+
+	frames := stacktrace.Frames[:0]
+	for _, frame := range stacktrace.Frames {
+		logging := frame.Module == "github.com/sirupsen/logrus" ||
+			frame.Module+"." == aloigPackage && !strings.HasSuffix(frame.AbsPath, "_test.go")
+		if !logging {
+			frames = append(frames, frame)
+		}
+	}
+	stacktrace.Frames = frames
+	return stacktrace
+}
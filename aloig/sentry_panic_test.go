@@ -0,0 +1,95 @@
+package aloig
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// TestSentryPanicEventContext tests that panic events are tagged with the
+// context fields and report the panic as their exception
+func TestSentryPanicEventContext(t *testing.T) {
+	hub, transport := newSentryTestHub(t, sentry.ClientOptions{AttachStacktrace: true})
+
+	logrusInstance := logrus.New()
+	logrusInstance.SetOutput(io.Discard)
+	logrusInstance.AddHook(NewSentryHook(hub, []logrus.Level{logrus.ErrorLevel, logrus.PanicLevel}))
+	logger := &logrusLogger{logger: logrusInstance}
+
+	ctx := WithTraceID(context.Background(), "trace-123")
+	ctx = WithUserID(ctx, "user-456")
+	ctx = ContextWithFields(ctx, map[string]interface{}{"tenant": "acme"})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected PanicContext to panic")
+			}
+		}()
+		logger.WithField("key", "value").PanicContext(ctx, "invariant broken")
+	}()
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	for key, value := range map[string]string{string(TraceIDKey): "trace-123", string(UserIDKey): "user-456", "tenant": "acme"} {
+		if event.Tags[key] != value {
+			t.Errorf("Expected tag %s=%s, got %v", key, value, event.Tags)
+		}
+		if _, ok := event.Extra[key]; ok {
+			t.Errorf("Expected tagged field %s to be removed from extra", key)
+		}
+	}
+	if event.Extra["key"] != "value" {
+		t.Errorf("Expected other fields to stay in extra, got %v", event.Extra)
+	}
+	if event.User.ID != "user-456" {
+		t.Errorf("Expected user 'user-456', got '%s'", event.User.ID)
+	}
+
+	if len(event.Exception) == 0 {
+		t.Fatal("Expected a panic exception")
+	}
+	exception := event.Exception[len(event.Exception)-1]
+	if exception.Type != "panic" || exception.Value != "invariant broken" {
+		t.Errorf("Expected panic exception 'invariant broken', got %+v", exception)
+	}
+	if exception.Mechanism == nil || exception.Mechanism.Type != "panic" {
+		t.Errorf("Expected panic mechanism, got %+v", exception.Mechanism)
+	}
+	if exception.Stacktrace == nil || len(exception.Stacktrace.Frames) == 0 {
+		t.Fatal("Expected a stack trace")
+	}
+	for _, frame := range exception.Stacktrace.Frames {
+		if frame.Module == "github.com/sirupsen/logrus" || !strings.HasSuffix(frame.AbsPath, "_test.go") && frame.Module+"." == aloigPackage {
+			t.Errorf("Expected logging frames to be skipped, got %s.%s", frame.Module, frame.Function)
+		}
+	}
+	last := exception.Stacktrace.Frames[len(exception.Stacktrace.Frames)-1]
+	if !strings.HasSuffix(last.AbsPath, "sentry_panic_test.go") {
+		t.Errorf("Expected the last frame to be the panicking call, got %s:%d", last.AbsPath, last.Lineno)
+	}
+}
+
+// TestAsyncHookFiresPanicsSynchronously tests that panic entries are fired
+// before the logging call returns
+func TestAsyncHookFiresPanicsSynchronously(t *testing.T) {
+	recording := newRecordingHook()
+	close(recording.gate)
+	hook := NewAsyncHook(recording, AsyncHookOptions{Workers: 1})
+	defer hook.Close()
+
+	if err := hook.Fire(&logrus.Entry{Level: logrus.PanicLevel, Message: "panic", Data: logrus.Fields{}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	recording.mu.Lock()
+	defer recording.mu.Unlock()
+	if got := len(recording.messages[""]); got != 1 {
+		t.Errorf("Expected the panic entry to be fired synchronously, got %d entries", got)
+	}
+}
@@ -0,0 +1,32 @@
+package aloig
+
+import (
+	"path/filepath"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// newSentryRouteHubs creates one hub per routed DSN, sharing the rest of the
+// Sentry configuration. Each route gets its own spool directory.
+func newSentryRouteHubs(config Config) (map[string]*sentry.Hub, error) {
+	hubs := make(map[string]*sentry.Hub, len(config.SentryRouteDSNs))
+	for value, dsn := range config.SentryRouteDSNs {
+		options := sentryClientOptions(config)
+		options.Dsn = dsn
+
+		spoolDir := config.SentrySpoolDir
+		if spoolDir != "" {
+			spoolDir = filepath.Join(spoolDir, value)
+		}
+		if err := applySentrySpool(&options, config, spoolDir); err != nil {
+			return nil, err
+		}
+
+		client, err := sentry.NewClient(options)
+		if err != nil {
+			return nil, err
+		}
+		hubs[value] = sentry.NewHub(client, sentry.NewScope())
+	}
+	return hubs, nil
+}
@@ -0,0 +1,62 @@
+package aloig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// TestSentryHookRoutes tests that events are routed to the hub of their team
+func TestSentryHookRoutes(t *testing.T) {
+	defaultHub, defaultTransport := newSentryTestHub(t, sentry.ClientOptions{})
+	paymentsHub, paymentsTransport := newSentryTestHub(t, sentry.ClientOptions{})
+
+	hook := NewSentryHook(defaultHub, []logrus.Level{logrus.ErrorLevel})
+	hook.SetRoutes("team", map[string]*sentry.Hub{"payments": paymentsHub})
+	hook.SetBreadcrumbLevel(logrus.InfoLevel)
+
+	ctx := sentry.SetHubOnContext(context.Background(), defaultHub.Clone())
+	hook.Fire(&logrus.Entry{Message: "payments step", Level: logrus.InfoLevel, Data: logrus.Fields{"team": "payments"}, Context: ctx})
+	hook.Fire(&logrus.Entry{Message: "payments error", Level: logrus.ErrorLevel, Data: logrus.Fields{"team": "payments"}, Context: ctx})
+	hook.Fire(&logrus.Entry{Message: "search error", Level: logrus.ErrorLevel, Data: logrus.Fields{"team": "search"}})
+	hook.Fire(&logrus.Entry{Message: "untagged error", Level: logrus.ErrorLevel, Data: logrus.Fields{}})
+
+	paymentsEvents := paymentsTransport.Events()
+	if len(paymentsEvents) != 1 || paymentsEvents[0].Message != "payments error" {
+		t.Fatalf("Expected the payments error to be routed, got %d events", len(paymentsEvents))
+	}
+	if len(paymentsEvents[0].Breadcrumbs) != 1 || paymentsEvents[0].Breadcrumbs[0].Message != "payments step" {
+		t.Errorf("Expected the payments breadcrumb on the routed event, got %v", paymentsEvents[0].Breadcrumbs)
+	}
+	if events := defaultTransport.Events(); len(events) != 2 {
+		t.Errorf("Expected 2 events on the default hub, got %d", len(events))
+	}
+}
+
+// TestNewSentryRouteHubs tests that a hub is created per routed DSN
+func TestNewSentryRouteHubs(t *testing.T) {
+	config := Config{
+		SentryRouteDSNs: map[string]string{
+			"payments": "https://public@example.com/2",
+			"search":   "https://public@example.com/3",
+		},
+	}
+
+	hubs, err := newSentryRouteHubs(config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(hubs) != 2 || hubs["payments"] == nil || hubs["search"] == nil {
+		t.Fatalf("Expected a hub per route, got %v", hubs)
+	}
+	if dsn := hubs["payments"].Client().Options().Dsn; dsn != "https://public@example.com/2" {
+		t.Errorf("Expected payments DSN, got %s", dsn)
+	}
+
+	config.SentryRouteDSNs["broken"] = "not a dsn"
+	if _, err := newSentryRouteHubs(config); err == nil {
+		t.Error("Expected error for invalid DSN")
+	}
+}
@@ -0,0 +1,341 @@
+package aloig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SentrySpool is an http.RoundTripper for the Sentry transport that keeps
+// the events Sentry could not receive (network errors, 429 and 5xx responses)
+// in a bounded spool and resends them with exponential backoff, so error
+// reports are not lost during network blips. Events are not resent before
+// the delay Sentry asks for in the X-Sentry-Rate-Limits or Retry-After
+// headers of its 429 and 503 responses. When a directory is given the
+// spool is persisted on disk and survives restarts; otherwise it is kept in
+// memory. When the spool is full the oldest event is dropped.
+type SentrySpool struct {
+	base      http.RoundTripper
+	dir       string
+	maxEvents int
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu      sync.Mutex
+	queue   []*spooledRequest
+	seq     uint64
+	retryAt time.Time
+	pending chan struct{}
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped sync.Once
+}
+
+// spooledRequest is a Sentry request waiting to be resent
+type spooledRequest struct {
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+
+	file string
+}
+
+// NewSentrySpool creates a spool sending requests through base (nil uses a
+// transport honoring the proxy environment variables) and keeping at most
+// maxEvents failed events, on disk in dir when it is not empty
+func NewSentrySpool(base http.RoundTripper, dir string, maxEvents int) (*SentrySpool, error) {
+	return newSentrySpool(base, dir, maxEvents, time.Second)
+}
+
+// newSentrySpool creates a spool whose retries start after minBackoff
+func newSentrySpool(base http.RoundTripper, dir string, maxEvents int, minBackoff time.Duration) (*SentrySpool, error) {
+	if base == nil {
+		base = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+
+	spool := &SentrySpool{
+		base:       base,
+		dir:        dir,
+		maxEvents:  maxEvents,
+		minBackoff: minBackoff,
+		maxBackoff: 5 * time.Minute,
+		pending:    make(chan struct{}, 1),
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		if err := spool.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	go spool.run()
+	return spool, nil
+}
+
+// RoundTrip sends the request, spooling it when Sentry can't receive it
+func (s *SentrySpool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := s.base.RoundTrip(req)
+	if shouldRetry(resp, err) {
+		s.limit(resp)
+		s.add(&spooledRequest{URL: req.URL.String(), Header: req.Header.Clone(), Body: body})
+		return resp, err
+	}
+
+	// Sentry is reachable again, resend what was spooled
+	if s.Len() > 0 {
+		signal(s.wake)
+	}
+	return resp, err
+}
+
+// Len returns the number of events waiting to be resent
+func (s *SentrySpool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// Close stops the retry loop. Spooled events stay on disk when persisted.
+func (s *SentrySpool) Close() {
+	s.stopped.Do(func() {
+		close(s.stop)
+	})
+}
+
+// shouldRetry checks if a request failed in a way that is worth retrying
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter returns how long Sentry asks not to send after a 429 or 503
+// response, from its X-Sentry-Rate-Limits header (the longest of its limits)
+// or else its Retry-After header, in seconds or as an HTTP date. It returns
+// 0 when the response sets no delay.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0
+	}
+
+	if limits := resp.Header.Get("X-Sentry-Rate-Limits"); limits != "" {
+		var delay time.Duration
+		for _, limit := range strings.Split(limits, ",") {
+			seconds, _, _ := strings.Cut(strings.TrimSpace(limit), ":")
+			if n, err := strconv.ParseFloat(seconds, 64); err == nil && time.Duration(n*float64(time.Second)) > delay {
+				delay = time.Duration(n * float64(time.Second))
+			}
+		}
+		if delay > 0 {
+			return delay
+		}
+	}
+
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// limit records the delay Sentry asks for in resp, keeping the latest end
+func (s *SentrySpool) limit(resp *http.Response) {
+	now := time.Now()
+	delay := retryAfter(resp, now)
+	if delay <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if retryAt := now.Add(delay); retryAt.After(s.retryAt) {
+		s.retryAt = retryAt
+	}
+}
+
+// limited returns how long is left before Sentry accepts events again
+func (s *SentrySpool) limited() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Until(s.retryAt)
+}
+
+// signal sends on a buffered channel without blocking
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// run resends spooled events with exponential backoff until closed
+func (s *SentrySpool) run() {
+	backoff := s.minBackoff
+	for {
+		if s.Len() == 0 {
+			select {
+			case <-s.stop:
+				return
+			case <-s.pending:
+			}
+		}
+
+		delay := backoff
+		if limited := s.limited(); limited > delay {
+			delay = limited
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+
+		// Sentry asked not to send yet
+		if s.limited() > 0 {
+			continue
+		}
+
+		if s.drain() {
+			backoff = s.minBackoff
+		} else if backoff *= 2; backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// drain resends spooled events oldest first, stopping at the first failure.
+// It returns false if an event could not be sent.
+func (s *SentrySpool) drain() bool {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			return true
+		}
+		item := s.queue[0]
+		s.mu.Unlock()
+
+		req, err := http.NewRequest(http.MethodPost, item.URL, bytes.NewReader(item.Body))
+		if err != nil {
+			s.remove(item)
+			continue
+		}
+		req.Header = item.Header.Clone()
+
+		resp, err := s.base.RoundTrip(req)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if shouldRetry(resp, err) {
+			s.limit(resp)
+			return false
+		}
+		s.remove(item)
+	}
+}
+
+// add spools a request, dropping the oldest one when the spool is full
+func (s *SentrySpool) add(item *spooledRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dir != "" {
+		s.seq++
+		item.file = filepath.Join(s.dir, fmt.Sprintf("%020d-%06d.json", time.Now().UnixNano(), s.seq%1000000))
+		if data, err := json.Marshal(item); err == nil {
+			os.WriteFile(item.file, data, 0o600)
+		}
+	}
+
+	s.queue = append(s.queue, item)
+	for len(s.queue) > s.maxEvents {
+		if s.queue[0].file != "" {
+			os.Remove(s.queue[0].file)
+		}
+		s.queue = s.queue[1:]
+	}
+	signal(s.pending)
+}
+
+// remove deletes a request from the spool once it was delivered
+func (s *SentrySpool) remove(item *spooledRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, queued := range s.queue {
+		if queued == item {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+	if item.file != "" {
+		os.Remove(item.file)
+	}
+}
+
+// load reads the requests persisted in the spool directory
+func (s *SentrySpool) load() error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		item := &spooledRequest{}
+		if err := json.Unmarshal(data, item); err != nil {
+			os.Remove(file)
+			continue
+		}
+		item.file = file
+		s.queue = append(s.queue, item)
+	}
+
+	for len(s.queue) > s.maxEvents {
+		os.Remove(s.queue[0].file)
+		s.queue = s.queue[1:]
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package aloig
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer is a test server that fails until it is marked as up
+type flakyServer struct {
+	mu       sync.Mutex
+	up       bool
+	received []string
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.up {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	f.received = append(f.received, string(body))
+}
+
+func (f *flakyServer) setUp(up bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.up = up
+}
+
+func (f *flakyServer) Received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.received...)
+}
+
+// post sends a body through the spool
+func post(t *testing.T, spool *SentrySpool, url, body string) {
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	resp, err := spool.RoundTrip(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+}
+
+// waitFor polls cond until it holds or the timeout expires
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestSentrySpoolRetries tests that failed events are resent once Sentry is back
+func TestSentrySpoolRetries(t *testing.T) {
+	server := &flakyServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	spool, err := newSentrySpool(nil, "", 2, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer spool.Close()
+
+	post(t, spool, ts.URL, "event-1")
+	post(t, spool, ts.URL, "event-2")
+	post(t, spool, ts.URL, "event-3")
+
+	if spool.Len() != 2 {
+		t.Fatalf("Expected spool bounded to 2 events, got %d", spool.Len())
+	}
+
+	server.setUp(true)
+	waitFor(t, func() bool { return spool.Len() == 0 })
+
+	received := server.Received()
+	if len(received) != 2 || received[0] != "event-2" || received[1] != "event-3" {
+		t.Errorf("Expected the two most recent events in order, got %v", received)
+	}
+}
+
+// TestSentrySpoolPersistence tests that spooled events survive a restart
+func TestSentrySpoolPersistence(t *testing.T) {
+	server := &flakyServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	spool, err := NewSentrySpool(nil, dir, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	post(t, spool, ts.URL, "persisted")
+	spool.Close()
+
+	server.setUp(true)
+	restored, err := newSentrySpool(nil, dir, 10, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer restored.Close()
+
+	if restored.Len() != 1 {
+		t.Fatalf("Expected 1 restored event, got %d", restored.Len())
+	}
+	waitFor(t, func() bool { return restored.Len() == 0 })
+
+	if received := server.Received(); len(received) != 1 || received[0] != "persisted" {
+		t.Errorf("Expected persisted event to be resent, got %v", received)
+	}
+}
+
+// rateLimitedServer is a test server answering 429 with a Retry-After
+// header to its first request
+type rateLimitedServer struct {
+	mu       sync.Mutex
+	limited  bool
+	received []time.Time
+}
+
+func (r *rateLimitedServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.limited {
+		r.limited = true
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	r.received = append(r.received, time.Now())
+}
+
+func (r *rateLimitedServer) Received() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time{}, r.received...)
+}
+
+// TestSentrySpoolRetryAfter tests that spooled events are not resent before
+// the Retry-After delay of a 429 response
+func TestSentrySpoolRetryAfter(t *testing.T) {
+	server := &rateLimitedServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	spool, err := newSentrySpool(nil, "", 10, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer spool.Close()
+
+	start := time.Now()
+	post(t, spool, ts.URL, "event")
+	if spool.Len() != 1 {
+		t.Fatalf("Expected the rate limited event to be spooled, got %d", spool.Len())
+	}
+
+	waitFor(t, func() bool { return spool.Len() == 0 })
+
+	received := server.Received()
+	if len(received) != 1 {
+		t.Fatalf("Expected the event to be resent once, got %d", len(received))
+	}
+	if waited := received[0].Sub(start); waited < time.Second {
+		t.Errorf("Expected the event to be resent after the Retry-After delay, resent after %v", waited)
+	}
+}
+
+// TestRetryAfter tests the delays read from Sentry responses
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status int
+		header map[string]string
+		expect time.Duration
+	}{
+		{http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}, 30 * time.Second},
+		{http.StatusServiceUnavailable, map[string]string{"Retry-After": now.Add(time.Minute).Format(http.TimeFormat)}, time.Minute},
+		{http.StatusTooManyRequests, map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0},
+		{http.StatusTooManyRequests, map[string]string{"Retry-After": "soon"}, 0},
+		{http.StatusTooManyRequests, map[string]string{}, 0},
+		{http.StatusInternalServerError, map[string]string{"Retry-After": "30"}, 0},
+		{http.StatusTooManyRequests, map[string]string{"X-Sentry-Rate-Limits": "60:error:key, 2700::organization", "Retry-After": "30"}, 2700 * time.Second},
+		{http.StatusTooManyRequests, map[string]string{"X-Sentry-Rate-Limits": "invalid", "Retry-After": "30"}, 30 * time.Second},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d %v", tc.status, tc.header), func(t *testing.T) {
+			resp := &http.Response{StatusCode: tc.status, Header: http.Header{}}
+			for key, value := range tc.header {
+				resp.Header.Set(key, value)
+			}
+			if delay := retryAfter(resp, now); delay != tc.expect {
+				t.Errorf("Expected %v, got %v", tc.expect, delay)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package aloig
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SequenceField holds the sequence number of an entry
+const SequenceField = "seq"
+
+// sequenceFormatter numbers the entries it formats from 1. It wraps the
+// formatter of the encoder, below the deduplication and sampling, so only
+// the entries written are numbered: a gap in the numbers of a logger means
+// entries were lost after being written (e.g. dropped by an async writer or
+// the shipping pipeline), and numbers out of order mean they were reordered.
+type sequenceFormatter struct {
+	logrus.Formatter
+
+	next uint64
+}
+
+// Format adds the next sequence number to the entry and formats it
+func (f *sequenceFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	entry.Data[SequenceField] = atomic.AddUint64(&f.next, 1)
+	return f.Formatter.Format(entry)
+}
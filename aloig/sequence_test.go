@@ -0,0 +1,67 @@
+package aloig
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestSequenceFormatter tests that concurrent entries get distinct,
+// consecutive numbers
+func TestSequenceFormatter(t *testing.T) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	buf := &lockedBuffer{}
+	logger.logger.SetOutput(buf)
+	logger.logger.SetFormatter(&sequenceFormatter{Formatter: logger.logger.Formatter})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				logger.Info("entry")
+			}
+		}()
+	}
+	wg.Wait()
+
+	var numbers []int
+	for _, match := range regexp.MustCompile(`seq=(\d+)`).FindAllStringSubmatch(buf.String(), -1) {
+		n, _ := strconv.Atoi(match[1])
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	if len(numbers) != 100 {
+		t.Fatalf("Expected 100 numbered entries, got %d", len(numbers))
+	}
+	for i, n := range numbers {
+		if n != i+1 {
+			t.Fatalf("Expected the numbers 1 to 100, got %v", numbers)
+		}
+	}
+}
+
+// TestNewLoggerSequence tests that the entries suppressed by the
+// deduplication are not numbered
+func TestNewLoggerSequence(t *testing.T) {
+	logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel, Sequence: true, DedupWindow: time.Hour}).(*logrusLogger)
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+
+	logger.Info("first")
+	logger.Info("first")
+	logger.Info("second")
+
+	output := buf.String()
+	if strings.Count(output, "\n") != 2 || !strings.Contains(output, `"seq":1`) || !strings.Contains(output, `"seq":2`) {
+		t.Errorf("Expected two consecutive numbered entries, got: %s", output)
+	}
+}
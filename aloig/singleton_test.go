@@ -0,0 +1,141 @@
+package aloig
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// TestReconfigure tests that Reconfigure replaces an initialized singleton
+func TestReconfigure(t *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	first := GetLogger()
+
+	// ConfigureLogger has no effect once the singleton exists
+	ConfigureLogger(Config{Environment: "dev", Level: logrus.ErrorLevel})
+	if GetLogger() != first {
+		t.Error("Expected ConfigureLogger not to replace an initialized singleton")
+	}
+
+	Reconfigure(Config{Environment: "dev", Level: logrus.ErrorLevel})
+	second := GetLogger()
+	if second == first {
+		t.Fatal("Expected Reconfigure to replace the singleton")
+	}
+	if level := second.(*logrusLogger).level(); level != logrus.ErrorLevel {
+		t.Errorf("Expected error level, got %s", level)
+	}
+}
+
+// TestReconfigureBeforeGetLogger tests that GetLogger keeps a reconfigured singleton
+func TestReconfigureBeforeGetLogger(t *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	Reconfigure(Config{Environment: "dev", Level: logrus.WarnLevel})
+	if level := GetLogger().(*logrusLogger).level(); level != logrus.WarnLevel {
+		t.Errorf("Expected warn level, got %s", level)
+	}
+}
+
+// TestResetForTesting tests that ConfigureLogger works again after a reset
+func TestResetForTesting(t *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	GetLogger()
+	ResetForTesting()
+
+	ConfigureLogger(Config{Environment: "dev", Level: logrus.DebugLevel})
+	if level := GetLogger().(*logrusLogger).level(); level != logrus.DebugLevel {
+		t.Errorf("Expected debug level, got %s", level)
+	}
+}
+
+// TestReconfigureConcurrent tests swapping the singleton while it is in use
+func TestReconfigureConcurrent(t *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if GetLogger() == nil {
+				t.Error("Expected a logger")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			Reconfigure(Config{Environment: "dev", Level: logrus.PanicLevel})
+		}()
+	}
+	wg.Wait()
+}
+
+// TestResetForTestingConcurrent tests resetting the singleton while it is
+// in use
+func TestResetForTestingConcurrent(t *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+	ConfigureLogger(Config{Environment: "dev", Level: logrus.PanicLevel})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Info("in use")
+		}()
+		go func() {
+			defer wg.Done()
+			ResetForTesting()
+			ConfigureLogger(Config{Environment: "dev", Level: logrus.PanicLevel})
+		}()
+	}
+	wg.Wait()
+}
+
+// TestReconfigureClosesPrevious tests that Reconfigure releases the async
+// writer, the async hooks and the Sentry hook of the replaced logger
+func TestReconfigureClosesPrevious(t *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+	client := sentry.CurrentHub().Client()
+	defer sentry.CurrentHub().BindClient(client)
+
+	config := Config{
+		Environment:      "prod",
+		Level:            logrus.PanicLevel,
+		SentryDSN:        "https://public@example.com/1",
+		AsyncBufferSize:  8,
+		AsyncHookWorkers: 1,
+	}
+	counts := func() []int {
+		resourcesMu.Lock()
+		asyncMu.Lock()
+		asyncHooksMu.Lock()
+		sentryMu.Lock()
+		defer func() {
+			sentryMu.Unlock()
+			asyncHooksMu.Unlock()
+			asyncMu.Unlock()
+			resourcesMu.Unlock()
+		}()
+		return []int{len(liveResources), len(asyncWriters), len(asyncHooks), len(sentryHooks)}
+	}
+
+	Reconfigure(config)
+	before := counts()
+	Reconfigure(config)
+	Reconfigure(config)
+	if after := counts(); !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected the replaced loggers to be released, got %v registered instead of %v", after, before)
+	}
+}
@@ -0,0 +1,457 @@
+package aloig
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+)
+
+// SQLOptions configures the logging of a wrapped database/sql driver
+type SQLOptions struct {
+	// Logger receives the entries. The singleton logger is used when nil.
+	Logger Logger
+
+	// SlowThreshold logs the queries taking longer at warn level.
+	// Zero disables slow query warnings.
+	SlowThreshold time.Duration
+
+	// LogParams logs the values of the bind parameters as the params field,
+	// rather than only counting them, with the ones selected by
+	// ParamRedaction logged as [REDACTED]
+	LogParams bool
+
+	// ParamRedaction selects the parameters redacted when LogParams is set
+	ParamRedaction SQLParamRedaction
+}
+
+// WrapSQLDriver wraps a database/sql driver so every query is logged with
+// its text, duration and error, along with the trace fields of the query
+// context. Literal values in the query text are replaced with "?" and bind
+// parameters are only counted, unless LogParams is set. Successful queries are logged at
+// debug level, slow ones at warn level and failed ones at error level.
+//
+//	sql.Register("postgres-logged", aloig.WrapSQLDriver(&pq.Driver{}, aloig.SQLOptions{}))
+//	db, err := sql.Open("postgres-logged", dsn)
+func WrapSQLDriver(d driver.Driver, options SQLOptions) driver.Driver {
+	return &sqlDriver{driver: d, options: options}
+}
+
+// WrapSQLConnector wraps a driver.Connector like WrapSQLDriver, for use with
+// sql.OpenDB
+func WrapSQLConnector(c driver.Connector, options SQLOptions) driver.Connector {
+	return &sqlConnector{connector: c, options: options}
+}
+
+// sqlDriver is a driver logging the queries of its connections
+type sqlDriver struct {
+	driver  driver.Driver
+	options SQLOptions
+}
+
+// Open opens a logged connection
+func (d *sqlDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{conn: conn, options: d.options}, nil
+}
+
+// sqlConnector is a connector logging the queries of its connections
+type sqlConnector struct {
+	connector driver.Connector
+	options   SQLOptions
+}
+
+// Connect opens a logged connection
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{conn: conn, options: c.options}, nil
+}
+
+// Driver returns the wrapped driver
+func (c *sqlConnector) Driver() driver.Driver {
+	return &sqlDriver{driver: c.connector.Driver(), options: c.options}
+}
+
+// sqlConn is a connection logging its queries
+type sqlConn struct {
+	conn    driver.Conn
+	options SQLOptions
+}
+
+// Prepare prepares a logged statement
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext prepares a logged statement
+func (c *sqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		logSQL(ctx, c.options, query, nil, time.Now(), err)
+		return nil, err
+	}
+	logged := &sqlStmt{stmt: stmt, conn: c.conn, query: query, options: c.options}
+	if converter, ok := stmt.(driver.ColumnConverter); ok {
+		return &sqlConverterStmt{sqlStmt: logged, converter: converter}, nil
+	}
+	return logged, nil
+}
+
+// Close closes the connection
+func (c *sqlConn) Close() error {
+	return c.conn.Close()
+}
+
+// Begin starts a transaction
+func (c *sqlConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a transaction with the given options. For the drivers
+// without options, it fails on the options they can't honor, like
+// database/sql does without the wrapper.
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+
+	tx, err := c.conn.Begin()
+	if err == nil && ctx.Err() != nil {
+		tx.Rollback()
+		return nil, ctx.Err()
+	}
+	return tx, err
+}
+
+// ExecContext executes a query without preparing it when the driver allows it
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	logSQL(ctx, c.options, query, args, start, err)
+	return result, err
+}
+
+// QueryContext runs a query without preparing it when the driver allows it
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	logSQL(ctx, c.options, query, args, start, err)
+	return rows, err
+}
+
+// Ping checks the connection when the driver supports it
+func (c *sqlConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession resets the connection when the driver supports it
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid reports whether the connection can be reused
+func (c *sqlConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue delegates argument conversion to the driver
+func (c *sqlConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+// sqlStmt is a prepared statement logging its executions
+type sqlStmt struct {
+	stmt    driver.Stmt
+	conn    driver.Conn
+	query   string
+	options SQLOptions
+}
+
+// sqlConverterStmt is a logged statement whose driver converts the
+// arguments with driver.ColumnConverter. Only these statements implement
+// it, as database/sql converts the arguments differently then.
+type sqlConverterStmt struct {
+	*sqlStmt
+	converter driver.ColumnConverter
+}
+
+// ColumnConverter returns the converter of the argument at index idx
+func (s *sqlConverterStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return s.converter.ColumnConverter(idx)
+}
+
+// Close closes the statement
+func (s *sqlStmt) Close() error {
+	return s.stmt.Close()
+}
+
+// NumInput returns the number of placeholder parameters
+func (s *sqlStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+// Exec executes the statement
+func (s *sqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.stmt.Exec(args)
+	logSQL(context.Background(), s.options, s.query, valuesToNamedValues(args), start, err)
+	return result, err
+}
+
+// Query runs the statement
+func (s *sqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.stmt.Query(args)
+	logSQL(context.Background(), s.options, s.query, valuesToNamedValues(args), start, err)
+	return rows, err
+}
+
+// ExecContext executes the statement
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if execer, ok := s.stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err != nil {
+			return nil, err
+		}
+		result, err = s.stmt.Exec(values)
+	}
+	logSQL(ctx, s.options, s.query, args, start, err)
+	return result, err
+}
+
+// QueryContext runs the statement
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err != nil {
+			return nil, err
+		}
+		rows, err = s.stmt.Query(values)
+	}
+	logSQL(ctx, s.options, s.query, args, start, err)
+	return rows, err
+}
+
+// CheckNamedValue delegates argument conversion to the statement, or to
+// the connection like database/sql does when the statement doesn't check
+// them
+func (s *sqlStmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	if checker, ok := s.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+// namedValuesToValues converts arguments for drivers without context support
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// valuesToNamedValues converts the arguments of the calls without context
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// logSQL logs a query with its duration and error
+func logSQL(ctx context.Context, options SQLOptions, query string, args []driver.NamedValue, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	logger := options.Logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+
+	duration := time.Since(start)
+	entry := logger.WithFields(map[string]interface{}{
+		"query":       RedactSQL(query),
+		"args":        len(args),
+		"duration_ms": float64(duration) / float64(time.Millisecond),
+	})
+	if options.LogParams && len(args) > 0 {
+		entry = entry.WithField("params", sqlParams(query, args, options.ParamRedaction))
+	}
+
+	switch {
+	case err != nil:
+		entry.WithError(err).ErrorContext(ctx, "sql query failed")
+	case options.SlowThreshold > 0 && duration > options.SlowThreshold:
+		entry.WarnContext(ctx, "slow sql query")
+	default:
+		entry.DebugContext(ctx, "sql query")
+	}
+}
+
+// RedactSQL replaces the string and numeric literals of a query with "?",
+// keeping identifiers, quoted identifiers and placeholders ($1, :name, @p1).
+// The string literals may escape quotes by doubling them or with a
+// backslash, and may be dollar-quoted ($$...$$ or $tag$...$tag$).
+func RedactSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'':
+			// String literal, '' and \' are escaped quotes
+			j := i + 1
+			for j < len(query) {
+				if query[j] == '\\' {
+					j += 2
+					continue
+				}
+				if query[j] == '\'' {
+					if j+1 < len(query) && query[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+			if j > len(query) {
+				j = len(query)
+			}
+			b.WriteByte('?')
+			i = j
+		case c == '$' && sqlDollarTag(query[i:]) != "":
+			// Dollar-quoted string literal
+			tag := sqlDollarTag(query[i:])
+			j := strings.Index(query[i+len(tag):], tag)
+			if j < 0 {
+				j = len(query)
+			} else {
+				j += i + 2*len(tag)
+			}
+			b.WriteByte('?')
+			i = j
+		case c == '"' || c == '`':
+			// Quoted identifier
+			j := strings.IndexByte(query[i+1:], c)
+			if j < 0 {
+				j = len(query)
+			} else {
+				j += i + 2
+			}
+			b.WriteString(query[i:j])
+			i = j
+		case isSQLDigit(c):
+			j := i
+			for j < len(query) && (isSQLDigit(query[j]) || query[j] == '.') {
+				j++
+			}
+			b.WriteByte('?')
+			i = j
+		case isSQLIdentChar(c) || c == '$' || c == ':' || c == '@':
+			// Identifier, keyword or placeholder
+			j := i + 1
+			for j < len(query) && isSQLIdentChar(query[j]) {
+				j++
+			}
+			b.WriteString(query[i:j])
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// sqlDollarTag returns the tag opening the dollar-quoted string at the start
+// of query ($$ or $tag$), or an empty string
+func sqlDollarTag(query string) string {
+	j := 1
+	if j < len(query) && !isSQLDigit(query[j]) {
+		for j < len(query) && isSQLIdentChar(query[j]) {
+			j++
+		}
+	}
+	if j < len(query) && query[j] == '$' {
+		return query[:j+1]
+	}
+	return ""
+}
+
+// isSQLDigit checks if c is a decimal digit
+func isSQLDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isSQLIdentChar checks if c can be part of an identifier
+func isSQLIdentChar(c byte) bool {
+	return c == '_' || isSQLDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
@@ -0,0 +1,79 @@
+//go:build !aloig_nodebug
+
+package aloig
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The queries are logged at debug level, compiled out by aloig_nodebug
+
+// TestWrapSQLConnector tests that queries are logged with context fields, duration and errors
+func TestWrapSQLConnector(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.DebugLevel)
+	db := sql.OpenDB(WrapSQLConnector(fakeSQLConnector{}, SQLOptions{Logger: logger, SlowThreshold: time.Millisecond}))
+	defer db.Close()
+
+	ctx := WithTraceID(context.Background(), "trace-sql")
+	if _, err := db.ExecContext(ctx, "UPDATE users SET name = 'alice' WHERE id = $1", 42); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := buf.String()
+	for _, expected := range []string{"level=debug", `msg="sql query"`, `query="UPDATE users SET name = ? WHERE id = $1"`, "args=1", "duration_ms=", "trace_id=trace-sql"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "alice") || strings.Contains(output, "=42") {
+		t.Errorf("Expected values to be redacted, got: %s", output)
+	}
+
+	buf.Reset()
+	if _, err := db.ExecContext(ctx, "SELECT fail"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if output := buf.String(); !strings.Contains(output, "level=error") || !strings.Contains(output, `error="syntax error"`) {
+		t.Errorf("Expected an error entry, got: %s", output)
+	}
+
+	buf.Reset()
+	if _, err := db.ExecContext(ctx, "SELECT slow"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output := buf.String(); !strings.Contains(output, "level=warning") || !strings.Contains(output, `msg="slow sql query"`) {
+		t.Errorf("Expected a slow query warning, got: %s", output)
+	}
+
+	// Queries fall back to prepared statements
+	buf.Reset()
+	rows, err := db.QueryContext(ctx, "SELECT id FROM users WHERE id = ?", 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows.Close()
+	if output := buf.String(); !strings.Contains(output, `query="SELECT id FROM users WHERE id = ?"`) || !strings.Contains(output, "trace_id=trace-sql") {
+		t.Errorf("Expected the prepared query to be logged, got: %s", output)
+	}
+}
+
+// TestWrapSQLConnectorParams tests that the parameters are logged when
+// enabled
+func TestWrapSQLConnectorParams(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.DebugLevel)
+	db := sql.OpenDB(WrapSQLConnector(fakeSQLConnector{}, SQLOptions{Logger: logger, LogParams: true}))
+	defer db.Close()
+
+	if _, err := db.ExecContext(context.Background(), "UPDATE users SET password = $1 WHERE id = $2", "hunter2", 42); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := buf.String()
+	if strings.Contains(output, "hunter2") || !strings.Contains(output, "params=") || !strings.Contains(output, "42") {
+		t.Errorf("Expected the parameters with the password redacted, got: %s", output)
+	}
+}
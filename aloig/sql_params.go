@@ -0,0 +1,311 @@
+package aloig
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRedactedColumns are the column names whose bound values are logged
+// as [REDACTED], matched as case-insensitive substrings of the column names
+var DefaultRedactedColumns = []string{"password", "passwd", "secret", "token", "api_key", "card_number", "cvv", "ssn", "email", "phone"}
+
+// SQLParamRedaction selects the bind parameters logged as [REDACTED] when
+// SQLOptions.LogParams is set
+type SQLParamRedaction struct {
+	// All redacts every parameter, only logging their types
+	All bool
+
+	// Positions are the 1-based positions of the redacted parameters, e.g.
+	// 2 for $2 or the second ?
+	Positions []int
+
+	// Columns redacts the parameters compared with, assigned to or inserted
+	// into the columns whose name contains one of them, and the named
+	// parameters (:email, @email) whose name does. DefaultRedactedColumns is
+	// used when nil.
+	Columns []string
+}
+
+// sqlParams returns the bind parameters of query as logged: their values,
+// or [REDACTED] as selected by the redaction
+func sqlParams(query string, args []driver.NamedValue, redaction SQLParamRedaction) []interface{} {
+	columns := redaction.Columns
+	if columns == nil {
+		columns = DefaultRedactedColumns
+	}
+	var byOrdinal map[int]string
+	var byName map[string]string
+	if !redaction.All && len(columns) > 0 {
+		byOrdinal, byName = sqlParamColumns(query)
+	}
+
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		column := byOrdinal[arg.Ordinal]
+		if arg.Name != "" {
+			column = byName[strings.ToLower(arg.Name)]
+		}
+		if redaction.All || containsInt(redaction.Positions, arg.Ordinal) ||
+			sqlColumnRedacted(column, columns) || sqlColumnRedacted(arg.Name, columns) {
+			params[i] = fmt.Sprintf("%s (%T)", RedactedValue, arg.Value)
+			continue
+		}
+		params[i] = sqlParamValue(arg.Value)
+	}
+	return params
+}
+
+// sqlParamValue returns the logged form of a parameter value
+func sqlParamValue(value driver.Value) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return fmt.Sprintf("[%d bytes]", len(v))
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// sqlColumnRedacted checks if column contains one of the redacted names
+func sqlColumnRedacted(column string, redacted []string) bool {
+	if column == "" {
+		return false
+	}
+	column = strings.ToLower(column)
+	for _, name := range redacted {
+		if strings.Contains(column, strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt checks if values contains v
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlTokenKind is the kind of a token of a query
+type sqlTokenKind int
+
+const (
+	sqlIdent sqlTokenKind = iota
+	sqlPlaceholder
+	sqlOperator
+	sqlPunct
+	sqlLiteral
+)
+
+// sqlToken is a token of a query. Identifiers are lowercased and unquoted,
+// keeping the last part of the qualified ones (u.email is email).
+// Placeholders have an ordinal ($2, the second ?) or a name (:email).
+type sqlToken struct {
+	kind    sqlTokenKind
+	text    string
+	ordinal int
+}
+
+// sqlParamColumns guesses the column of each placeholder of query, by
+// ordinal and by lowercased name, from the comparisons (email = $1,
+// name LIKE ?), IN lists and INSERT column lists
+func sqlParamColumns(query string) (map[int]string, map[string]string) {
+	tokens := tokenizeSQL(query)
+	byOrdinal := make(map[int]string)
+	byName := make(map[string]string)
+
+	// paren is an open parenthesis: the column of its IN list, or the index
+	// of the current item of an INSERT VALUES row
+	type paren struct {
+		column string
+		values bool
+		item   int
+	}
+	var parens []paren
+	var insertColumns []string
+	collecting, afterValues := false, false
+
+	for i, token := range tokens {
+		switch {
+		case token.kind == sqlPunct && token.text == "(":
+			p := paren{}
+			switch {
+			case i >= 2 && tokens[i-1].kind == sqlIdent && tokens[i-2].text == "into":
+				collecting, insertColumns = true, nil
+			case afterValues && len(parens) == 0:
+				p.values = true
+			case i >= 2 && tokens[i-1].text == "in" && tokens[i-2].kind == sqlIdent:
+				p.column = tokens[i-2].text
+			case i >= 3 && tokens[i-1].text == "in" && tokens[i-2].text == "not" && tokens[i-3].kind == sqlIdent:
+				p.column = tokens[i-3].text
+			}
+			parens = append(parens, p)
+		case token.kind == sqlPunct && token.text == ")":
+			if len(parens) > 0 {
+				parens = parens[:len(parens)-1]
+			}
+			if len(parens) == 0 {
+				collecting = false
+			}
+		case token.kind == sqlPunct && token.text == ",":
+			if len(parens) > 0 && parens[len(parens)-1].values {
+				parens[len(parens)-1].item++
+			}
+		case token.kind == sqlIdent && token.text == "values":
+			afterValues = true
+		case token.kind == sqlIdent && collecting && len(parens) == 1:
+			insertColumns = append(insertColumns, token.text)
+		case token.kind == sqlPlaceholder:
+			column := sqlComparedColumn(tokens[:i])
+			if column == "" && len(parens) > 0 {
+				p := parens[len(parens)-1]
+				column = p.column
+				if p.values && len(parens) == 1 && p.item < len(insertColumns) {
+					column = insertColumns[p.item]
+				}
+			}
+			if column == "" {
+				continue
+			}
+			if token.ordinal > 0 {
+				byOrdinal[token.ordinal] = column
+			} else {
+				byName[token.text] = column
+			}
+		}
+	}
+	return byOrdinal, byName
+}
+
+// sqlComparedColumn returns the column compared with or assigned to the
+// placeholder following tokens, if any
+func sqlComparedColumn(tokens []sqlToken) string {
+	i := len(tokens) - 1
+	if i < 1 {
+		return ""
+	}
+	switch {
+	case tokens[i].kind == sqlOperator:
+	case tokens[i].text == "like" || tokens[i].text == "ilike":
+		if tokens[i-1].text == "not" {
+			i--
+		}
+	default:
+		return ""
+	}
+	if i < 1 || tokens[i-1].kind != sqlIdent {
+		return ""
+	}
+	return tokens[i-1].text
+}
+
+// tokenizeSQL splits query into tokens, skipping the comments
+func tokenizeSQL(query string) []sqlToken {
+	var tokens []sqlToken
+	positional := 0
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '\'':
+			j := i + 1
+			for j < len(query) {
+				if query[j] == '\'' {
+					if j+1 < len(query) && query[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlLiteral})
+			i = j
+		case c == '"' || c == '`':
+			j := strings.IndexByte(query[i+1:], c)
+			if j < 0 {
+				j = len(query) - i - 1
+			}
+			tokens = appendSQLIdent(tokens, query[i+1:i+1+j])
+			i += j + 2
+		case c == '?':
+			positional++
+			tokens = append(tokens, sqlToken{kind: sqlPlaceholder, ordinal: positional})
+			i++
+		case c == '$' && i+1 < len(query) && isSQLDigit(query[i+1]):
+			j := i + 1
+			for j < len(query) && isSQLDigit(query[j]) {
+				j++
+			}
+			ordinal, _ := strconv.Atoi(query[i+1 : j])
+			tokens = append(tokens, sqlToken{kind: sqlPlaceholder, ordinal: ordinal})
+			i = j
+		case (c == ':' || c == '@') && i+1 < len(query) && isSQLIdentChar(query[i+1]) && !isSQLDigit(query[i+1]) &&
+			(c == '@' || i == 0 || query[i-1] != ':'):
+			j := i + 1
+			for j < len(query) && isSQLIdentChar(query[j]) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlPlaceholder, text: strings.ToLower(query[i+1 : j])})
+			i = j
+		case isSQLDigit(c):
+			j := i
+			for j < len(query) && (isSQLDigit(query[j]) || query[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlLiteral})
+			i = j
+		case isSQLIdentChar(c):
+			j := i + 1
+			for j < len(query) && isSQLIdentChar(query[j]) {
+				j++
+			}
+			tokens = appendSQLIdent(tokens, query[i:j])
+			i = j
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: string(c)})
+			i++
+		case c == '=' || c == '<' || c == '>' || c == '!':
+			j := i + 1
+			for j < len(query) && (query[j] == '=' || query[j] == '>') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlOperator, text: query[i:j]})
+			i = j
+		case c == '.':
+			// Qualified name: keep the last part only
+			if len(tokens) > 0 && tokens[len(tokens)-1].kind == sqlIdent {
+				tokens = tokens[:len(tokens)-1]
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+// appendSQLIdent appends the identifier ident to tokens
+func appendSQLIdent(tokens []sqlToken, ident string) []sqlToken {
+	return append(tokens, sqlToken{kind: sqlIdent, text: strings.ToLower(ident)})
+}
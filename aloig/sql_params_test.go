@@ -0,0 +1,64 @@
+package aloig
+
+import (
+	"database/sql/driver"
+	"testing"
+)
+
+// TestSQLParamColumns tests the columns guessed for the placeholders
+func TestSQLParamColumns(t *testing.T) {
+	tests := []struct {
+		query     string
+		byOrdinal map[int]string
+		byName    map[string]string
+	}{
+		{"SELECT * FROM users u WHERE u.email = $1 AND age >= $2", map[int]string{1: "email", 2: "age"}, nil},
+		{`UPDATE "users" SET "password_hash" = ?, name = 'x' WHERE id = ?`, map[int]string{1: "password_hash", 2: "id"}, nil},
+		{"INSERT INTO public.users (email, name, phone) VALUES ($1, $2, $3), ($4, $5, $6)", map[int]string{1: "email", 2: "name", 3: "phone"}, nil},
+		{"SELECT id FROM users WHERE name NOT LIKE ? AND token IN (?, ?) -- email = ?", map[int]string{1: "name", 2: "token", 3: "token"}, nil},
+		{"SELECT id FROM users WHERE email = :email AND created_at > :since::timestamp", nil, map[string]string{"email": "email", "since": "created_at"}},
+		{"SELECT lower($1)", nil, nil},
+	}
+	for _, tt := range tests {
+		byOrdinal, byName := sqlParamColumns(tt.query)
+		for ordinal, column := range tt.byOrdinal {
+			if byOrdinal[ordinal] != column {
+				t.Errorf("%s: expected $%d to be %s, got %v", tt.query, ordinal, column, byOrdinal)
+			}
+		}
+		for name, column := range tt.byName {
+			if byName[name] != column {
+				t.Errorf("%s: expected :%s to be %s, got %v", tt.query, name, column, byName)
+			}
+		}
+		if len(tt.byOrdinal) == 0 && len(tt.byName) == 0 && (len(byOrdinal) > 0 || len(byName) > 0) {
+			t.Errorf("%s: expected no columns, got %v and %v", tt.query, byOrdinal, byName)
+		}
+	}
+}
+
+// TestSQLParams tests the redaction of all, positional and column-matched
+// parameters
+func TestSQLParams(t *testing.T) {
+	query := "UPDATE users SET email = $1, name = $2 WHERE id = $3"
+	args := []driver.NamedValue{{Ordinal: 1, Value: "a@example.com"}, {Ordinal: 2, Value: "Alice"}, {Ordinal: 3, Value: int64(42)}}
+
+	params := sqlParams(query, args, SQLParamRedaction{})
+	if params[0] != "[REDACTED] (string)" || params[1] != "Alice" || params[2] != int64(42) {
+		t.Errorf("Expected the email to be redacted by default, got %v", params)
+	}
+	params = sqlParams(query, args, SQLParamRedaction{Positions: []int{2}, Columns: []string{}})
+	if params[0] != "a@example.com" || params[1] != "[REDACTED] (string)" {
+		t.Errorf("Expected the second parameter only to be redacted, got %v", params)
+	}
+	params = sqlParams(query, args, SQLParamRedaction{All: true})
+	if params[1] != "[REDACTED] (string)" || params[2] != "[REDACTED] (int64)" {
+		t.Errorf("Expected every parameter to be redacted, got %v", params)
+	}
+
+	named := []driver.NamedValue{{Name: "api_key", Ordinal: 1, Value: "k-123"}, {Name: "blob", Ordinal: 2, Value: []byte{1, 2, 3}}}
+	params = sqlParams("SELECT 1 WHERE :api_key = key AND data = :blob", named, SQLParamRedaction{})
+	if params[0] != "[REDACTED] (string)" || params[1] != "[3 bytes]" {
+		t.Errorf("Expected the named secret to be redacted and bytes summarized, got %v", params)
+	}
+}
@@ -0,0 +1,177 @@
+package aloig
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSQLConnector is a connector to an in-memory database that fails the
+// queries containing "fail" and sleeps on the ones containing "slow"
+type fakeSQLConnector struct{}
+
+func (fakeSQLConnector) Connect(context.Context) (driver.Conn, error) { return fakeSQLConn{}, nil }
+func (fakeSQLConnector) Driver() driver.Driver                        { return nil }
+
+type fakeSQLConn struct{}
+
+func (fakeSQLConn) Prepare(query string) (driver.Stmt, error) { return fakeSQLStmt{query: query}, nil }
+func (fakeSQLConn) Close() error                              { return nil }
+func (fakeSQLConn) Begin() (driver.Tx, error)                 { return fakeSQLTx{}, nil }
+
+func (fakeSQLConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "fail") {
+		return nil, errors.New("syntax error")
+	}
+	if strings.Contains(query, "slow") {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeSQLStmt struct {
+	query string
+}
+
+func (fakeSQLStmt) Close() error  { return nil }
+func (fakeSQLStmt) NumInput() int { return -1 }
+func (fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) { return fakeSQLRows{}, nil }
+
+type fakeSQLTx struct{}
+
+func (fakeSQLTx) Commit() error   { return nil }
+func (fakeSQLTx) Rollback() error { return nil }
+
+// fakeSQLConverterConnector connects to a database whose connections
+// check the fakeSQLID arguments, with statements converting the others to
+// strings
+type fakeSQLConverterConnector struct {
+	stmt *fakeSQLConverterStmt
+}
+
+func (c fakeSQLConverterConnector) Connect(context.Context) (driver.Conn, error) {
+	return fakeSQLConverterConn{stmt: c.stmt}, nil
+}
+func (fakeSQLConverterConnector) Driver() driver.Driver { return nil }
+
+type fakeSQLConverterConn struct {
+	fakeSQLConn
+	stmt *fakeSQLConverterStmt
+}
+
+// fakeSQLID is an argument converted by fakeSQLConverterConn
+type fakeSQLID struct{}
+
+func (c fakeSQLConverterConn) Prepare(query string) (driver.Stmt, error) { return c.stmt, nil }
+func (fakeSQLConverterConn) CheckNamedValue(value *driver.NamedValue) error {
+	if _, ok := value.Value.(fakeSQLID); ok {
+		value.Value = "id"
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+type fakeSQLConverterStmt struct {
+	fakeSQLStmt
+	args []driver.Value
+}
+
+func (*fakeSQLConverterStmt) NumInput() int { return 2 }
+func (s *fakeSQLConverterStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.args = args
+	return driver.RowsAffected(1), nil
+}
+func (*fakeSQLConverterStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return fakeSQLStringConverter{}
+}
+
+type fakeSQLStringConverter struct{}
+
+func (fakeSQLStringConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	return fmt.Sprint(v), nil
+}
+
+type fakeSQLRows struct{}
+
+func (fakeSQLRows) Columns() []string              { return []string{"id"} }
+func (fakeSQLRows) Close() error                   { return nil }
+func (fakeSQLRows) Next(dest []driver.Value) error { return io.EOF }
+
+// TestRedactSQL tests that literals are removed from queries
+func TestRedactSQL(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM t WHERE a = 'it''s' AND b = 3.14":     "SELECT * FROM t WHERE a = ? AND b = ?",
+		`SELECT "col1", table2.id FROM table2 WHERE x = $2`:  `SELECT "col1", table2.id FROM table2 WHERE x = $2`,
+		"INSERT INTO t (a, b) VALUES (:name, @p1)":           "INSERT INTO t (a, b) VALUES (:name, @p1)",
+		"SELECT 1 LIMIT 10":                                  "SELECT ? LIMIT ?",
+		`SELECT * FROM t WHERE a = 'it\'s secret' AND b = 1`: "SELECT * FROM t WHERE a = ? AND b = ?",
+		`SELECT * FROM t WHERE a = 'secret\\' AND b = 1`:     "SELECT * FROM t WHERE a = ? AND b = ?",
+		"SELECT $$it's secret$$, $1":                         "SELECT ?, $1",
+		"SELECT $body$secret $$ text$body$ FROM t":           "SELECT ? FROM t",
+		"SELECT $tag$unterminated secret":                    "SELECT ?",
+		"SELECT 'unterminated \\":                            "SELECT ?",
+	}
+	for query, expected := range tests {
+		if redacted := RedactSQL(query); redacted != expected {
+			t.Errorf("RedactSQL(%q) = %q, expected %q", query, redacted, expected)
+		}
+	}
+}
+
+// TestWrapSQLConnectorBeginTx tests that the transaction options the driver
+// can't honor fail like without the wrapper
+func TestWrapSQLConnectorBeginTx(t *testing.T) {
+	for _, connector := range []driver.Connector{fakeSQLConnector{}, WrapSQLConnector(fakeSQLConnector{}, SQLOptions{})} {
+		db := sql.OpenDB(connector)
+
+		tx, err := db.BeginTx(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Expected a transaction, got %v", err)
+		}
+		tx.Rollback()
+
+		for _, opts := range []*sql.TxOptions{{Isolation: sql.LevelSerializable}, {ReadOnly: true}} {
+			if _, err := db.BeginTx(context.Background(), opts); err == nil {
+				t.Errorf("Expected an error for %+v with %T", *opts, connector)
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := db.BeginTx(ctx, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the context error with %T, got %v", connector, err)
+		}
+		db.Close()
+	}
+}
+
+// TestWrapSQLConnectorConverters tests that the arguments are checked and
+// converted by the driver like without the wrapper
+func TestWrapSQLConnectorConverters(t *testing.T) {
+	stmt := &fakeSQLConverterStmt{}
+	connector := fakeSQLConverterConnector{stmt: stmt}
+	for _, connector := range []driver.Connector{connector, WrapSQLConnector(connector, SQLOptions{})} {
+		db := sql.OpenDB(connector)
+		prepared, err := db.Prepare("UPDATE t SET a = ? WHERE b = ?")
+		if err != nil {
+			t.Fatalf("Expected a statement, got %v", err)
+		}
+		if _, err := prepared.Exec(fakeSQLID{}, 7); err != nil {
+			t.Fatalf("Expected the statement to run, got %v", err)
+		}
+		if !reflect.DeepEqual(stmt.args, []driver.Value{"id", "7"}) {
+			t.Errorf("Expected the converted arguments with %T, got %v", connector, stmt.args)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,148 @@
+package aloig
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// StackTraceField is the field holding the stack trace of error entries
+const StackTraceField = "stack_trace"
+
+// maxStackDepth is the maximum number of frames written in stack traces
+const maxStackDepth = 32
+
+// stackBuffer holds the program counters and the text of a stack trace
+// being captured
+type stackBuffer struct {
+	// pcs leaves room for the logrus and aloig frames preceding the caller
+	pcs  [2 * maxStackDepth]uintptr
+	text bytes.Buffer
+}
+
+// stackPool reuses the buffers of stack captures
+var stackPool = sync.Pool{
+	New: func() interface{} {
+		return &stackBuffer{}
+	},
+}
+
+// captureStack returns the stack trace of the caller of the logging call,
+// skipping skip more frames, in the format of runtime.Stack without the
+// goroutine header and the arguments. Only the program counters are
+// captured, the frames are resolved as they're written and the buffers are
+// pooled, so the cost is bounded by maxStackDepth.
+func captureStack(skip int) string {
+	stack := stackPool.Get().(*stackBuffer)
+	defer func() {
+		stack.text.Reset()
+		stackPool.Put(stack)
+	}()
+
+	frames := runtime.CallersFrames(stack.pcs[:runtime.Callers(2, stack.pcs[:])])
+	found := false
+	depth := 0
+	for depth < maxStackDepth {
+		frame, more := frames.Next()
+		if !found && !isLoggingFrame(frame) {
+			found = true
+		}
+		if found {
+			if skip > 0 {
+				skip--
+			} else {
+				writeFrame(&stack.text, frame)
+				depth++
+			}
+		}
+		if !more {
+			break
+		}
+	}
+
+	return stack.text.String()
+}
+
+// errorStack returns the stack trace recorded by err or the errors it wraps,
+// in the format of captureStack, or an empty string if none records one.
+// Errors record their stack with a StackTrace method returning program
+// counters, like those of github.com/pkg/errors. The stack of the innermost
+// error is used, the closest to the failure; among the errors joined by an
+// Unwrap() []error method, the first one recording a stack is used.
+func errorStack(err error) string {
+	pcs := errorStackPCs(err)
+	if len(pcs) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	frames := runtime.CallersFrames(pcs)
+	for depth := 0; depth < maxStackDepth; depth++ {
+		frame, more := frames.Next()
+		writeFrame(&buf, frame)
+		if !more {
+			break
+		}
+	}
+	return buf.String()
+}
+
+// errorStackPCs returns the program counters of the stack recorded by the
+// innermost error of err recording one
+func errorStackPCs(err error) []uintptr {
+	if err == nil {
+		return nil
+	}
+
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range multi.Unwrap() {
+			if pcs := errorStackPCs(e); pcs != nil {
+				return pcs
+			}
+		}
+	} else if pcs := errorStackPCs(errors.Unwrap(err)); pcs != nil {
+		return pcs
+	}
+	return stackTracePCs(err)
+}
+
+// stackTracePCs returns the program counters returned by the StackTrace
+// method of err, if it has one returning a slice of uintptr, such as the
+// errors.StackTrace of github.com/pkg/errors, without depending on the
+// package
+func stackTracePCs(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+	signature := method.Type()
+	if signature.NumIn() != 0 || signature.NumOut() != 1 ||
+		signature.Out(0).Kind() != reflect.Slice || signature.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	trace := method.Call(nil)[0]
+	if trace.Len() == 0 {
+		return nil
+	}
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs
+}
+
+// writeFrame writes frame to buf as "function()\n\tfile:line"
+func writeFrame(buf *bytes.Buffer, frame runtime.Frame) {
+	if buf.Len() > 0 {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(frame.Function)
+	buf.WriteString("()\n\t")
+	buf.WriteString(frame.File)
+	buf.WriteByte(':')
+	buf.WriteString(strconv.Itoa(frame.Line))
+}
@@ -0,0 +1,133 @@
+package aloig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newStackLogger returns a logger writing JSON with or without stack traces
+func newStackLogger(stackTraces bool) (*logrusLogger, *bytes.Buffer) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetFormatter(&CallerJSONFormatter{JSONFormatter: &logrus.JSONFormatter{}, StackTraces: stackTraces})
+	return logger, buf
+}
+
+// stackTraceOf returns the stack trace of the JSON entry in buf
+func stackTraceOf(t *testing.T, buf *bytes.Buffer) (string, bool) {
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", buf.String(), err)
+	}
+	stack, ok := entry[StackTraceField].(string)
+	return stack, ok
+}
+
+// TestStackTrace tests that error entries carry the stack of the caller
+func TestStackTrace(t *testing.T) {
+	logger, buf := newStackLogger(true)
+
+	logger.Error("failed")
+	stack, ok := stackTraceOf(t, buf)
+	if !ok {
+		t.Fatalf("Expected a stack trace, got: %s", buf.String())
+	}
+	if !strings.HasPrefix(stack, "github.com/aloi-tech/aloig_go/aloig.TestStackTrace()\n\t") {
+		t.Errorf("Expected the stack trace to start at the caller, got: %s", stack)
+	}
+	if strings.Contains(stack, "sirupsen/logrus") || strings.Contains(stack, "aloig.(*logrusLogger)") {
+		t.Errorf("Expected no logging frames in the stack trace, got: %s", stack)
+	}
+	if frames := strings.Count(stack, "\n\t"); frames > maxStackDepth {
+		t.Errorf("Expected at most %d frames, got %d", maxStackDepth, frames)
+	}
+
+	buf.Reset()
+	logger.Info("done")
+	if _, ok := stackTraceOf(t, buf); ok {
+		t.Errorf("Expected no stack trace for info entries, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.WithField(StackTraceField, "main.main()").Error("recovered")
+	if stack, _ := stackTraceOf(t, buf); stack != "main.main()" {
+		t.Errorf("Expected the stack trace field to be kept, got: %s", stack)
+	}
+}
+
+// TestStackTraceDisabled tests that stack traces are opt-in
+func TestStackTraceDisabled(t *testing.T) {
+	logger, buf := newStackLogger(false)
+
+	logger.Error("failed")
+	if _, ok := stackTraceOf(t, buf); ok {
+		t.Errorf("Expected no stack trace, got: %s", buf.String())
+	}
+}
+
+// BenchmarkCaptureStack measures the cost of capturing a stack trace
+func BenchmarkCaptureStack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		captureStack(0)
+	}
+}
+
+// stackFrame and stackTrace mirror the types of github.com/pkg/errors
+type stackFrame uintptr
+type stackTrace []stackFrame
+
+// tracedError records the stack where it was created, like the errors of
+// github.com/pkg/errors
+type tracedError struct {
+	msg   string
+	stack []uintptr
+}
+
+func (e *tracedError) Error() string { return e.msg }
+func (e *tracedError) StackTrace() stackTrace {
+	trace := make(stackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		trace[i] = stackFrame(pc)
+	}
+	return trace
+}
+
+// newTracedError returns an error recording the stack of its caller
+func newTracedError(msg string) error {
+	pcs := make([]uintptr, maxStackDepth)
+	return &tracedError{msg: msg, stack: pcs[:runtime.Callers(1, pcs)]}
+}
+
+// TestStackTraceFromError tests that the stack recorded by the innermost
+// error, or by a joined error, is written instead of the logging call stack
+func TestStackTraceFromError(t *testing.T) {
+	logger, buf := newStackLogger(true)
+	origin := "github.com/aloi-tech/aloig_go/aloig.newTracedError()\n\t"
+
+	errs := map[string]error{
+		"traced":  newTracedError("query failed"),
+		"wrapped": fmt.Errorf("load orders: %w", newTracedError("query failed")),
+		"joined":  &joinedError{errs: []error{errors.New("plain"), newTracedError("query failed")}},
+	}
+	for name, err := range errs {
+		buf.Reset()
+		logger.WithError(err).Error("failed")
+		if stack, _ := stackTraceOf(t, buf); !strings.HasPrefix(stack, origin) {
+			t.Errorf("%s: expected the stack of the error, got: %s", name, stack)
+		}
+	}
+
+	buf.Reset()
+	logger.WithError(fmt.Errorf("load orders: %w", io.EOF)).Error("failed")
+	if stack, _ := stackTraceOf(t, buf); !strings.HasPrefix(stack, "github.com/aloi-tech/aloig_go/aloig.TestStackTraceFromError()\n\t") {
+		t.Errorf("Expected the stack of the caller without error stack, got: %s", stack)
+	}
+}
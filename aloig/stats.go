@@ -0,0 +1,170 @@
+package aloig
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatsExpvarName is the name of the expvar variable published by
+// PublishStats
+const StatsExpvarName = "aloig"
+
+// LoggerStats are the statistics of the loggers created with NewLogger
+type LoggerStats struct {
+	// Entries is the number of entries written, by level
+	Entries map[string]uint64 `json:"entries"`
+
+	// Bytes is the size of the entries written
+	Bytes uint64 `json:"bytes"`
+
+	// Suppressed is the number of entries suppressed by the deduplication
+	Suppressed uint64 `json:"suppressed"`
+
+	// Sampled is the number of entries dropped by the adaptive sampling
+	Sampled uint64 `json:"sampled"`
+
+	// Dropped is the number of entries dropped by the async writers and
+	// hooks
+	Dropped uint64 `json:"dropped"`
+
+	// QueueDepth is the number of entries waiting in the async writers and
+	// hooks
+	QueueDepth int `json:"queue_depth"`
+
+	// QueueCapacity is the number of entries the async writers and hooks
+	// can buffer
+	QueueCapacity int `json:"queue_capacity"`
+
+	// HookErrors is the number of hook failures, e.g. of the Sentry hook
+	HookErrors uint64 `json:"hook_errors"`
+}
+
+// stats holds the counters of the loggers created with NewLogger
+var stats struct {
+	entries    [logrus.TraceLevel + 1]uint64
+	bytes      uint64
+	suppressed uint64
+	sampled    uint64
+	hookErrors uint64
+}
+
+// Stats returns the statistics of the loggers created with NewLogger since
+// the process started
+func Stats() LoggerStats {
+	s := LoggerStats{
+		Entries:    make(map[string]uint64, len(logrus.AllLevels)),
+		Bytes:      atomic.LoadUint64(&stats.bytes),
+		Suppressed: atomic.LoadUint64(&stats.suppressed),
+		Sampled:    atomic.LoadUint64(&stats.sampled),
+		HookErrors: atomic.LoadUint64(&stats.hookErrors),
+	}
+	for _, level := range logrus.AllLevels {
+		s.Entries[level.String()] = atomic.LoadUint64(&stats.entries[level])
+	}
+
+	asyncMu.Lock()
+	for _, w := range asyncWriters {
+		s.Dropped += w.Dropped()
+		s.QueueDepth += len(w.queue)
+		s.QueueCapacity += cap(w.queue)
+	}
+	asyncMu.Unlock()
+
+	asyncHooksMu.Lock()
+	for _, h := range asyncHooks {
+		depth, capacity := h.queued()
+		s.Dropped += h.Dropped()
+		s.QueueDepth += depth
+		s.QueueCapacity += capacity
+	}
+	asyncHooksMu.Unlock()
+	return s
+}
+
+var publishStatsOnce sync.Once
+
+// PublishStats publishes the statistics as the "aloig" expvar variable, so
+// they are served by /debug/vars. It can be called several times.
+func PublishStats() {
+	publishStatsOnce.Do(func() {
+		expvar.Publish(StatsExpvarName, expvar.Func(func() interface{} {
+			return Stats()
+		}))
+	})
+}
+
+// statsFormatter counts the entries and bytes written
+type statsFormatter struct {
+	logrus.Formatter
+}
+
+// Format formats the entry and counts it unless nothing is written
+func (f *statsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	serialized, err := f.Formatter.Format(entry)
+	if err == nil && len(serialized) > 0 {
+		if int(entry.Level) < len(stats.entries) {
+			atomic.AddUint64(&stats.entries[entry.Level], 1)
+		}
+		atomic.AddUint64(&stats.bytes, uint64(len(serialized)))
+	}
+	return serialized, err
+}
+
+// statsHook counts the failures of a hook and passes them to onError,
+// recording the writes of the hooks writing to a sink
+type statsHook struct {
+	logrus.Hook
+
+	sink    *sinkHealth
+	onError func(*HookError)
+}
+
+// Fire fires the hook, counting its failure. The failure is only returned to
+// logrus, which prints it to stderr, when there is no onError.
+func (hook statsHook) Fire(entry *logrus.Entry) error {
+	err := hook.Hook.Fire(entry)
+	if hook.sink != nil {
+		hook.sink.record(err)
+	}
+	if err == nil {
+		return nil
+	}
+	atomic.AddUint64(&stats.hookErrors, 1)
+	if hook.onError == nil {
+		return err
+	}
+	hook.onError(&HookError{Hook: fmt.Sprintf("%T", hook.Hook), Level: entry.Level, Message: entry.Message, Err: err})
+	return nil
+}
+
+// countHookErrors replaces the hooks of logger with hooks counting their
+// failures and passing them to onError (see statsHook), and returns the
+// sinks of the hooks writing to one
+func countHookErrors(logger *logrus.Logger, onError func(*HookError)) []*sinkHealth {
+	hooks := make(logrus.LevelHooks, len(logger.Hooks))
+	sinks := make(map[sinkHook]*sinkHealth)
+	var hookSinks []*sinkHealth
+	for level, levelHooks := range logger.Hooks {
+		for _, hook := range levelHooks {
+			if _, ok := hook.(statsHook); ok {
+				hooks[level] = append(hooks[level], hook)
+				continue
+			}
+			wrapped := statsHook{Hook: hook, onError: onError}
+			if s, ok := hook.(sinkHook); ok {
+				if sinks[s] == nil {
+					sinks[s] = &sinkHealth{name: s.sinkName()}
+					hookSinks = append(hookSinks, sinks[s])
+				}
+				wrapped.sink = sinks[s]
+			}
+			hooks[level] = append(hooks[level], wrapped)
+		}
+	}
+	logger.ReplaceHooks(hooks)
+	return hookSinks
+}
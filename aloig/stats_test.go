@@ -0,0 +1,64 @@
+package aloig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// failingHook is a hook that always fails
+type failingHook struct{}
+
+func (failingHook) Levels() []logrus.Level         { return logrus.AllLevels }
+func (failingHook) Fire(entry *logrus.Entry) error { return errors.New("hook failed") }
+
+// TestStats tests that the written entries, bytes and hook failures are
+// counted
+func TestStats(t *testing.T) {
+	before := Stats()
+
+	logger := NewLogger(Config{Environment: "prod", Level: logrus.InfoLevel})
+	buf := &bytes.Buffer{}
+	logrusInstance := logger.(*logrusLogger).logger
+	logrusInstance.SetOutput(buf)
+	logrusInstance.SetReportCaller(false)
+	logrusInstance.AddHook(failingHook{})
+	countHookErrors(logrusInstance, nil)
+
+	logger.Info("one")
+	logger.Warn("two")
+	logger.Debug("disabled")
+
+	after := Stats()
+	if after.Entries["info"]-before.Entries["info"] != 1 || after.Entries["warning"]-before.Entries["warning"] != 1 {
+		t.Errorf("Expected one info and one warning entry, got %v then %v", before.Entries, after.Entries)
+	}
+	if after.Bytes-before.Bytes != uint64(buf.Len()) {
+		t.Errorf("Expected %d bytes, got %d", buf.Len(), after.Bytes-before.Bytes)
+	}
+	if after.HookErrors-before.HookErrors != 2 {
+		t.Errorf("Expected two hook failures, got %d", after.HookErrors-before.HookErrors)
+	}
+}
+
+// TestPublishStats tests that the statistics are published with expvar
+func TestPublishStats(t *testing.T) {
+	PublishStats()
+	PublishStats()
+
+	variable := expvar.Get(StatsExpvarName)
+	if variable == nil {
+		t.Fatal("Expected the statistics to be published")
+	}
+	var published LoggerStats
+	if err := json.Unmarshal([]byte(variable.String()), &published); err != nil {
+		t.Fatalf("Failed to parse the published statistics: %v", err)
+	}
+	if _, ok := published.Entries["error"]; !ok {
+		t.Errorf("Expected the entries by level, got %s", variable.String())
+	}
+}
@@ -0,0 +1,94 @@
+package aloig
+
+import (
+	"context"
+	"io"
+	stdlog "log"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StdLogger returns a standard library logger forwarding each line to the
+// singleton logger at the given level, for libraries that only accept a
+// *log.Logger such as http.Server.ErrorLog
+func StdLogger(level logrus.Level) *stdlog.Logger {
+	return stdlog.New(&levelWriter{level: level}, "", 0)
+}
+
+// NewStdLogger returns a standard library logger forwarding each line to
+// logger at the given level
+func NewStdLogger(logger Logger, level logrus.Level) *stdlog.Logger {
+	return stdlog.New(NewLevelWriter(logger, level), "", 0)
+}
+
+// NewLevelWriter returns an io.Writer logging each line written to it as a
+// separate entry of logger at the given level. A nil logger writes to the
+// singleton logger.
+func NewLevelWriter(logger Logger, level logrus.Level) io.Writer {
+	return &levelWriter{logger: logger, level: level}
+}
+
+// levelWriter is an io.Writer logging lines at a fixed level
+type levelWriter struct {
+	logger Logger
+	level  logrus.Level
+}
+
+// Write logs every non-empty line of p
+func (w *levelWriter) Write(p []byte) (int, error) {
+	logger := w.logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		logAtLevel(logger, w.level, line)
+	}
+	return len(p), nil
+}
+
+// logAtLevel logs msg with the Logger method matching level
+func logAtLevel(logger Logger, level logrus.Level, msg string) {
+	switch level {
+	case logrus.PanicLevel:
+		logger.Panic(msg)
+	case logrus.FatalLevel:
+		logger.Fatal(msg)
+	case logrus.ErrorLevel:
+		logger.Error(msg)
+	case logrus.WarnLevel:
+		logger.Warn(msg)
+	case logrus.InfoLevel:
+		logger.Info(msg)
+	case logrus.DebugLevel:
+		logger.Debug(msg)
+	default:
+		logger.Trace(msg)
+	}
+}
+
+// logContextAtLevel logs msg with the context fields using the Logger
+// method matching level
+func logContextAtLevel(ctx context.Context, logger Logger, level logrus.Level, msg string) {
+	switch level {
+	case logrus.PanicLevel:
+		logger.PanicContext(ctx, msg)
+	case logrus.FatalLevel:
+		logger.FatalContext(ctx, msg)
+	case logrus.ErrorLevel:
+		logger.ErrorContext(ctx, msg)
+	case logrus.WarnLevel:
+		logger.WarnContext(ctx, msg)
+	case logrus.InfoLevel:
+		logger.InfoContext(ctx, msg)
+	case logrus.DebugLevel:
+		logger.DebugContext(ctx, msg)
+	default:
+		logger.TraceContext(ctx, msg)
+	}
+}
@@ -0,0 +1,47 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestNewStdLogger tests that standard library log lines are forwarded at the chosen level
+func TestNewStdLogger(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+
+	std := NewStdLogger(logger.WithField("component", "http"), logrus.WarnLevel)
+	std.Printf("http: TLS handshake error from %s", "10.0.0.1")
+	std.Print("first\nsecond")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "level=warning") || !strings.Contains(line, "component=http") {
+			t.Errorf("Expected a warning entry with fields, got: %s", line)
+		}
+	}
+	if !strings.Contains(lines[0], "TLS handshake error from 10.0.0.1") {
+		t.Errorf("Expected the formatted message, got: %s", lines[0])
+	}
+}
+
+// TestStdLoggerSingleton tests that StdLogger writes to the current singleton logger
+func TestStdLoggerSingleton(t *testing.T) {
+	originalLog := log
+	defer func() { log = originalLog }()
+
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	log = logger
+
+	StdLogger(logrus.DebugLevel).Print("filtered")
+	StdLogger(logrus.ErrorLevel).Print("kept")
+
+	output := buf.String()
+	if strings.Contains(output, "filtered") || !strings.Contains(output, "level=error msg=kept") {
+		t.Errorf("Expected only the error entry, got: %s", output)
+	}
+}
@@ -0,0 +1,130 @@
+package aloig
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestSyslogLevelSeverity tests the logrus level to syslog severity mapping
+func TestSyslogLevelSeverity(t *testing.T) {
+	testCases := []struct {
+		level    logrus.Level
+		expected SyslogSeverity
+	}{
+		{logrus.PanicLevel, SyslogSeverityEmergency},
+		{logrus.FatalLevel, SyslogSeverityCritical},
+		{logrus.ErrorLevel, SyslogSeverityError},
+		{logrus.WarnLevel, SyslogSeverityWarning},
+		{logrus.InfoLevel, SyslogSeverityInfo},
+		{logrus.DebugLevel, SyslogSeverityDebug},
+		{logrus.TraceLevel, SyslogSeverityDebug},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			if result := SyslogLevelSeverity(tc.level); result != tc.expected {
+				t.Errorf("Expected severity %d, got %d", tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestSyslogHookUDP tests that entries are delivered as RFC5424 datagrams
+func TestSyslogHookUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	hook, err := NewSyslogHook("udp", conn.LocalAddr().String(), SyslogFacilityLocal0, "test-host", "test-app")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer hook.Close()
+
+	entry := &logrus.Entry{
+		Message: "test message",
+		Level:   logrus.ErrorLevel,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:    logrus.Fields{"trace_id": "abc", "quote": `a"b`},
+	}
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read datagram: %v", err)
+	}
+	msg := string(buf[:n])
+
+	// local0 (16) * 8 + error (3) = 131
+	if !strings.HasPrefix(msg, "<131>1 2024-01-02T03:04:05.000000Z test-host test-app ") {
+		t.Errorf("Unexpected header: %s", msg)
+	}
+	if !strings.Contains(msg, `[aloig@32473 quote="a\"b" trace_id="abc"]`) {
+		t.Errorf("Expected structured data with escaped fields, got: %s", msg)
+	}
+	if !strings.HasSuffix(msg, " test message") {
+		t.Errorf("Expected message at the end, got: %s", msg)
+	}
+}
+
+// TestSyslogHookTCP tests that stream transports use octet-counting framing
+func TestSyslogHookTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	hook, err := NewSyslogHook("tcp", listener.Addr().String(), SyslogFacilityUser, "test-host", "test-app")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entry := &logrus.Entry{Message: "tcp message\n", Level: logrus.InfoLevel, Data: logrus.Fields{}}
+	if err := hook.Fire(entry); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	hook.Close()
+
+	select {
+	case msg := <-received:
+		parts := strings.SplitN(msg, " ", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], "<14>1 ") {
+			t.Errorf("Expected octet-counted frame with PRI 14, got: %s", msg)
+		}
+		if !strings.Contains(msg, " - - tcp message") {
+			t.Errorf("Expected nil structured data and trimmed message, got: %s", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for syslog message")
+	}
+}
+
+// TestNewSyslogHookUnsupportedNetwork tests that unknown networks are rejected
+func TestNewSyslogHookUnsupportedNetwork(t *testing.T) {
+	if _, err := NewSyslogHook("sctp", "localhost:514", SyslogFacilityUser, "", ""); err == nil {
+		t.Error("Expected error for unsupported network")
+	}
+}
@@ -0,0 +1,96 @@
+package aloig
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TemporalComponent is the component field value of Temporal SDK entries
+const TemporalComponent = "temporal"
+
+// temporalFieldNames maps the keys logged by the Temporal SDK to aloig
+// field names
+var temporalFieldNames = map[string]string{
+	"Namespace":    "namespace",
+	"TaskQueue":    "task_queue",
+	"WorkerID":     "worker_id",
+	"WorkflowType": "workflow_type",
+	"WorkflowID":   "workflow_id",
+	"RunID":        "workflow_run_id",
+	"ActivityType": "activity_type",
+	"ActivityID":   "activity_id",
+	"Attempt":      "attempt",
+	"Error":        logrus.ErrorKey,
+}
+
+// TemporalLogger forwards the logs of Temporal workflows, activities and
+// workers to aloig. It implements the Temporal SDK's log.Logger interface
+// (Debug, Info, Warn and Error with key-value pairs), so it can be set as
+// client.Options.Logger without aloig depending on the SDK. The IDs the SDK
+// adds (WorkflowID, RunID, ActivityID, ...) are logged as snake_case fields
+// (workflow_id, workflow_run_id, activity_id, ...) and entries carry the
+// component field.
+type TemporalLogger struct {
+	logger Logger
+}
+
+// NewTemporalLogger returns a Temporal SDK logger writing to logger. A nil
+// logger writes to the singleton logger.
+func NewTemporalLogger(logger Logger) *TemporalLogger {
+	return &TemporalLogger{logger: logger}
+}
+
+// Debug logs msg at debug level with the key-value pairs
+func (l *TemporalLogger) Debug(msg string, keyvals ...interface{}) {
+	l.log(logrus.DebugLevel, msg, keyvals)
+}
+
+// Info logs msg at info level with the key-value pairs
+func (l *TemporalLogger) Info(msg string, keyvals ...interface{}) {
+	l.log(logrus.InfoLevel, msg, keyvals)
+}
+
+// Warn logs msg at warn level with the key-value pairs
+func (l *TemporalLogger) Warn(msg string, keyvals ...interface{}) {
+	l.log(logrus.WarnLevel, msg, keyvals)
+}
+
+// Error logs msg at error level with the key-value pairs
+func (l *TemporalLogger) Error(msg string, keyvals ...interface{}) {
+	l.log(logrus.ErrorLevel, msg, keyvals)
+}
+
+// log writes msg with the fields of keyvals
+func (l *TemporalLogger) log(level logrus.Level, msg string, keyvals []interface{}) {
+	logger := l.logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+	if !logger.IsLevelEnabled(level) {
+		return
+	}
+	logAtLevel(logger.WithFields(temporalFields(keyvals)), level, msg)
+}
+
+// temporalFields converts key-value pairs to fields. A key without value is
+// logged under "!BADKEY".
+func temporalFields(keyvals []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(keyvals)/2+1)
+	fields["component"] = TemporalComponent
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			fields["!BADKEY"] = keyvals[i]
+			break
+		}
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		if name, ok := temporalFieldNames[key]; ok {
+			key = name
+		}
+		fields[key] = keyvals[i+1]
+	}
+	return fields
+}
@@ -0,0 +1,42 @@
+package aloig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// temporalLogger mirrors the Temporal SDK's log.Logger interface
+type temporalLogger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+var _ temporalLogger = (*TemporalLogger)(nil)
+
+// TestTemporalLogger tests that Temporal logs are forwarded with aloig field names
+func TestTemporalLogger(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	temporal := NewTemporalLogger(logger)
+
+	temporal.Error("Activity error.", "Namespace", "default", "WorkflowID", "order-42", "RunID", "run-1",
+		"ActivityID", "5", "Attempt", 3, "Error", errors.New("card declined"), "Custom", "value", "dangling")
+	output := buf.String()
+	for _, expected := range []string{"level=error", `msg="Activity error."`, "component=temporal", "namespace=default",
+		"workflow_id=order-42", "workflow_run_id=run-1", "activity_id=5", "attempt=3", `error="card declined"`,
+		"Custom=value", "!BADKEY=dangling"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	temporal.Debug("Workflow task started", "WorkflowID", "order-42")
+	if buf.Len() != 0 {
+		t.Errorf("Expected disabled levels to be skipped, got: %s", buf.String())
+	}
+}
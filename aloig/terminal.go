@@ -0,0 +1,29 @@
+package aloig
+
+import (
+	"io"
+	"os"
+)
+
+// isTerminal reports whether out is a terminal rather than a file or pipe
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// textOutput reports whether the entries are written as text for a human
+// rather than as JSON: with ForceText, in the dev environment, or when the
+// output is a terminal. ForceJSON takes precedence.
+func textOutput(config Config, terminal bool) bool {
+	switch {
+	case config.ForceJSON:
+		return false
+	case config.ForceText:
+		return true
+	}
+	return config.Environment == "dev" || terminal
+}
@@ -0,0 +1,49 @@
+package aloig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTextOutput tests the choice between text and JSON output
+func TestTextOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		terminal bool
+		text     bool
+	}{
+		{"prod to a pipe", Config{Environment: "prod"}, false, false},
+		{"prod to a terminal", Config{Environment: "prod"}, true, true},
+		{"dev to a pipe", Config{Environment: "dev"}, false, true},
+		{"dev to a terminal", Config{Environment: "dev"}, true, true},
+		{"forced JSON", Config{Environment: "dev", ForceJSON: true}, true, false},
+		{"forced text", Config{Environment: "prod", ForceText: true}, false, true},
+		{"both forced", Config{Environment: "prod", ForceJSON: true, ForceText: true}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := textOutput(tt.config, tt.terminal); got != tt.text {
+				t.Errorf("Expected text output %v, got %v", tt.text, got)
+			}
+		})
+	}
+}
+
+// TestIsTerminal tests that files and buffers are not terminals
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer f.Close()
+
+	if isTerminal(f) {
+		t.Error("Expected a regular file not to be a terminal")
+	}
+	if isTerminal(&bytes.Buffer{}) {
+		t.Error("Expected a buffer not to be a terminal")
+	}
+}
@@ -0,0 +1,86 @@
+package aloig
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timer measures an operation and logs its outcome once it is done
+type Timer struct {
+	ctx    context.Context
+	logger Logger
+	name   string
+	start  time.Time
+	once   sync.Once
+}
+
+// StartTimer starts timing the named operation on the singleton logger.
+// Call Done or DoneErr when it ends to log its duration and outcome:
+//
+//	t := aloig.StartTimer(ctx, "import_users")
+//	defer func() { t.DoneErr(err) }()
+func StartTimer(ctx context.Context, name string) *Timer {
+	return NewTimer(ctx, nil, name)
+}
+
+// NewTimer starts timing the named operation on logger. A nil logger writes
+// to the singleton logger.
+func NewTimer(ctx context.Context, logger Logger, name string) *Timer {
+	return &Timer{ctx: ctx, logger: logger, name: name, start: time.Now()}
+}
+
+// Elapsed returns the time since the timer started
+func (t *Timer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// Done logs the successful completion of the operation
+func (t *Timer) Done() {
+	t.DoneErr(nil)
+}
+
+// DoneErr logs the completion of the operation, at error level with the
+// error when err is not nil. Only the first call of Done or DoneErr logs.
+func (t *Timer) DoneErr(err error) {
+	t.once.Do(func() {
+		logger := t.logger
+		if logger == nil {
+			logger = GetLogger()
+		}
+
+		entry := logger.WithFields(map[string]interface{}{
+			"operation":   t.name,
+			"duration_ms": float64(t.Elapsed()) / float64(time.Millisecond),
+			"success":     err == nil,
+		})
+		if err != nil {
+			entry.WithError(err).ErrorContext(t.ctx, t.name+" failed")
+			return
+		}
+		entry.InfoContext(t.ctx, t.name+" completed")
+	})
+}
+
+// WarnIfSlow runs fn and logs a warning with its duration and the context
+// fields on the singleton logger when it took longer than threshold, so slow
+// database calls, RPCs or batch jobs stand out without logging every call:
+//
+//	err := aloig.WarnIfSlow(ctx, "load_orders", 200*time.Millisecond, func(ctx context.Context) error {
+//		return db.QueryRowContext(ctx, query, id).Scan(&order)
+//	})
+//
+// It returns the error of fn, which it doesn't log.
+func WarnIfSlow(ctx context.Context, name string, threshold time.Duration, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	if elapsed := time.Since(start); elapsed > threshold {
+		GetLogger().WithFields(map[string]interface{}{
+			"operation":    name,
+			"duration_ms":  float64(elapsed) / float64(time.Millisecond),
+			"threshold_ms": float64(threshold) / float64(time.Millisecond),
+			"success":      err == nil,
+		}).WarnContext(ctx, name+" slow")
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package aloig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestTimer tests that timers log the duration and outcome once
+func TestTimer(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	ctx := WithTraceID(context.Background(), "trace-timer")
+
+	timer := NewTimer(ctx, logger, "import_users")
+	time.Sleep(2 * time.Millisecond)
+	timer.Done()
+	timer.DoneErr(errors.New("ignored"))
+
+	output := buf.String()
+	if strings.Count(output, "\n") != 1 {
+		t.Fatalf("Expected a single entry, got: %s", output)
+	}
+	for _, expected := range []string{"level=info", `msg="import_users completed"`, "operation=import_users", "success=true", "duration_ms=", "trace_id=trace-timer"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if timer.Elapsed() < 2*time.Millisecond {
+		t.Errorf("Expected at least 2ms elapsed, got %s", timer.Elapsed())
+	}
+
+	buf.Reset()
+	NewTimer(ctx, logger, "sync_orders").DoneErr(errors.New("upstream timeout"))
+	output = buf.String()
+	for _, expected := range []string{"level=error", `msg="sync_orders failed"`, "success=false", `error="upstream timeout"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
+
+// TestWarnIfSlow tests that only the calls over the threshold are logged,
+// with the error of fn returned
+func TestWarnIfSlow(t *testing.T) {
+	originalLog := log
+	defer func() { log = originalLog }()
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	log = logger
+	ctx := WithTraceID(context.Background(), "trace-slow")
+
+	err := WarnIfSlow(ctx, "load_orders", time.Hour, func(ctx context.Context) error {
+		return nil
+	})
+	if err != nil || buf.Len() != 0 {
+		t.Fatalf("Expected nothing logged under the threshold, got %v and: %s", err, buf.String())
+	}
+
+	failure := errors.New("connection reset")
+	err = WarnIfSlow(ctx, "load_orders", time.Millisecond, func(ctx context.Context) error {
+		if GetTraceID(ctx) != "trace-slow" {
+			t.Error("Expected fn to get the context")
+		}
+		time.Sleep(2 * time.Millisecond)
+		return failure
+	})
+	if err != failure {
+		t.Errorf("Expected the error of fn, got %v", err)
+	}
+	output := buf.String()
+	for _, expected := range []string{"level=warning", `msg="load_orders slow"`, "operation=load_orders", "threshold_ms=1", "success=false", "duration_ms=", "trace_id=trace-slow"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package aloig
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TimestampEpochMillis is a timestamp format writing entry times as the
+// number of milliseconds since the Unix epoch
+const TimestampEpochMillis = "epoch_millis"
+
+// formatTimestamp formats t with layout, which may be TimestampEpochMillis
+func formatTimestamp(t time.Time, layout string) string {
+	if layout == TimestampEpochMillis {
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return t.Format(layout)
+}
+
+// epochMillisFormatter returns a pooled copy of formatter (see
+// putJSONFormatter) that doesn't write the entry time, and sets the time
+// field of the entry to its epoch milliseconds so it is written as a number
+// instead
+func epochMillisFormatter(formatter *logrus.JSONFormatter, entry *logrus.Entry) *logrus.JSONFormatter {
+	timeKey := logrus.FieldKeyTime
+	if key, ok := formatter.FieldMap[logrus.FieldKeyTime]; ok {
+		timeKey = key
+	}
+
+	copied := getJSONFormatter(formatter)
+	// Move the time key out of the way so the field doesn't clash with it;
+	// it is never written since the timestamp is disabled
+	copied.FieldMap[logrus.FieldKeyTime] = "\x00" + timeKey
+	copied.DisableTimestamp = true
+
+	if value, ok := entry.Data[timeKey]; ok {
+		entry.Data["fields."+timeKey] = value
+	}
+	entry.Data[timeKey] = entry.Time.UnixMilli()
+	return copied
+}
+
+// utcHook converts entry times to UTC
+type utcHook struct{}
+
+// Levels returns the levels to which the hook will be applied
+func (utcHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire converts the entry time to UTC
+func (utcHook) Fire(entry *logrus.Entry) error {
+	entry.Time = entry.Time.UTC()
+	return nil
+}
@@ -0,0 +1,58 @@
+package aloig
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newTimestampTestLogger returns a logger for config writing to a buffer at a
+// fixed time in a UTC+2 time zone
+func newTimestampTestLogger(config Config) (*logrusLogger, *bytes.Buffer) {
+	config.Level = logrus.InfoLevel
+	config.Clock = NewManualClock(time.Date(2024, 1, 2, 5, 4, 5, 123456789, time.FixedZone("UTC+2", 2*3600)))
+	logger := NewLogger(config).(*logrusLogger)
+	var buf bytes.Buffer
+	logger.logger.SetOutput(&buf)
+	return logger, &buf
+}
+
+// TestTimestampFormat tests the configurable layout and time zone
+func TestTimestampFormat(t *testing.T) {
+	logger, buf := newTimestampTestLogger(Config{Environment: "prod", TimestampFormat: time.RFC3339Nano, TimestampUTC: true})
+	logger.Info("hello")
+	if !strings.Contains(buf.String(), `"time":"2024-01-02T03:04:05.123456789Z"`) {
+		t.Errorf("Expected an RFC3339Nano UTC time, got: %s", buf.String())
+	}
+
+	logger, buf = newTimestampTestLogger(Config{Environment: "prod"})
+	logger.Info("hello")
+	if !strings.Contains(buf.String(), `"time":"2024-01-02T05:04:05+02:00"`) {
+		t.Errorf("Expected an RFC3339 local time, got: %s", buf.String())
+	}
+
+	logger, buf = newTimestampTestLogger(Config{Environment: "dev", TimestampFormat: "2006-01-02 15:04:05", TimestampUTC: true})
+	logger.Info("hello")
+	if !strings.HasPrefix(buf.String(), "2024-01-02 03:04:05 ") && !strings.Contains(buf.String(), "2024-01-02 03:04:05\x1b") {
+		t.Errorf("Expected a custom UTC time in dev, got: %q", buf.String())
+	}
+}
+
+// TestTimestampEpochMillis tests epoch millisecond timestamps
+func TestTimestampEpochMillis(t *testing.T) {
+	logger, buf := newTimestampTestLogger(Config{Environment: "prod", TimestampFormat: TimestampEpochMillis})
+	logger.WithField("time", "user value").Info("hello")
+
+	output := buf.String()
+	if !strings.Contains(output, `"time":1704164645123`) || !strings.Contains(output, `"fields.time":"user value"`) {
+		t.Errorf("Expected an epoch millis time and the clashing field renamed, got: %s", output)
+	}
+
+	out, _ := (&PrettyFormatter{DisableColors: true, TimestampFormat: TimestampEpochMillis}).Format(&logrus.Entry{Time: time.UnixMilli(1704164645123), Message: "hello", Data: logrus.Fields{}})
+	if !strings.HasPrefix(string(out), "1704164645123 ") {
+		t.Errorf("Expected an epoch millis time, got: %q", out)
+	}
+}
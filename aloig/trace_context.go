@@ -1,11 +1,6 @@
 package aloig
 
-import (
-	"context"
-	"strings"
-
-	"github.com/google/uuid"
-)
+import "context"
 
 type contextKey string
 
@@ -23,21 +18,154 @@ const (
 	SessionIDKey contextKey = "session_id"
 )
 
+// contextFieldsKey is the key of the logging fields carried by contexts
+type contextFieldsKey struct{}
+
+// The indexes of the IDs in contextIDKeys and contextFields.ids
+const (
+	contextTraceID = iota
+	contextRequestID
+	contextUserID
+	contextSessionID
+	numContextIDs
+)
+
+// contextIDKeys are the keys of the IDs carried by contexts. They are
+// stored as interfaces so passing them to context.Value doesn't allocate.
+var contextIDKeys = [numContextIDs]interface{}{TraceIDKey, RequestIDKey, UserIDKey, SessionIDKey}
+
+// contextFields are the logging fields carried by a context. They are
+// immutable: the With functions store a modified copy, so the fields of the
+// contexts they return are read without a lookup per key.
+type contextFields struct {
+	// ids are the IDs set, by index in contextIDKeys, or nil. They are
+	// stored as interfaces so looking them up and logging them doesn't
+	// allocate.
+	ids [numContextIDs]interface{}
+
+	// fields are the fields added with ContextWithFields
+	fields map[string]interface{}
+}
+
+// noContextFields are the fields of contexts carrying none
+var noContextFields = &contextFields{}
+
+// fieldsContext is a context carrying logging fields. It also answers the
+// TraceIDKey, RequestIDKey, UserIDKey and SessionIDKey lookups, for the code
+// reading them directly.
+type fieldsContext struct {
+	context.Context
+	fields *contextFields
+}
+
+// Value returns the fields for contextFieldsKey and the matching ID for the
+// legacy keys, deferring to the parent otherwise
+func (c *fieldsContext) Value(key interface{}) interface{} {
+	if key == (contextFieldsKey{}) {
+		return c.fields
+	}
+	for i, idKey := range contextIDKeys {
+		if key == idKey && c.fields.ids[i] != nil {
+			return c.fields.ids[i]
+		}
+	}
+	return c.Context.Value(key)
+}
+
+// getContextFields returns the logging fields carried by ctx. The IDs stored
+// with context.WithValue and the exported keys are read too, and replace
+// those set before them with the With functions, so the last ID set wins.
+func getContextFields(ctx context.Context) *contextFields {
+	if ctx == nil {
+		return noContextFields
+	}
+	if c, ok := ctx.(*fieldsContext); ok {
+		return c.fields
+	}
+
+	fields, ok := ctx.Value(contextFieldsKey{}).(*contextFields)
+	if !ok {
+		fields = noContextFields
+	}
+	// The lookups of the legacy keys find the IDs of the fields unless
+	// others were stored after them. The copy is only made then.
+	legacy := fields
+	for i, key := range contextIDKeys {
+		id := contextIDValue(ctx.Value(key))
+		if id == legacy.ids[i] {
+			continue
+		}
+		if legacy == fields {
+			copied := *fields
+			legacy = &copied
+		}
+		legacy.ids[i] = id
+	}
+	return legacy
+}
+
+// contextIDValue returns value if it is a non-empty string, or nil
+func contextIDValue(value interface{}) interface{} {
+	if id, ok := value.(string); !ok || id == "" {
+		return nil
+	}
+	return value
+}
+
+// id returns the ID at index i, or an empty string
+func (f *contextFields) id(i int) string {
+	id, _ := f.ids[i].(string)
+	return id
+}
+
+// setIDAt sets the ID at index i, removing it when empty
+func (f *contextFields) setIDAt(i int, id string) {
+	if id == "" {
+		f.ids[i] = nil
+		return
+	}
+	f.ids[i] = id
+}
+
+// updateContextFields returns a context carrying a copy of the fields of ctx
+// modified by set
+func updateContextFields(ctx context.Context, set func(*contextFields)) context.Context {
+	fields := *getContextFields(ctx)
+	set(&fields)
+	return &fieldsContext{Context: ctx, fields: &fields}
+}
+
+// len returns the number of fields set
+func (f *contextFields) len() int {
+	n := len(f.fields)
+	for _, id := range f.ids {
+		if id != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// addTo adds the fields set to data
+func (f *contextFields) addTo(data map[string]interface{}) {
+	for k, v := range f.fields {
+		data[k] = v
+	}
+	for i, id := range f.ids {
+		if id != nil {
+			data[string(contextIDKeys[i].(contextKey))] = id
+		}
+	}
+}
+
 // WithTraceID returns a new context with the specified trace ID
 func WithTraceID(ctx context.Context, traceID string) context.Context {
-	return context.WithValue(ctx, TraceIDKey, traceID)
+	return updateContextFields(ctx, func(f *contextFields) { f.setIDAt(contextTraceID, traceID) })
 }
 
 // GetTraceID gets the trace ID from context
 func GetTraceID(ctx context.Context) string {
-	if ctx == nil {
-		return ""
-	}
-	traceID, ok := ctx.Value(TraceIDKey).(string)
-	if !ok || traceID == "" {
-		return ""
-	}
-	return traceID
+	return getContextFields(ctx).id(contextTraceID)
 }
 
 // EnsureTraceID ensures there's a trace ID in the context
@@ -56,81 +184,112 @@ func EnsureTraceID(ctx context.Context) (context.Context, string) {
 	return ctx, traceID
 }
 
-// GenerateTraceID generates a new random trace ID
-func GenerateTraceID() string {
-	return strings.ReplaceAll(uuid.New().String(), "-", "")
-}
-
 // WithRequestID returns a new context with the specified request ID
 func WithRequestID(ctx context.Context, requestID string) context.Context {
-	return context.WithValue(ctx, RequestIDKey, requestID)
+	return updateContextFields(ctx, func(f *contextFields) { f.setIDAt(contextRequestID, requestID) })
 }
 
 // GetRequestID gets the request ID from context
 func GetRequestID(ctx context.Context) string {
+	return getContextFields(ctx).id(contextRequestID)
+}
+
+// EnsureRequestID ensures there's a request ID in the context
+// If it doesn't exist, creates a new one with GenerateRequestID
+func EnsureRequestID(ctx context.Context) (context.Context, string) {
 	if ctx == nil {
-		return ""
+		ctx = context.Background()
 	}
-	requestID, ok := ctx.Value(RequestIDKey).(string)
-	if !ok || requestID == "" {
-		return ""
+
+	requestID := GetRequestID(ctx)
+	if requestID == "" {
+		requestID = GenerateRequestID()
+		ctx = WithRequestID(ctx, requestID)
 	}
-	return requestID
+
+	return ctx, requestID
 }
 
 // WithUserID returns a new context with the specified user ID
 func WithUserID(ctx context.Context, userID string) context.Context {
-	return context.WithValue(ctx, UserIDKey, userID)
+	return updateContextFields(ctx, func(f *contextFields) { f.setIDAt(contextUserID, userID) })
 }
 
 // GetUserID gets the user ID from context
 func GetUserID(ctx context.Context) string {
-	if ctx == nil {
-		return ""
-	}
-	userID, ok := ctx.Value(UserIDKey).(string)
-	if !ok || userID == "" {
-		return ""
-	}
-	return userID
+	return getContextFields(ctx).id(contextUserID)
 }
 
 // WithSessionID returns a new context with the specified session ID
 func WithSessionID(ctx context.Context, sessionID string) context.Context {
-	return context.WithValue(ctx, SessionIDKey, sessionID)
+	return updateContextFields(ctx, func(f *contextFields) { f.setIDAt(contextSessionID, sessionID) })
 }
 
 // GetSessionID gets the session ID from context
 func GetSessionID(ctx context.Context) string {
-	if ctx == nil {
-		return ""
-	}
-	sessionID, ok := ctx.Value(SessionIDKey).(string)
-	if !ok || sessionID == "" {
-		return ""
-	}
-	return sessionID
+	return getContextFields(ctx).id(contextSessionID)
 }
 
-// ExtractContextFields extracts all context fields into a map
-func ExtractContextFields(ctx context.Context) map[string]interface{} {
-	fields := make(map[string]interface{})
-
-	if traceID := GetTraceID(ctx); traceID != "" {
-		fields["trace_id"] = traceID
+// EnsureSessionID ensures there's a session ID in the context
+// If it doesn't exist, creates a new one with GenerateTraceID
+func EnsureSessionID(ctx context.Context) (context.Context, string) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	if requestID := GetRequestID(ctx); requestID != "" {
-		fields["request_id"] = requestID
+	sessionID := GetSessionID(ctx)
+	if sessionID == "" {
+		sessionID = GenerateTraceID()
+		ctx = WithSessionID(ctx, sessionID)
 	}
 
-	if userID := GetUserID(ctx); userID != "" {
-		fields["user_id"] = userID
-	}
+	return ctx, sessionID
+}
+
+// ContextWithFields returns a context carrying the given fields on top of
+// those of ctx, so every context log call made with it logs them:
+//
+//	ctx = aloig.ContextWithFields(ctx, map[string]interface{}{"order_id": order.ID})
+//	aloig.InfoContext(ctx, "Payment authorized") // order_id=...
+//
+// Fields added later replace those with the same name. The trace_id,
+// request_id, user_id and session_id string fields set the IDs returned by
+// GetTraceID and the other getters.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	return updateContextFields(ctx, func(f *contextFields) {
+		merged := make(map[string]interface{}, len(f.fields)+len(fields))
+		for k, v := range f.fields {
+			merged[k] = v
+		}
+		for k, v := range fields {
+			if id, ok := v.(string); ok && f.setID(k, id) {
+				continue
+			}
+			merged[k] = v
+		}
+		f.fields = merged
+	})
+}
 
-	if sessionID := GetSessionID(ctx); sessionID != "" {
-		fields["session_id"] = sessionID
+// setID sets the ID named name, returning false if there is none
+func (f *contextFields) setID(name, value string) bool {
+	for i, key := range contextIDKeys {
+		if string(key.(contextKey)) == name {
+			f.setIDAt(i, value)
+			return true
+		}
 	}
+	return false
+}
 
+// ExtractContextFields extracts all context fields into a map
+func ExtractContextFields(ctx context.Context) map[string]interface{} {
+	contextFields := getContextFields(ctx)
+	registry := registeredContextFields()
+	fields := make(map[string]interface{}, contextFields.len()+len(registry))
+	if ctx != nil {
+		addRegisteredContextFields(ctx, registry, fields)
+	}
+	contextFields.addTo(fields)
 	return fields
 }
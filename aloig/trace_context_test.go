@@ -2,8 +2,11 @@ package aloig
 
 import (
 	"context"
+	"io"
 	"strings"
 	"testing"
+
+	"github.com/sirupsen/logrus"
 )
 
 // TestWithTraceID tests that WithTraceID correctly adds trace ID to context
@@ -111,6 +114,38 @@ func TestEnsureTraceID(t *testing.T) {
 	}
 }
 
+// TestEnsureRequestIDAndSessionID tests that EnsureRequestID and
+// EnsureSessionID generate the missing IDs and keep the existing ones
+func TestEnsureRequestIDAndSessionID(t *testing.T) {
+	ensures := map[string]struct {
+		ensure func(context.Context) (context.Context, string)
+		with   func(context.Context, string) context.Context
+		get    func(context.Context) string
+	}{
+		"request": {EnsureRequestID, WithRequestID, GetRequestID},
+		"session": {EnsureSessionID, WithSessionID, GetSessionID},
+	}
+
+	for name, e := range ensures {
+		t.Run(name, func(t *testing.T) {
+			ctx, id := e.ensure(nil)
+			if id == "" || e.get(ctx) != id {
+				t.Errorf("Expected a generated ID in the context, got %q and %q", id, e.get(ctx))
+			}
+
+			_, other := e.ensure(context.Background())
+			if other == id {
+				t.Error("Expected distinct generated IDs")
+			}
+
+			ctx, id = e.ensure(e.with(context.Background(), "existing"))
+			if id != "existing" || e.get(ctx) != "existing" {
+				t.Errorf("Expected the existing ID to be kept, got %q", id)
+			}
+		})
+	}
+}
+
 // TestGenerateTraceID tests that GenerateTraceID generates valid trace IDs
 func TestGenerateTraceID(t *testing.T) {
 	// Generate multiple trace IDs to ensure they're different
@@ -403,3 +438,130 @@ func TestContextChaining(t *testing.T) {
 		t.Errorf("Expected %d fields after chaining, got %d", expectedCount, len(fields))
 	}
 }
+
+// TestContextLegacyKeys tests that the IDs stay readable with the exported
+// keys in both directions
+func TestContextLegacyKeys(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "request-456")
+	if value, _ := ctx.Value(RequestIDKey).(string); value != "request-456" {
+		t.Errorf("Expected the request ID under RequestIDKey, got %q", value)
+	}
+
+	legacy := context.WithValue(context.Background(), TraceIDKey, "trace-legacy")
+	if traceID := GetTraceID(legacy); traceID != "trace-legacy" {
+		t.Errorf("Expected the trace ID set with TraceIDKey, got %q", traceID)
+	}
+
+	ctx = WithUserID(legacy, "user-789")
+	fields := ExtractContextFields(ctx)
+	if fields["trace_id"] != "trace-legacy" || fields["user_id"] != "user-789" {
+		t.Errorf("Expected the legacy and new IDs, got %v", fields)
+	}
+	if value, _ := ctx.Value(TraceIDKey).(string); value != "trace-legacy" {
+		t.Errorf("Expected the parent value under TraceIDKey, got %q", value)
+	}
+
+	// A legacy value stored after the With functions replaces their ID
+	ctx = ContextWithFields(WithTraceID(context.Background(), "trace-a"), map[string]interface{}{"order_id": 42})
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, TraceIDKey, "trace-b"))
+	defer cancel()
+	if traceID := GetTraceID(ctx); traceID != "trace-b" {
+		t.Errorf("Expected the trace ID stored last, got %q", traceID)
+	}
+	fields = ExtractContextFields(WithUserID(ctx, "user-789"))
+	if fields["trace_id"] != "trace-b" || fields["user_id"] != "user-789" || fields["order_id"] != 42 {
+		t.Errorf("Expected the last trace ID with the other fields, got %v", fields)
+	}
+}
+
+// TestContextWithFields tests that context fields accumulate and are logged
+func TestContextWithFields(t *testing.T) {
+	parent := ContextWithFields(WithTraceID(context.Background(), "trace-123"), map[string]interface{}{"order_id": 42, "step": "start"})
+	ctx := ContextWithFields(parent, map[string]interface{}{"step": "charge", "request_id": "request-456"})
+
+	fields := ExtractContextFields(ctx)
+	expected := map[string]interface{}{"trace_id": "trace-123", "request_id": "request-456", "order_id": 42, "step": "charge"}
+	if len(fields) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, fields)
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, fields[k])
+		}
+	}
+	if GetRequestID(ctx) != "request-456" {
+		t.Errorf("Expected request_id to set the request ID, got %q", GetRequestID(ctx))
+	}
+	if step := ExtractContextFields(parent)["step"]; step != "start" {
+		t.Errorf("Expected the parent context to be unchanged, got step=%v", step)
+	}
+
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.InfoContext(ctx, "Payment authorized")
+	for _, expected := range []string{"order_id=42", "step=charge", "trace_id=trace-123", "request_id=request-456"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in the output, got: %s", expected, buf.String())
+		}
+	}
+}
+
+// newBenchmarkContext returns a context carrying the four IDs
+func newBenchmarkContext() context.Context {
+	ctx := WithTraceID(context.Background(), "trace-123")
+	ctx = WithRequestID(ctx, "request-456")
+	ctx = WithUserID(ctx, "user-789")
+	return WithSessionID(ctx, "session-abc")
+}
+
+// BenchmarkExtractContextFields measures the cost of reading the IDs
+func BenchmarkExtractContextFields(b *testing.B) {
+	ctx := newBenchmarkContext()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractContextFields(ctx)
+	}
+}
+
+// newWrappedBenchmarkContext returns the context of newBenchmarkContext
+// wrapped like in an HTTP handler
+func newWrappedBenchmarkContext(b *testing.B) context.Context {
+	ctx, cancel := context.WithCancel(newBenchmarkContext())
+	b.Cleanup(cancel)
+	return context.WithValue(ctx, benchmarkKey{}, "value")
+}
+
+// benchmarkKey is the key of the value of newWrappedBenchmarkContext
+type benchmarkKey struct{}
+
+// BenchmarkExtractContextFieldsWrapped measures the cost of reading the IDs
+// of a wrapped context
+func BenchmarkExtractContextFieldsWrapped(b *testing.B) {
+	ctx := newWrappedBenchmarkContext(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractContextFields(ctx)
+	}
+}
+
+// BenchmarkInfoContext measures the cost of a context log call
+func BenchmarkInfoContext(b *testing.B) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetOutput(io.Discard)
+	ctx := newBenchmarkContext()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.InfoContext(ctx, "message")
+	}
+}
+
+// BenchmarkInfoContextWrapped measures the cost of a context log call with
+// a wrapped context
+func BenchmarkInfoContextWrapped(b *testing.B) {
+	logger, _ := newBufferLogger(logrus.InfoLevel)
+	logger.logger.SetOutput(io.Discard)
+	ctx := newWrappedBenchmarkContext(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.InfoContext(ctx, "message")
+	}
+}
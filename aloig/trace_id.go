@@ -0,0 +1,198 @@
+package aloig
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TraceIDFormat is the format of the IDs generated by GenerateTraceID and
+// GenerateRequestID
+type TraceIDFormat int
+
+const (
+	// TraceIDUUID generates random UUIDs without dashes (32 hex characters)
+	TraceIDUUID TraceIDFormat = iota
+
+	// TraceIDULID generates ULIDs (26 Crockford base32 characters), which
+	// start with the generation time in milliseconds, so the IDs sort
+	// chronologically in log stores
+	TraceIDULID
+
+	// TraceIDW3C generates 16 random bytes as 32 lowercase hex characters,
+	// the trace IDs of W3C Trace Context, so the IDs can be used directly in
+	// traceparent headers
+	TraceIDW3C
+)
+
+// traceIDFormatNames are the names of the trace ID formats
+var traceIDFormatNames = []string{"uuid", "ulid", "w3c"}
+
+// String returns the name of the format
+func (f TraceIDFormat) String() string {
+	if f < 0 || int(f) >= len(traceIDFormatNames) {
+		return fmt.Sprintf("TraceIDFormat(%d)", int(f))
+	}
+	return traceIDFormatNames[f]
+}
+
+// ParseTraceIDFormat returns the format with the given name ("uuid", "ulid"
+// or "w3c")
+func ParseTraceIDFormat(name string) (TraceIDFormat, error) {
+	for i, format := range traceIDFormatNames {
+		if strings.EqualFold(name, format) {
+			return TraceIDFormat(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown trace ID format %q", name)
+}
+
+// traceIDFormat is the format of the generated trace IDs, see
+// SetTraceIDFormat
+var traceIDFormat int32
+
+// SetTraceIDFormat sets the format of the IDs generated by GenerateTraceID
+// and GenerateRequestID. NewLogger sets it to Config.TraceIDFormat unless it
+// is TraceIDUUID, the zero value.
+func SetTraceIDFormat(format TraceIDFormat) {
+	atomic.StoreInt32(&traceIDFormat, int32(format))
+}
+
+// IDGenerator generates the trace and request IDs, e.g. Snowflake IDs or IDs
+// prefixed with the region. It must be safe for concurrent use.
+type IDGenerator interface {
+	// TraceID returns a new trace ID
+	TraceID() string
+
+	// RequestID returns a new request ID
+	RequestID() string
+}
+
+var (
+	idGeneratorMu sync.RWMutex
+	idGenerator   IDGenerator
+)
+
+// SetIDGenerator sets the generator of the IDs returned by GenerateTraceID
+// and GenerateRequestID; nil generates them in the format set with
+// SetTraceIDFormat. NewLogger sets it to Config.IDGenerator when set.
+func SetIDGenerator(generator IDGenerator) {
+	idGeneratorMu.Lock()
+	defer idGeneratorMu.Unlock()
+	idGenerator = generator
+}
+
+// currentIDGenerator returns the generator set with SetIDGenerator, if any
+func currentIDGenerator() IDGenerator {
+	idGeneratorMu.RLock()
+	defer idGeneratorMu.RUnlock()
+	return idGenerator
+}
+
+// GenerateTraceID generates a new trace ID with the generator set with
+// SetIDGenerator, or a random one in the format set with SetTraceIDFormat
+func GenerateTraceID() string {
+	if generator := currentIDGenerator(); generator != nil {
+		return generator.TraceID()
+	}
+	return generateID()
+}
+
+// GenerateRequestID generates a new request ID with the generator set with
+// SetIDGenerator, or a random one in the format set with SetTraceIDFormat
+func GenerateRequestID() string {
+	if generator := currentIDGenerator(); generator != nil {
+		return generator.RequestID()
+	}
+	return generateID()
+}
+
+// generateID returns a random ID in the format set with SetTraceIDFormat
+func generateID() string {
+	switch TraceIDFormat(atomic.LoadInt32(&traceIDFormat)) {
+	case TraceIDULID:
+		return newULID(time.Now())
+	case TraceIDW3C:
+		return randomHexID(16)
+	}
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+// GenerateSpanID generates a new random span ID: 8 bytes as 16 lowercase
+// hex characters, the parent IDs of W3C Trace Context
+func GenerateSpanID() string {
+	return randomHexID(8)
+}
+
+// IsValidTraceID checks if id is a valid W3C trace ID: 32 lowercase hex
+// characters, not all zeros. The UUID and W3C formats of GenerateTraceID
+// produce valid IDs; ULIDs are not.
+func IsValidTraceID(id string) bool {
+	return isValidHexID(id, 32)
+}
+
+// randomHexID returns n random bytes, not all zeros, as lowercase hex
+func randomHexID(n int) string {
+	id := make([]byte, n)
+	for {
+		if _, err := rand.Read(id); err != nil {
+			// Like uuid.New, which the default format relies on
+			panic(err)
+		}
+		for _, b := range id {
+			if b != 0 {
+				return hex.EncodeToString(id)
+			}
+		}
+	}
+}
+
+// isValidHexID checks if id is made of size lowercase hex characters, not
+// all zeros, as the IDs of W3C Trace Context
+func isValidHexID(id string, size int) bool {
+	if len(id) != size {
+		return false
+	}
+	zero := true
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+		zero = zero && c == '0'
+	}
+	return !zero
+}
+
+// crockfordAlphabet is the base32 alphabet of ULIDs
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID made of the milliseconds since the Unix epoch at t
+// (48 bits) followed by 80 random bits
+func newULID(t time.Time) string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(t.UnixNano()/int64(time.Millisecond))<<16)
+	if _, err := rand.Read(id[6:]); err != nil {
+		// Like uuid.New, which the default format relies on
+		panic(err)
+	}
+
+	// The 128 bits are written as 26 characters of 5 bits, the first one
+	// holding the 3 leading bits
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var encoded [26]byte
+	for i := len(encoded) - 1; i >= 0; i-- {
+		encoded[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(encoded[:])
+}
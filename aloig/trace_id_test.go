@@ -0,0 +1,152 @@
+package aloig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseTraceIDFormat tests parsing the format names
+func TestParseTraceIDFormat(t *testing.T) {
+	for _, format := range []TraceIDFormat{TraceIDUUID, TraceIDULID, TraceIDW3C} {
+		parsed, err := ParseTraceIDFormat(strings.ToUpper(format.String()))
+		if err != nil || parsed != format {
+			t.Errorf("Expected %s, got %v (%v)", format, parsed, err)
+		}
+	}
+	if _, err := ParseTraceIDFormat("snowflake"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+// TestGenerateTraceIDULID tests that the ULID trace IDs are valid and sort
+// chronologically
+func TestGenerateTraceIDULID(t *testing.T) {
+	SetTraceIDFormat(TraceIDULID)
+	defer SetTraceIDFormat(TraceIDUUID)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, GenerateTraceID())
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	for _, id := range ids {
+		if len(id) != 26 {
+			t.Errorf("Expected 26 characters, got %q", id)
+		}
+		if strings.Trim(id, crockfordAlphabet) != "" {
+			t.Errorf("Expected Crockford base32 characters, got %q", id)
+		}
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Errorf("Expected the IDs to sort chronologically, got %v", ids)
+	}
+
+	// A logger without format keeps the one set before
+	NewLogger(Config{Environment: "prod"})
+	if id := GenerateTraceID(); len(id) != 26 {
+		t.Errorf("Expected the ULID format to be kept, got %q", id)
+	}
+}
+
+// TestNewULID tests the encoding of the timestamp of a ULID
+func TestNewULID(t *testing.T) {
+	// The example of the ULID specification: 1469918176385 ms
+	id := newULID(time.Unix(0, 1469918176385*int64(time.Millisecond)))
+	if !strings.HasPrefix(id, "01ARYZ6S41") {
+		t.Errorf("Expected the timestamp 01ARYZ6S41, got %q", id)
+	}
+	if newULID(time.Unix(0, 0))[:10] != "0000000000" {
+		t.Error("Expected a zero timestamp for the Unix epoch")
+	}
+}
+
+// TestGenerateTraceIDW3C tests that the W3C trace IDs and the span IDs are
+// valid
+func TestGenerateTraceIDW3C(t *testing.T) {
+	SetTraceIDFormat(TraceIDW3C)
+	defer SetTraceIDFormat(TraceIDUUID)
+
+	traceID := GenerateTraceID()
+	if !IsValidTraceID(traceID) {
+		t.Errorf("Expected a valid trace ID, got %q", traceID)
+	}
+	if traceID == GenerateTraceID() {
+		t.Error("Expected distinct trace IDs")
+	}
+
+	spanID := GenerateSpanID()
+	if !isValidHexID(spanID, 16) {
+		t.Errorf("Expected 16 lowercase hex characters, got %q", spanID)
+	}
+}
+
+// TestIsValidTraceID tests the validation of W3C trace IDs
+func TestIsValidTraceID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{"4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"00000000000000000000000000000000", false},
+		{"4BF92F3577B34DA6A3CE929D0E0E4736", false},
+		{"4bf92f3577b34da6a3ce929d0e0e473", false},
+		{"4bf92f3577b34da6a3ce929d0e0e4736a", false},
+		{"4bf92f3577b34da6a3ce929d0e0e473g", false},
+		{"01ARYZ6S41TSV4RRFFQ69G5FAV", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if valid := IsValidTraceID(tt.id); valid != tt.valid {
+			t.Errorf("IsValidTraceID(%q) = %v, expected %v", tt.id, valid, tt.valid)
+		}
+	}
+
+	if id := GenerateTraceID(); !IsValidTraceID(id) {
+		t.Errorf("Expected the default trace IDs to be valid, got %q", id)
+	}
+}
+
+// prefixIDGenerator generates IDs prefixed with a region and a kind
+type prefixIDGenerator struct {
+	region string
+	next   uint64
+}
+
+func (g *prefixIDGenerator) TraceID() string {
+	return fmt.Sprintf("%s-t-%d", g.region, atomic.AddUint64(&g.next, 1))
+}
+
+func (g *prefixIDGenerator) RequestID() string {
+	return fmt.Sprintf("%s-r-%d", g.region, atomic.AddUint64(&g.next, 1))
+}
+
+// TestIDGenerator tests that the IDs are generated by the configured
+// generator
+func TestIDGenerator(t *testing.T) {
+	NewLogger(Config{Environment: "prod", IDGenerator: &prefixIDGenerator{region: "eu"}})
+	defer SetIDGenerator(nil)
+
+	if id := GenerateTraceID(); id != "eu-t-1" {
+		t.Errorf("Expected eu-t-1, got %q", id)
+	}
+	if _, id := EnsureRequestID(context.Background()); id != "eu-r-2" {
+		t.Errorf("Expected eu-r-2, got %q", id)
+	}
+
+	// A logger without generator keeps the one set before
+	NewLogger(Config{Environment: "prod"})
+	if id := GenerateTraceID(); id != "eu-t-3" {
+		t.Errorf("Expected the generator to be kept, got %q", id)
+	}
+
+	SetIDGenerator(nil)
+	if id := GenerateRequestID(); !IsValidTraceID(id) {
+		t.Errorf("Expected a random ID without generator, got %q", id)
+	}
+}
@@ -0,0 +1,64 @@
+package aloig
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxLogValuerDepth bounds the resolution of LogValuers returning LogValuers
+const maxLogValuerDepth = 100
+
+// LogValuer is implemented by types that control how they are logged, for
+// example to redact themselves or to defer an expensive rendering. LogValue
+// is only called when the entry is actually emitted, never for entries below
+// the logger level.
+//
+//	type Card struct{ Number string }
+//
+//	func (c Card) LogValue() interface{} {
+//		return "****" + c.Number[len(c.Number)-4:]
+//	}
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// LogValuerHook is a hook that replaces the LogValuer fields of emitted
+// entries with their LogValue. NewLogger adds it before any other hook so
+// outputs, Sentry and syslog only see resolved values.
+type LogValuerHook struct{}
+
+// Levels returns the levels to which the hook will be applied
+func (hook *LogValuerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire resolves the LogValuer fields of the entry
+func (hook *LogValuerHook) Fire(entry *logrus.Entry) error {
+	for key, value := range entry.Data {
+		if valuer, ok := value.(LogValuer); ok {
+			entry.Data[key] = resolveLogValue(valuer)
+		}
+	}
+	return nil
+}
+
+// resolveLogValue calls LogValue until the value is not a LogValuer anymore.
+// A panicking LogValue is reported in the value instead of crashing.
+func resolveLogValue(valuer LogValuer) (value interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			value = fmt.Sprintf("!PANIC: LogValue: %v", r)
+		}
+	}()
+
+	value = valuer
+	for i := 0; i < maxLogValuerDepth; i++ {
+		v, ok := value.(LogValuer)
+		if !ok {
+			return value
+		}
+		value = v.LogValue()
+	}
+	return fmt.Sprintf("!ERROR: LogValue exceeded %d levels", maxLogValuerDepth)
+}
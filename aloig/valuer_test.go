@@ -0,0 +1,59 @@
+package aloig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// testCard redacts its number when logged
+type testCard struct {
+	number string
+	calls  *int
+}
+
+func (c testCard) LogValue() interface{} {
+	*c.calls++
+	return "****" + c.number[len(c.number)-4:]
+}
+
+// nestedValuer returns another LogValuer
+type nestedValuer struct{}
+
+func (nestedValuer) LogValue() interface{} {
+	return testCard{number: "4111111111111111", calls: new(int)}
+}
+
+// panickingValuer panics when logged
+type panickingValuer struct{}
+
+func (panickingValuer) LogValue() interface{} { panic("broken") }
+
+// TestLogValuerHook tests that LogValuer fields are resolved only for emitted entries
+func TestLogValuerHook(t *testing.T) {
+	logger, buf := newBufferLogger(logrus.InfoLevel)
+	logger.logger.AddHook(&LogValuerHook{})
+
+	calls := 0
+	card := testCard{number: "4111111111111111", calls: &calls}
+
+	logger.WithField("card", card).Debug("filtered")
+	if calls != 0 {
+		t.Errorf("Expected LogValue not to be called for a disabled level, got %d calls", calls)
+	}
+
+	logger.With(Any("card", card), Any("nested", nestedValuer{}), Any("broken", panickingValuer{})).Info("payment")
+	output := buf.String()
+	if calls != 1 {
+		t.Errorf("Expected LogValue to be called once, got %d calls", calls)
+	}
+	for _, expected := range []string{`card="****1111"`, `nested="****1111"`, `broken="!PANIC: LogValue: broken"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "4111111111111111") {
+		t.Errorf("Expected the card number to be redacted, got: %s", output)
+	}
+}
@@ -0,0 +1,135 @@
+// Command aloig-pretty renders logs in the aloig production JSON format
+// human-readably, for tailing production-format logs locally:
+//
+//	kubectl logs -f deploy/orders | aloig-pretty -level warn
+//	aloig-pretty -trace 4bf92f35 < service.log
+//
+// Lines that are not JSON entries are printed as they are.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	aloig "github.com/aloi-tech/aloig_go/aloig"
+	"github.com/sirupsen/logrus"
+)
+
+// collapsedFields are the fields added to every entry by NewLogger, hidden
+// unless -all is set
+var collapsedFields = []string{"env", "appname", "hostname", "servername", "release", "function", "full_function", "file", "line"}
+
+// options are the command line options
+type options struct {
+	level   logrus.Level
+	traceID string
+	all     bool
+	noColor bool
+}
+
+func main() {
+	var opts options
+	level := flag.String("level", "trace", "minimum level of the entries shown")
+	flag.StringVar(&opts.traceID, "trace", "", "only show entries with this trace_id")
+	flag.BoolVar(&opts.all, "all", false, "show the fields added to every entry")
+	flag.BoolVar(&opts.noColor, "no-color", false, "disable colors")
+	flag.Parse()
+
+	parsed, err := logrus.ParseLevel(*level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	opts.level = parsed
+
+	if err := run(os.Stdin, os.Stdout, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run renders the entries read from in to out
+func run(in io.Reader, out io.Writer, opts options) error {
+	formatter := &aloig.PrettyFormatter{DisableColors: opts.noColor}
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		entry, ok := parseEntry(line)
+		if !ok {
+			w.Write(line)
+			w.WriteByte('\n')
+			continue
+		}
+		if entry.Level > opts.level {
+			continue
+		}
+		if opts.traceID != "" && fmt.Sprint(entry.Data[string(aloig.TraceIDKey)]) != opts.traceID {
+			continue
+		}
+		if !opts.all {
+			for _, key := range collapsedFields {
+				delete(entry.Data, key)
+			}
+		}
+
+		formatted, err := formatter.Format(entry)
+		if err != nil {
+			return err
+		}
+		w.Write(formatted)
+	}
+	return scanner.Err()
+}
+
+// parseEntry converts a JSON line into an entry
+func parseEntry(line []byte) (*logrus.Entry, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, false
+	}
+
+	entry := &logrus.Entry{Data: logrus.Fields{}, Level: logrus.InfoLevel}
+	if msg, ok := data[logrus.FieldKeyMsg].(string); ok {
+		entry.Message = msg
+		delete(data, logrus.FieldKeyMsg)
+	}
+	if ts, ok := data[logrus.FieldKeyTime].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			entry.Time = t
+		}
+		delete(data, logrus.FieldKeyTime)
+	}
+	if level, ok := data[logrus.FieldKeyLevel].(string); ok {
+		delete(data, logrus.FieldKeyLevel)
+		if base, ok := data[aloig.BaseLevelField].(string); ok {
+			// Custom level entries carry their logrus level separately
+			delete(data, aloig.BaseLevelField)
+			data[aloig.LevelNameField] = level
+			level = base
+		}
+		if parsed, err := logrus.ParseLevel(level); err == nil {
+			entry.Level = parsed
+		}
+	}
+	for k, v := range data {
+		entry.Data[k] = v
+	}
+	return entry, true
+}
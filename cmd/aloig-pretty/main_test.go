@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testInput = `{"level":"info","msg":"started","time":"2024-01-02T15:04:05.123Z","hostname":"web-1","trace_id":"t1"}
+not json
+{"level":"error","msg":"payment failed","time":"2024-01-02T15:04:06Z","caller":"pay.go:12","error":"card declined","trace_id":"t2","amount":1999}
+{"level":"critical","base_level":"error","msg":"disk full","trace_id":"t2"}
+`
+
+// TestRun tests rendering, level and trace filtering and collapsed fields
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	if err := run(strings.NewReader(testInput), &out, options{level: logrus.TraceLevel, noColor: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := out.String()
+	for _, expected := range []string{"15:04:05.123 INFO  [t1] started", "not json\n", "ERROR [t2] payment failed", "pay.go:12 amount=1999", "    error:\n        card declined", "CRITICAL [t2] disk full"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in output, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "web-1") || strings.Contains(output, "base_level") {
+		t.Errorf("Expected collapsed fields, got:\n%s", output)
+	}
+
+	out.Reset()
+	if err := run(strings.NewReader(testInput), &out, options{level: logrus.WarnLevel, traceID: "t2", all: true, noColor: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output = out.String()
+	if strings.Contains(output, "started") || !strings.Contains(output, "payment failed") || !strings.Contains(output, "disk full") {
+		t.Errorf("Expected only the t2 error entries, got:\n%s", output)
+	}
+}